@@ -0,0 +1,211 @@
+package talkative
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrConversationForbidden is returned when a principal is not allowed to modify a conversation.
+var ErrConversationForbidden = newError("conversation access denied")
+
+// Define an enum-like type to represent the access granted to a principal on a conversation.
+type Access string
+
+const (
+	// The principal can load and list the conversation.
+	ACCESS_READ Access = "read"
+
+	// The principal can also save the conversation, i.e: append messages.
+	ACCESS_WRITE Access = "write"
+)
+
+// PRINCIPAL_ANYONE is the principal granting access to everyone in an ACL.
+const PRINCIPAL_ANYONE = "*"
+
+// Allows reports whether the principal has the access on the conversation.
+//
+// The owner has every access, only the owner can change the ACL or delete the conversation.
+// Conversations without owner are not restricted.
+func (c *Conversation) Allows(principal string, access Access) bool {
+	if c.Owner == "" || c.Owner == principal {
+		return true
+	}
+
+	for _, granted := range []Access{c.ACL[principal], c.ACL[PRINCIPAL_ANYONE]} {
+		if granted == ACCESS_WRITE || (granted == ACCESS_READ && access == ACCESS_READ) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ACLConversationStore wraps a ConversationStore and enforces the ownership and ACL of the conversations,
+// so multi-user backends don't need to track who can access which conversation themselves.
+//
+// Use For to obtain the view of the store of a principal.
+type ACLConversationStore struct {
+	store ConversationStore
+	locks conversationLocks // Serialises the checks and writes of every conversation across principals.
+}
+
+// NewACLConversationStore creates a new store enforcing the access control of the conversations of the underlying store.
+func NewACLConversationStore(store ConversationStore) *ACLConversationStore {
+	return &ACLConversationStore{store: store}
+}
+
+// For returns the ConversationStore of the principal, i.e: the ID of the authenticated user.
+//
+// Conversations the principal cannot read are hidden from List and reported as ErrConversationNotFound
+// by Load, so their existence is not disclosed. Conversations the principal saves first are owned by them.
+func (s *ACLConversationStore) For(principal string) ConversationStore {
+	return &principalStore{store: s.store, locks: &s.locks, principal: principal}
+}
+
+// conversationLocks holds a mutex per conversation ID, for as long as it is locked.
+type conversationLocks struct {
+	mu    sync.Mutex
+	locks map[string]*conversationLock
+}
+
+// conversationLock is the mutex of a conversation, with the number of goroutines holding or waiting for it.
+type conversationLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock locks the conversation with the given ID and returns the function unlocking it.
+func (l *conversationLocks) lock(id string) func() {
+	l.mu.Lock()
+
+	if l.locks == nil {
+		l.locks = map[string]*conversationLock{}
+	}
+
+	lock, ok := l.locks[id]
+
+	if !ok {
+		lock = &conversationLock{}
+		l.locks[id] = lock
+	}
+
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		l.mu.Lock()
+
+		if lock.refs--; lock.refs == 0 {
+			delete(l.locks, id)
+		}
+
+		l.mu.Unlock()
+	}
+}
+
+// principalStore is the view of an ACLConversationStore of a principal.
+type principalStore struct {
+	store     ConversationStore
+	locks     *conversationLocks
+	principal string
+}
+
+// Save saves the conversation when the principal can write it, assigning new conversations to the principal.
+//
+// The owner and ACL of existing conversations are kept unless the principal is the owner. Saves and deletions
+// of the same conversation are serialised, so the access is checked against the conversation being replaced.
+func (s *principalStore) Save(conversation *Conversation) error {
+	defer s.locks.lock(conversation.ID)()
+
+	existing, err := s.store.Load(conversation.ID)
+
+	if errors.Is(err, ErrConversationNotFound) {
+		if conversation.Owner != "" && conversation.Owner != s.principal {
+			return ErrConversationForbidden
+		}
+
+		saved := conversation.clone()
+		saved.Owner = s.principal
+
+		return s.store.Save(saved)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !existing.Allows(s.principal, ACCESS_WRITE) {
+		return ErrConversationForbidden
+	}
+
+	saved := conversation.clone()
+
+	if existing.Owner != "" && existing.Owner != s.principal {
+		saved.Owner, saved.ACL = existing.Owner, existing.ACL
+	}
+
+	return s.store.Save(saved)
+}
+
+// Load returns the conversation when the principal can read it.
+func (s *principalStore) Load(id string) (*Conversation, error) {
+	conversation, err := s.store.Load(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !conversation.Allows(s.principal, ACCESS_READ) {
+		return nil, ErrConversationNotFound
+	}
+
+	return conversation, nil
+}
+
+// List returns the conversations the principal can read, ordered by creation time.
+func (s *principalStore) List() ([]*Conversation, error) {
+	conversations, err := s.store.List()
+
+	if err != nil {
+		return nil, err
+	}
+
+	readable := make([]*Conversation, 0, len(conversations))
+
+	for _, conversation := range conversations {
+		if conversation.Allows(s.principal, ACCESS_READ) {
+			readable = append(readable, conversation)
+		}
+	}
+
+	return readable, nil
+}
+
+// Delete removes the conversation when the principal owns it.
+func (s *principalStore) Delete(id string) error {
+	defer s.locks.lock(id)()
+
+	conversation, err := s.store.Load(id)
+
+	if errors.Is(err, ErrConversationNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if conversation.Owner != "" && conversation.Owner != s.principal {
+		if conversation.Allows(s.principal, ACCESS_READ) {
+			return ErrConversationForbidden
+		}
+
+		return nil
+	}
+
+	return s.store.Delete(id)
+}
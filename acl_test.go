@@ -0,0 +1,119 @@
+package talkative_test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestACLConversationStore tests enforcing the ownership and ACL of conversations per principal.
+func TestACLConversationStore(t *testing.T) {
+	store := talkative.NewACLConversationStore(talkative.NewMemoryConversationStore())
+	alice, bob, carol := store.For("alice"), store.For("bob"), store.For("carol")
+
+	assert.NoError(t, alice.Save(&talkative.Conversation{ID: "1", Model: "llama2"}))
+	assert.NoError(t, bob.Save(&talkative.Conversation{ID: "2", Model: "llama2"}))
+	assert.ErrorIs(t, carol.Save(&talkative.Conversation{ID: "3", Owner: "alice"}), talkative.ErrConversationForbidden)
+
+	conversation, err := alice.Load("1")
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", conversation.Owner)
+	}
+
+	_, err = bob.Load("1")
+	assert.ErrorIs(t, err, talkative.ErrConversationNotFound)
+
+	list, _ := alice.List()
+	assert.Len(t, list, 1)
+
+	// Sharing the conversation with bob for reading and carol for writing.
+	conversation.ACL = map[string]talkative.Access{"bob": talkative.ACCESS_READ, "carol": talkative.ACCESS_WRITE}
+	assert.NoError(t, alice.Save(conversation))
+
+	list, _ = bob.List()
+	assert.Len(t, list, 2)
+
+	shared, err := bob.Load("1")
+	assert.NoError(t, err)
+
+	shared.Append(talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	assert.ErrorIs(t, bob.Save(shared), talkative.ErrConversationForbidden)
+	assert.ErrorIs(t, bob.Delete("1"), talkative.ErrConversationForbidden)
+
+	// Writers can append messages but not take over the conversation.
+	shared.Owner, shared.ACL = "carol", nil
+	assert.NoError(t, carol.Save(shared))
+
+	conversation, _ = alice.Load("1")
+	assert.Len(t, conversation.Messages, 1)
+	assert.Equal(t, "alice", conversation.Owner)
+	assert.Equal(t, talkative.ACCESS_READ, conversation.ACL["bob"])
+
+	// Deleting unreadable conversations is a no-op, the owner deletes them.
+	assert.NoError(t, carol.Delete("2"))
+	_, err = bob.Load("2")
+	assert.NoError(t, err)
+
+	assert.NoError(t, alice.Delete("1"))
+	_, err = alice.Load("1")
+	assert.ErrorIs(t, err, talkative.ErrConversationNotFound)
+}
+
+// TestACLConversationStoreConcurrentSave tests that a new conversation saved concurrently by several principals
+// is owned by exactly one of them.
+func TestACLConversationStoreConcurrentSave(t *testing.T) {
+	store := talkative.NewACLConversationStore(slowConversationStore{talkative.NewMemoryConversationStore()})
+
+	var (
+		wg    sync.WaitGroup
+		saved atomic.Int32
+	)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func(principal string) {
+			defer wg.Done()
+
+			if err := store.For(principal).Save(&talkative.Conversation{ID: "1"}); err == nil {
+				saved.Add(1)
+			} else {
+				assert.ErrorIs(t, err, talkative.ErrConversationForbidden)
+			}
+		}(fmt.Sprint("user", i))
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), saved.Load())
+}
+
+// slowConversationStore is a ConversationStore taking its time to save conversations, widening race windows.
+type slowConversationStore struct {
+	talkative.ConversationStore
+}
+
+func (s slowConversationStore) Save(conversation *talkative.Conversation) error {
+	time.Sleep(time.Millisecond)
+
+	return s.ConversationStore.Save(conversation)
+}
+
+// TestConversationAllows tests resolving the access of principals on conversations.
+func TestConversationAllows(t *testing.T) {
+	public := &talkative.Conversation{Owner: "alice", ACL: map[string]talkative.Access{talkative.PRINCIPAL_ANYONE: talkative.ACCESS_READ}}
+
+	assert.True(t, public.Allows("alice", talkative.ACCESS_WRITE))
+	assert.True(t, public.Allows("bob", talkative.ACCESS_READ))
+	assert.False(t, public.Allows("bob", talkative.ACCESS_WRITE))
+
+	unowned := &talkative.Conversation{}
+	assert.True(t, unowned.Allows("bob", talkative.ACCESS_WRITE))
+}
@@ -0,0 +1,92 @@
+package talkative
+
+import (
+	"context"
+	"errors"
+)
+
+// Pin returns the client of the endpoint the conversation is pinned to, so its turns benefit from the
+// prompt cache of a single endpoint.
+//
+// A conversation is pinned on its first turn to the endpoint its ID is assigned to, the endpoint being
+// recorded in Conversation.Endpoint. It is pinned again when its endpoint is no longer part of the
+// shards or is not ready. Pin returns nil when there is no endpoint.
+func (s *Shards) Pin(conversation *Conversation) *Client {
+	if client := s.pinned(conversation); client != nil {
+		return client
+	}
+
+	client := s.Pick(conversation.ID)
+
+	if client != nil {
+		conversation.Endpoint = client.base
+	}
+
+	return client
+}
+
+// ChatConversation sends the messages following the conversation to its pinned endpoint, see Pin, and
+// appends them along with the aggregated reply to the conversation.
+//
+// When the pinned endpoint is unavailable, the request fails over to the next endpoint of the
+// conversation, which it is pinned to from then on. Bad requests and cancellations never fail over.
+// The conversation is left untouched when all endpoints fail.
+func (s *Shards) ChatConversation(ctx context.Context, conversation *Conversation, params *ChatParams, msgs ...ChatMessage) (string, error) {
+	client := s.Pin(conversation)
+
+	if client == nil {
+		return "", ErrNoShards
+	}
+
+	history := append(append([]ChatMessage(nil), conversation.Messages...), msgs...)
+	candidates := append([]*Client{client}, s.rank(conversation.ID)...)
+	tried := map[*Client]bool{}
+
+	var err error
+
+	for _, candidate := range candidates {
+		if tried[candidate] {
+			continue
+		}
+
+		tried[candidate] = true
+
+		var reply string
+
+		reply, err = candidate.chatTextContext(ctx, conversation.Model, params, history...)
+
+		if err == nil {
+			conversation.Endpoint = candidate.base
+			conversation.Append(msgs...)
+			conversation.Append(ChatMessage{Role: ASSISTANT, Content: reply})
+
+			return reply, nil
+		}
+
+		if errors.Is(err, ErrBadRequest) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return "", err
+}
+
+// pinned returns the client of the endpoint the conversation is pinned to, nil when it is not pinned
+// or its endpoint is unavailable.
+func (s *Shards) pinned(conversation *Conversation) *Client {
+	if conversation.Endpoint == "" {
+		return nil
+	}
+
+	for _, client := range s.clients {
+		if client.base == conversation.Endpoint {
+			if s.health != nil && s.health.NotReady(client.base) {
+				return nil
+			}
+
+			return client
+		}
+	}
+
+	return nil
+}
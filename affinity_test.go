@@ -0,0 +1,89 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestShardsPin tests pinning a conversation on its first turn and pinning it again when its endpoint is not ready.
+func TestShardsPin(t *testing.T) {
+	a, _ := talkative.New("http://a:11434")
+	b, _ := talkative.New("http://b:11434")
+
+	shards := talkative.NewShards(a, b)
+	conversation := &talkative.Conversation{ID: "conversation"}
+
+	pinned := shards.Pin(conversation)
+	{
+		assert.Same(t, shards.Pick("conversation"), pinned)
+		assert.NotEmpty(t, conversation.Endpoint)
+	}
+
+	// The pin survives changes of the assignment of its ID.
+	other, endpoint := a, "http://a:11434"
+
+	if pinned == a {
+		other, endpoint = b, "http://b:11434"
+	}
+
+	conversation.Endpoint = endpoint
+	assert.Same(t, other, shards.Pin(conversation))
+
+	monitor := talkative.NewHealthMonitor([]string{conversation.Endpoint}, talkative.HealthOptions{
+		Probe: func(ctx context.Context, endpoint string) error { return errors.New("down") },
+	})
+	monitor.Check(context.Background())
+	shards.SetHealthMonitor(monitor)
+
+	assert.Same(t, pinned, shards.Pin(conversation))
+	assert.Nil(t, talkative.NewShards().Pin(&talkative.Conversation{}))
+}
+
+// TestShardsChatConversation tests failing over to another endpoint when the pinned endpoint is unavailable.
+func TestShardsChatConversation(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		if request.Messages[len(request.Messages)-1].Content == "invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello!"}, Done: true})
+	})
+	defer server.Close()
+
+	down, _ := talkative.New("http://127.0.0.1:1")
+	up, _ := talkative.New(server.URL)
+
+	shards := talkative.NewShards(down, up)
+	conversation := &talkative.Conversation{ID: "conversation", Endpoint: "http://127.0.0.1:1"}
+
+	reply, err := shards.ChatConversation(context.Background(), conversation, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello!", reply)
+		assert.Equal(t, server.URL, conversation.Endpoint)
+		assert.Len(t, conversation.Messages, 2)
+	}
+
+	_, err = shards.ChatConversation(context.Background(), conversation, nil, talkative.ChatMessage{Role: talkative.USER, Content: "invalid"})
+	{
+		assert.ErrorIs(t, err, talkative.ErrBadRequest)
+		assert.Equal(t, server.URL, conversation.Endpoint)
+		assert.Len(t, conversation.Messages, 2)
+	}
+
+	_, err = talkative.NewShards().ChatConversation(context.Background(), conversation, nil)
+	assert.ErrorIs(t, err, talkative.ErrNoShards)
+}
@@ -0,0 +1,101 @@
+package talkative
+
+import (
+	"strings"
+	"time"
+)
+
+// AggregatedResponse is the result of a streamed chat or completion, accumulated from all of its chunks.
+type AggregatedResponse struct {
+	Model      string        // The model which generated the response.
+	Content    string        // The full content of the response.
+	Done       bool          // Whether the final response was received.
+	DoneReason string        // Why the generation completed, i.e: "stop" or "length".
+	Chunks     int           // The number of chunks received.
+	Elapsed    time.Duration // Time elapsed between the start of the request and the last chunk.
+	Context    []int         // The encoding of the conversation, only set for completions.
+
+	ChatMetrics // The metrics of the final response.
+}
+
+// aggregated is implemented by the responses which can be accumulated into an AggregatedResponse.
+type aggregated interface {
+	aggregate(a *aggregator)
+}
+
+// aggregate accumulates the chat response.
+func (r *ChatResponse) aggregate(a *aggregator) {
+	a.add(r.Model, r.Message.Content)
+
+	if r.Done {
+		a.finish(r.DoneReason, r.ChatMetrics, nil)
+	}
+}
+
+// aggregate accumulates the completion response.
+func (r *CompletionResponse) aggregate(a *aggregator) {
+	a.add(r.Model, r.Response)
+
+	if r.Done {
+		metrics := ChatMetrics{
+			TotalDuration:      r.TotalDuration,
+			LoadDuration:       r.LoadDuration,
+			PromptEvalCount:    r.PromptEvalCount,
+			PromptEvalDuration: r.PromptEvalDuration,
+			EvalCount:          r.EvalCount,
+			EvalDuration:       r.EvalDuration,
+			Estimated:          r.Estimated,
+		}
+
+		a.finish(r.DoneReason, metrics, r.Context)
+	}
+}
+
+// aggregator accumulates the chunks of a stream.
+type aggregator struct {
+	start    time.Time
+	content  strings.Builder
+	response AggregatedResponse
+}
+
+// newAggregator creates a new aggregator for a stream starting now.
+func newAggregator() *aggregator {
+	return &aggregator{start: time.Now()}
+}
+
+// add accumulates a chunk.
+func (a *aggregator) add(model, content string) {
+	a.content.WriteString(content)
+	a.response.Model = model
+	a.response.Chunks++
+	a.response.Elapsed = time.Since(a.start)
+}
+
+// finish records the final chunk.
+func (a *aggregator) finish(reason string, metrics ChatMetrics, context []int) {
+	a.response.Done = true
+	a.response.DoneReason = reason
+	a.response.ChatMetrics = metrics
+	a.response.Context = context
+}
+
+// result returns the response accumulated so far.
+func (a *aggregator) result() *AggregatedResponse {
+	response := a.response
+	response.Content = a.content.String()
+
+	return &response
+}
+
+// accumulate wraps the callback so the successful responses are accumulated.
+func accumulate[T any](a *aggregator, cb func(*T, error)) func(*T, error) {
+	return func(response *T, err error) {
+		if err == nil {
+			if r, ok := any(response).(aggregated); ok {
+				r.aggregate(a)
+			}
+		}
+
+		cb(response, err)
+	}
+}
@@ -0,0 +1,65 @@
+package talkative_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCallResult tests accumulating the chunks and final metrics of a chat.
+func TestCallResult(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Model: "llama3", Message: talkative.ChatMessage{Content: "Hello"}},
+		talkative.ChatResponse{Model: "llama3", Message: talkative.ChatMessage{Content: " world"}},
+		talkative.ChatResponse{Model: "llama3", Message: talkative.ChatMessage{Content: "!"}, Done: true, DoneReason: "stop", ChatMetrics: talkative.ChatMetrics{EvalCount: 3, TotalDuration: 42}},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	call, err := client.StartChat(context.Background(), "", nil, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	assert.NoError(t, err)
+
+	result, err := call.Result()
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "llama3", result.Model)
+		assert.Equal(t, "Hello world!", result.Content)
+		assert.True(t, result.Done)
+		assert.Equal(t, "stop", result.DoneReason)
+		assert.Equal(t, 3, result.Chunks)
+		assert.Equal(t, 3, result.EvalCount)
+		assert.Equal(t, 42, result.TotalDuration)
+		assert.Nil(t, result.Context)
+	}
+}
+
+// TestCallResultCompletion tests accumulating the chunks, final metrics and context of a completion.
+func TestCallResultCompletion(t *testing.T) {
+	server := streamServer(
+		talkative.CompletionResponse{Response: "Hello"},
+		talkative.CompletionResponse{Done: true, DoneReason: "length", CompletionMetrics: talkative.CompletionMetrics{EvalCount: 1, Context: []int{1, 2}}},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	chunks := 0
+
+	call, err := client.StartCompletion(context.Background(), "", func(cr *talkative.CompletionResponse, err error) {
+		chunks++
+	}, &talkative.CompletionMessage{Prompt: "Hi"})
+	assert.NoError(t, err)
+
+	result, err := call.Result()
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello", result.Content)
+		assert.Equal(t, "length", result.DoneReason)
+		assert.Equal(t, 1, result.EvalCount)
+		assert.Equal(t, []int{1, 2}, result.Context)
+		assert.Equal(t, 2, chunks)
+	}
+}
@@ -0,0 +1,179 @@
+package talkative
+
+import (
+	"context"
+	"sync"
+)
+
+// models tracks the model aliases of a client and its in-flight requests per model.
+type models struct {
+	mu       sync.Mutex
+	aliases  map[string]string // Maps aliases to the model they currently point to.
+	inflight map[string]int    // Number of in-flight requests per model.
+	changed  chan struct{}     // Closed and replaced whenever a request finishes.
+}
+
+// SetAlias makes alias point to the given model, requests for the alias are sent to the model.
+//
+// Aliases are resolved once, an alias pointing to another alias is not followed.
+func (c *Client) SetAlias(alias, model string) {
+	c.models.mu.Lock()
+	defer c.models.mu.Unlock()
+
+	if c.models.aliases == nil {
+		c.models.aliases = map[string]string{}
+	}
+
+	c.models.aliases[alias] = model
+}
+
+// RemoveAlias removes the alias, requests for it are sent to a model of the same name again.
+func (c *Client) RemoveAlias(alias string) {
+	c.models.mu.Lock()
+	defer c.models.mu.Unlock()
+
+	delete(c.models.aliases, alias)
+}
+
+// Alias returns the model the alias points to, the boolean reports whether the alias exists.
+func (c *Client) Alias(alias string) (string, bool) {
+	c.models.mu.Lock()
+	defer c.models.mu.Unlock()
+
+	model, ok := c.models.aliases[alias]
+
+	return model, ok
+}
+
+// InFlight returns the number of in-flight requests of the given model.
+func (c *Client) InFlight(model string) int {
+	c.models.mu.Lock()
+	defer c.models.mu.Unlock()
+
+	return c.models.inflight[model]
+}
+
+// SwitchAlias atomically points the alias to another model and emits EVENT_ALIAS_SWITCHED.
+//
+// Requests issued after the switch use the new model while in-flight requests continue on the previous one.
+// When drain is true, SwitchAlias then waits for the in-flight requests of the previous model to finish and
+// emits EVENT_ALIAS_DRAINED, allowing the previous model to be unloaded safely (blue/green deployment).
+// The wait is aborted when the context is cancelled, the switch itself is never rolled back.
+func (c *Client) SwitchAlias(ctx context.Context, alias, model string, drain bool) error {
+	c.models.mu.Lock()
+
+	if c.models.aliases == nil {
+		c.models.aliases = map[string]string{}
+	}
+
+	previous := c.models.aliases[alias]
+	c.models.aliases[alias] = model
+
+	c.models.mu.Unlock()
+
+	c.events.emit(Event{
+		Type:     EVENT_ALIAS_SWITCHED,
+		Op:       "alias",
+		Model:    model,
+		Alias:    alias,
+		Previous: previous,
+	})
+
+	if !drain || previous == "" || previous == model {
+		return nil
+	}
+
+	for {
+		c.models.mu.Lock()
+		inflight := c.models.inflight[previous]
+		changed := c.models.changed
+
+		if changed == nil {
+			changed = make(chan struct{})
+			c.models.changed = changed
+		}
+
+		c.models.mu.Unlock()
+
+		if inflight == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+
+	c.events.emit(Event{
+		Type:     EVENT_ALIAS_DRAINED,
+		Op:       "alias",
+		Model:    model,
+		Alias:    alias,
+		Previous: previous,
+	})
+
+	return nil
+}
+
+// resolve returns the model the given name points to when it is an alias, the name itself otherwise.
+func (c *Client) resolve(name string) string {
+	c.models.mu.Lock()
+	defer c.models.mu.Unlock()
+
+	if model, ok := c.models.aliases[name]; ok {
+		return model
+	}
+
+	return name
+}
+
+// hold resolves the model the given name points to, like resolve, and records an in-flight request of it in
+// the same critical section, so a drain of the previous model of a switched alias can't miss the request while
+// it is being prepared. The caller must release the returned model once the request is tracked by begin.
+func (c *Client) hold(name string) string {
+	c.models.mu.Lock()
+	defer c.models.mu.Unlock()
+
+	model, ok := c.models.aliases[name]
+
+	if !ok {
+		model = name
+	}
+
+	if c.models.inflight == nil {
+		c.models.inflight = map[string]int{}
+	}
+
+	c.models.inflight[model]++
+
+	return model
+}
+
+// acquire records an in-flight request of the model.
+func (c *Client) acquire(model string) {
+	c.models.mu.Lock()
+	defer c.models.mu.Unlock()
+
+	if c.models.inflight == nil {
+		c.models.inflight = map[string]int{}
+	}
+
+	c.models.inflight[model]++
+}
+
+// release records the end of an in-flight request of the model and wakes up drains.
+func (c *Client) release(model string) {
+	c.models.mu.Lock()
+	defer c.models.mu.Unlock()
+
+	if c.models.inflight[model]--; c.models.inflight[model] <= 0 {
+		delete(c.models.inflight, model)
+	}
+
+	if c.models.changed != nil {
+		close(c.models.changed)
+		c.models.changed = nil
+	}
+}
@@ -0,0 +1,144 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSwitchAlias tests switching an alias to another model while draining the in-flight requests.
+func TestSwitchAlias(t *testing.T) {
+	release := make(chan struct{})
+	models := make(chan string, 2)
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		models <- request.Model
+
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		if request.Model == "blue" {
+			<-release
+		}
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Model: request.Model, Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	client.SetAlias("prod", "blue")
+
+	events := make(chan talkative.Event, 2)
+	client.Subscribe(func(e talkative.Event) { events <- e }, talkative.EVENT_ALIAS_SWITCHED, talkative.EVENT_ALIAS_DRAINED)
+
+	message := talkative.ChatMessage{Role: talkative.USER, Content: "Hi"}
+	blue, err := client.Chat("prod", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "blue", <-models)
+		assert.Equal(t, 1, client.InFlight("blue"))
+	}
+
+	switched := make(chan error)
+
+	go func() {
+		switched <- client.SwitchAlias(context.Background(), "prod", "green", true)
+	}()
+
+	event := <-events
+	assert.Equal(t, talkative.EVENT_ALIAS_SWITCHED, event.Type)
+	assert.Equal(t, "blue", event.Previous)
+
+	model, _ := client.Alias("prod")
+	assert.Equal(t, "green", model)
+
+	green, _ := client.Chat("prod", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+	<-green
+	assert.Equal(t, "green", <-models)
+
+	select {
+	case <-switched:
+		t.Fatal("switch returned before the in-flight requests were drained")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-blue
+
+	assert.NoError(t, <-switched)
+	assert.Equal(t, talkative.EVENT_ALIAS_DRAINED, (<-events).Type)
+	assert.Equal(t, 0, client.InFlight("blue"))
+}
+
+// TestSwitchAliasPreparing tests that draining waits for requests resolved to the previous model while they
+// are still being prepared, and that failed preparations don't stay in-flight.
+func TestSwitchAliasPreparing(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Done: true})
+	}))
+	defer server.Close()
+
+	preparing := make(chan string)
+	resume := make(chan error)
+
+	client, _ := talkative.New(server.URL, talkative.WithChatHook(func(request *talkative.ChatRequest) error {
+		preparing <- request.Model
+
+		return <-resume
+	}))
+	client.SetAlias("prod", "blue")
+
+	message := talkative.ChatMessage{Role: talkative.USER, Content: "Hi"}
+	sent := make(chan error)
+
+	go func() {
+		done, err := client.Chat("prod", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+
+		if err == nil {
+			<-done
+		}
+
+		sent <- err
+	}()
+
+	assert.Equal(t, "blue", <-preparing)
+	assert.Equal(t, 1, client.InFlight("blue"))
+
+	switched := make(chan error)
+
+	go func() {
+		switched <- client.SwitchAlias(context.Background(), "prod", "green", true)
+	}()
+
+	select {
+	case <-switched:
+		t.Fatal("switch returned before the request being prepared was drained")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	resume <- nil
+	assert.NoError(t, <-sent)
+	assert.NoError(t, <-switched)
+	assert.Equal(t, 0, client.InFlight("blue"))
+
+	go func() {
+		_, err := client.Chat("prod", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+
+		sent <- err
+	}()
+
+	assert.Equal(t, "green", <-preparing)
+
+	resume <- assert.AnError
+	assert.ErrorIs(t, <-sent, assert.AnError)
+	assert.Equal(t, 0, client.InFlight("green"))
+}
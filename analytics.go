@@ -0,0 +1,330 @@
+package talkative
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// OUTPUT_LENGTH_BUCKETS are the upper bounds, in characters, of the output length histogram.
+var OUTPUT_LENGTH_BUCKETS = []int{64, 256, 1024, 4096, 16384}
+
+// OutputStats holds the statistics computed over a single output.
+type OutputStats struct {
+	Model      string  // The model which produced the output.
+	Length     int     // The length of the output in characters.
+	Words      int     // The number of words of the output.
+	Language   string  // The guessed language, an ISO 639-1 code or "unknown".
+	Repetition float64 // The share of repeated word trigrams, from 0 (no repetition) to 1.
+}
+
+// ModelStats aggregates the statistics of the outputs of a model.
+type ModelStats struct {
+	Outputs         int            // The number of outputs analyzed.
+	Buckets         []int          // The number of outputs per length bucket, the last one counts longer outputs.
+	Length          int            // The total length of the outputs in characters.
+	Languages       map[string]int // The number of outputs per guessed language.
+	Repetition      float64        // The sum of the repetition scores.
+	MaxRepetition   float64        // The highest repetition score.
+	RepetitiveCount int            // The number of outputs exceeding the repetition threshold.
+}
+
+// MeanRepetition returns the mean repetition score of the outputs.
+func (s ModelStats) MeanRepetition() float64 {
+	if s.Outputs == 0 {
+		return 0
+	}
+
+	return s.Repetition / float64(s.Outputs)
+}
+
+// Analyzer computes simple statistics over model outputs to detect degradation in production,
+// such as repetition loops or outputs switching language.
+//
+// Outputs are analyzed by wrapping callbacks with TrackChat and TrackCompletion, or directly with Analyze.
+// The aggregated statistics are exported in the Prometheus text format by registering the analyzer
+// with Metrics.Register. An Analyzer is safe for concurrent use.
+type Analyzer struct {
+	// Threshold is the repetition score above which an output is counted as repetitive, defaults to 0.5.
+	Threshold float64
+
+	// OnStats is called with the statistics of every analyzed output. (Optional)
+	OnStats func(stats OutputStats)
+
+	mu     sync.Mutex
+	models map[string]*ModelStats
+}
+
+// NewAnalyzer creates a new analyzer with the default repetition threshold.
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{Threshold: 0.5, models: map[string]*ModelStats{}}
+}
+
+// Analyze computes and records the statistics of the output of the model.
+func (a *Analyzer) Analyze(model, output string) OutputStats {
+	words := strings.Fields(output)
+
+	stats := OutputStats{
+		Model:      model,
+		Length:     len([]rune(output)),
+		Words:      len(words),
+		Language:   GuessLanguage(output),
+		Repetition: RepetitionScore(words),
+	}
+
+	a.mu.Lock()
+
+	s, ok := a.models[model]
+
+	if !ok {
+		s = &ModelStats{Buckets: make([]int, len(OUTPUT_LENGTH_BUCKETS)+1), Languages: map[string]int{}}
+		a.models[model] = s
+	}
+
+	bucket := sort.SearchInts(OUTPUT_LENGTH_BUCKETS, stats.Length)
+
+	s.Outputs++
+	s.Buckets[bucket]++
+	s.Length += stats.Length
+	s.Languages[stats.Language]++
+	s.Repetition += stats.Repetition
+	s.MaxRepetition = math.Max(s.MaxRepetition, stats.Repetition)
+
+	if stats.Repetition > a.Threshold {
+		s.RepetitiveCount++
+	}
+
+	a.mu.Unlock()
+
+	if a.OnStats != nil {
+		a.OnStats(stats)
+	}
+
+	return stats
+}
+
+// Stats returns a copy of the aggregated statistics of the model.
+func (a *Analyzer) Stats(model string) ModelStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, ok := a.models[model]
+
+	if !ok {
+		return ModelStats{}
+	}
+
+	stats := *s
+	stats.Buckets = append([]int{}, s.Buckets...)
+	stats.Languages = make(map[string]int, len(s.Languages))
+
+	for language, count := range s.Languages {
+		stats.Languages[language] = count
+	}
+
+	return stats
+}
+
+// TrackChat wraps the callback so the complete output of the chat is analyzed once the final response arrives.
+func (a *Analyzer) TrackChat(cb ChatCallBack) ChatCallBack {
+	sb := strings.Builder{}
+
+	return func(cr *ChatResponse, err error) {
+		if err == nil {
+			sb.WriteString(cr.Message.Content)
+
+			if cr.Done {
+				a.Analyze(cr.Model, sb.String())
+			}
+		}
+
+		cb(cr, err)
+	}
+}
+
+// TrackCompletion wraps the callback so the complete output of the completion is analyzed once the final response arrives.
+func (a *Analyzer) TrackCompletion(cb CompletionCallback) CompletionCallback {
+	sb := strings.Builder{}
+
+	return func(cr *CompletionResponse, err error) {
+		if err == nil {
+			sb.WriteString(cr.Response)
+
+			if cr.Done {
+				a.Analyze(cr.Model, sb.String())
+			}
+		}
+
+		cb(cr, err)
+	}
+}
+
+// WriteTo writes the aggregated statistics in the Prometheus text exposition format.
+func (a *Analyzer) WriteTo(w io.Writer) (int64, error) {
+	a.mu.Lock()
+
+	models := make([]string, 0, len(a.models))
+
+	for model := range a.models {
+		models = append(models, model)
+	}
+
+	sort.Strings(models)
+
+	buf := &bytes.Buffer{}
+
+	fmt.Fprint(buf, "# HELP talkative_output_length_chars Length of the outputs in characters.\n# TYPE talkative_output_length_chars histogram\n")
+
+	for _, model := range models {
+		s := a.models[model]
+		cumulative := 0
+
+		for i, bound := range OUTPUT_LENGTH_BUCKETS {
+			cumulative += s.Buckets[i]
+			fmt.Fprintf(buf, "talkative_output_length_chars_bucket{model=%s,le=\"%d\"} %d\n", labelValue(model), bound, cumulative)
+		}
+
+		fmt.Fprintf(buf, "talkative_output_length_chars_bucket{model=%s,le=\"+Inf\"} %d\n", labelValue(model), s.Outputs)
+		fmt.Fprintf(buf, "talkative_output_length_chars_sum{model=%s} %d\n", labelValue(model), s.Length)
+		fmt.Fprintf(buf, "talkative_output_length_chars_count{model=%s} %d\n", labelValue(model), s.Outputs)
+	}
+
+	fmt.Fprint(buf, "# HELP talkative_output_language_total Number of outputs per guessed language.\n# TYPE talkative_output_language_total counter\n")
+
+	for _, model := range models {
+		languages := make([]string, 0, len(a.models[model].Languages))
+
+		for language := range a.models[model].Languages {
+			languages = append(languages, language)
+		}
+
+		sort.Strings(languages)
+
+		for _, language := range languages {
+			fmt.Fprintf(buf, "talkative_output_language_total{model=%s,language=%s} %d\n", labelValue(model), labelValue(language), a.models[model].Languages[language])
+		}
+	}
+
+	fmt.Fprint(buf, "# HELP talkative_output_repetition_max Highest repetition score of the outputs.\n# TYPE talkative_output_repetition_max gauge\n")
+
+	for _, model := range models {
+		fmt.Fprintf(buf, "talkative_output_repetition_max{model=%s} %g\n", labelValue(model), a.models[model].MaxRepetition)
+	}
+
+	fmt.Fprint(buf, "# HELP talkative_output_repetitive_total Number of outputs exceeding the repetition threshold.\n# TYPE talkative_output_repetitive_total counter\n")
+
+	for _, model := range models {
+		fmt.Fprintf(buf, "talkative_output_repetitive_total{model=%s} %d\n", labelValue(model), a.models[model].RepetitiveCount)
+	}
+
+	a.mu.Unlock()
+
+	return buf.WriteTo(w)
+}
+
+// RepetitionScore returns the share of repeated word trigrams, from 0 (no repetition) to 1.
+//
+// Outputs stuck in a loop repeat the same sequence of words and score close to 1.
+func RepetitionScore(words []string) float64 {
+	if len(words) < 4 {
+		return 0
+	}
+
+	seen := make(map[[3]string]bool, len(words))
+	repeated := 0
+
+	for i := 0; i+3 <= len(words); i++ {
+		trigram := [3]string{strings.ToLower(words[i]), strings.ToLower(words[i+1]), strings.ToLower(words[i+2])}
+
+		if seen[trigram] {
+			repeated++
+		}
+
+		seen[trigram] = true
+	}
+
+	return float64(repeated) / float64(len(words)-2)
+}
+
+// stopwords are frequent words used to tell apart languages written in the Latin script.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "of", "to", "in", "that", "it", "with", "for"},
+	"es": {"el", "la", "de", "que", "y", "en", "los", "es", "por", "una"},
+	"fr": {"le", "la", "les", "de", "et", "est", "une", "des", "pour", "que"},
+	"de": {"der", "die", "und", "das", "ist", "nicht", "ein", "zu", "mit", "den"},
+	"pt": {"o", "a", "de", "que", "e", "do", "da", "em", "não", "uma"},
+	"it": {"il", "di", "che", "e", "la", "per", "un", "non", "sono", "gli"},
+}
+
+// GuessLanguage guesses the language of the text from its script and, for the Latin script, its frequent words.
+//
+// It returns an ISO 639-1 code, or "unknown" when the text is too short or ambiguous.
+func GuessLanguage(text string) string {
+	scripts := map[string]int{}
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			scripts["zh"]++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			scripts["ja"]++
+		case unicode.Is(unicode.Hangul, r):
+			scripts["ko"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			scripts["ru"]++
+		case unicode.Is(unicode.Arabic, r):
+			scripts["ar"]++
+		case unicode.Is(unicode.Devanagari, r):
+			scripts["hi"]++
+		case unicode.Is(unicode.Latin, r):
+			scripts["latin"]++
+		}
+	}
+
+	// Japanese mixes kana with Han characters.
+	if scripts["ja"] > 0 {
+		scripts["ja"] += scripts["zh"]
+		scripts["zh"] = 0
+	}
+
+	script, best := "unknown", 0
+
+	for name, count := range scripts {
+		if count > best || (count == best && name < script) {
+			script, best = name, count
+		}
+	}
+
+	if script != "latin" {
+		return script
+	}
+
+	counts := map[string]int{}
+
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		word = strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) })
+
+		for language, words := range stopwords {
+			for _, stopword := range words {
+				if word == stopword {
+					counts[language]++
+				}
+			}
+		}
+	}
+
+	language, best := "unknown", 0
+
+	for name, count := range counts {
+		if count > best || (count == best && name < language) {
+			language, best = name, count
+		}
+	}
+
+	return language
+}
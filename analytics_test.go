@@ -0,0 +1,58 @@
+package talkative_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGuessLanguage tests guessing the language of outputs.
+func TestGuessLanguage(t *testing.T) {
+	assert.Equal(t, "en", talkative.GuessLanguage("The cat is sleeping on the sofa and it is happy."))
+	assert.Equal(t, "es", talkative.GuessLanguage("El gato duerme en el sofá y la casa es grande."))
+	assert.Equal(t, "fr", talkative.GuessLanguage("Le chat est sur le canapé et les enfants jouent."))
+	assert.Equal(t, "ru", talkative.GuessLanguage("Кошка спит на диване."))
+	assert.Equal(t, "ja", talkative.GuessLanguage("猫はソファで寝ています。"))
+	assert.Equal(t, "unknown", talkative.GuessLanguage("1234 5678"))
+}
+
+// TestRepetitionScore tests scoring repetition loops.
+func TestRepetitionScore(t *testing.T) {
+	assert.Equal(t, 0.0, talkative.RepetitionScore(strings.Fields("a quick brown fox jumps over the lazy dog")))
+	assert.Greater(t, talkative.RepetitionScore(strings.Fields(strings.Repeat("I am sorry. ", 20))), 0.9)
+}
+
+// TestAnalyzer tests analyzing streamed outputs and exporting the statistics.
+func TestAnalyzer(t *testing.T) {
+	analyzer := talkative.NewAnalyzer()
+	outputs := []talkative.OutputStats{}
+	analyzer.OnStats = func(stats talkative.OutputStats) { outputs = append(outputs, stats) }
+
+	cb := analyzer.TrackChat(func(cr *talkative.ChatResponse, err error) {})
+	cb(&talkative.ChatResponse{Model: "llama2", Message: talkative.ChatMessage{Content: strings.Repeat("loop again and ", 10)}}, nil)
+	cb(&talkative.ChatResponse{Model: "llama2", Done: true}, nil)
+
+	analyzer.TrackCompletion(func(cr *talkative.CompletionResponse, err error) {})(&talkative.CompletionResponse{Model: "llama2", Response: "The answer is 42.", Done: true}, nil)
+
+	stats := analyzer.Stats("llama2")
+	{
+		assert.Equal(t, 2, stats.Outputs)
+		assert.Equal(t, 1, stats.RepetitiveCount)
+		assert.Equal(t, []int{1, 1, 0, 0, 0, 0}, stats.Buckets)
+		assert.Equal(t, 2, stats.Languages["en"])
+		assert.Len(t, outputs, 2)
+	}
+
+	metrics := talkative.NewMetrics()
+	metrics.Register(analyzer)
+
+	buf := &bytes.Buffer{}
+	metrics.WriteTo(buf)
+
+	assert.Contains(t, buf.String(), `talkative_output_length_chars_bucket{model="llama2",le="+Inf"} 2`)
+	assert.Contains(t, buf.String(), `talkative_output_repetitive_total{model="llama2"} 1`)
+}
@@ -0,0 +1,163 @@
+package talkative
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Define an enum-like type to represent the kind of an attachment.
+type AttachmentKind string
+
+const (
+	// Images, sent to multimodal models as base64 encoded images.
+	ATTACHMENT_IMAGE AttachmentKind = "image"
+
+	// Audio recordings, transcribed with Client.Transcribe as chats and completions do not accept them.
+	ATTACHMENT_AUDIO AttachmentKind = "audio"
+
+	// Text documents, inlined in the content of the message.
+	ATTACHMENT_DOCUMENT AttachmentKind = "document"
+
+	// Any other content.
+	ATTACHMENT_BINARY AttachmentKind = "binary"
+)
+
+// ErrAttachment is returned when an attachment cannot be sent to the endpoint.
+var ErrAttachment = newError("unsupported attachment")
+
+// Attachment is a binary file attached to a message, i.e: an image.
+//
+// It carries its MIME type so it can be converted to whatever the target endpoint expects,
+// application code doesn't need to encode images itself.
+type Attachment struct {
+	Name string // The name of the file, used to detect the MIME type from its extension.
+	MIME string // The MIME type of the content, i.e: "image/png".
+	Data []byte // The raw content.
+}
+
+// NewAttachment creates a new attachment, detecting its MIME type from its content and name.
+func NewAttachment(name string, data []byte) *Attachment {
+	return &Attachment{Name: name, MIME: detectMIME(name, data), Data: data}
+}
+
+// ReadAttachment reads the content of the reader into a new attachment.
+func ReadAttachment(name string, r io.Reader) (*Attachment, error) {
+	data, err := io.ReadAll(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAttachment(name, data), nil
+}
+
+// OpenAttachment reads the file at path into a new attachment.
+func OpenAttachment(path string) (*Attachment, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAttachment(filepath.Base(path), data), nil
+}
+
+// Kind returns the kind of the attachment according to its MIME type.
+func (a *Attachment) Kind() AttachmentKind {
+	media, _, _ := mime.ParseMediaType(a.MIME)
+
+	switch {
+	case strings.HasPrefix(media, "image/"):
+		return ATTACHMENT_IMAGE
+	case strings.HasPrefix(media, "audio/"):
+		return ATTACHMENT_AUDIO
+	case strings.HasPrefix(media, "text/"), media == "application/json", media == "application/xml":
+		return ATTACHMENT_DOCUMENT
+	default:
+		return ATTACHMENT_BINARY
+	}
+}
+
+// Base64 returns the content encoded in standard base64, as expected by the images of the endpoints.
+func (a *Attachment) Base64() string {
+	return base64.StdEncoding.EncodeToString(a.Data)
+}
+
+// DataURL returns the content as a data URL, i.e: "data:image/png;base64,...".
+func (a *Attachment) DataURL() string {
+	return "data:" + a.MIME + ";base64," + a.Base64()
+}
+
+// Attach adds the attachments to the chat message.
+//
+// Images are added to the images of the message and documents are inlined in its content,
+// other kinds of attachments are rejected with ErrAttachment.
+func (m *ChatMessage) Attach(attachments ...*Attachment) error {
+	images, content, err := convertAttachments(attachments)
+
+	if err != nil {
+		return err
+	}
+
+	m.Images = append(m.Images, images...)
+	m.Content += content
+
+	return nil
+}
+
+// Attach adds the attachments to the completion message.
+//
+// Images are added to the images of the message and documents are inlined in its prompt,
+// other kinds of attachments are rejected with ErrAttachment.
+func (m *CompletionMessage) Attach(attachments ...*Attachment) error {
+	images, content, err := convertAttachments(attachments)
+
+	if err != nil {
+		return err
+	}
+
+	m.Images = append(m.Images, images...)
+	m.Prompt += content
+
+	return nil
+}
+
+// convertAttachments converts the attachments to base64 images and inlined document content.
+func convertAttachments(attachments []*Attachment) ([]string, string, error) {
+	images := []string{}
+	sb := strings.Builder{}
+
+	for _, attachment := range attachments {
+		switch attachment.Kind() {
+		case ATTACHMENT_IMAGE:
+			images = append(images, attachment.Base64())
+		case ATTACHMENT_DOCUMENT:
+			fmt.Fprintf(&sb, "\n\n%s:\n```\n%s\n```", attachment.Name, strings.TrimRight(string(attachment.Data), "\n"))
+		default:
+			return nil, "", fmt.Errorf("%w: %s (%s)", ErrAttachment, attachment.Name, attachment.MIME)
+		}
+	}
+
+	return images, sb.String(), nil
+}
+
+// detectMIME detects the MIME type from the content, falling back to the extension of the name.
+func detectMIME(name string, data []byte) string {
+	detected := http.DetectContentType(data)
+
+	if detected != "application/octet-stream" && !strings.HasPrefix(detected, "text/plain") {
+		return detected
+	}
+
+	if byExtension := mime.TypeByExtension(filepath.Ext(name)); byExtension != "" {
+		return byExtension
+	}
+
+	return detected
+}
@@ -0,0 +1,61 @@
+package talkative_test
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// png is the signature of a PNG image.
+var png = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// TestAttachment tests detecting the kind of attachments and converting them for the endpoints.
+func TestAttachment(t *testing.T) {
+	image := talkative.NewAttachment("photo", png)
+	{
+		assert.Equal(t, "image/png", image.MIME)
+		assert.Equal(t, talkative.ATTACHMENT_IMAGE, image.Kind())
+		assert.Equal(t, "data:image/png;base64,"+base64.StdEncoding.EncodeToString(png), image.DataURL())
+	}
+
+	document := talkative.NewAttachment("notes.txt", []byte("Buy milk\n"))
+	assert.Equal(t, talkative.ATTACHMENT_DOCUMENT, document.Kind())
+
+	message := talkative.ChatMessage{Role: talkative.USER, Content: "Describe these"}
+	{
+		assert.NoError(t, message.Attach(image, document))
+		assert.Equal(t, []string{image.Base64()}, message.Images)
+		assert.Equal(t, "Describe these\n\nnotes.txt:\n```\nBuy milk\n```", message.Content)
+	}
+
+	audio := talkative.NewAttachment("voice.mp3", []byte("ID3\x03\x00"))
+	completion := talkative.CompletionMessage{Prompt: "Transcribe"}
+	{
+		assert.Equal(t, talkative.ATTACHMENT_AUDIO, audio.Kind())
+		assert.ErrorIs(t, completion.Attach(audio), talkative.ErrAttachment)
+		assert.Empty(t, completion.Images)
+	}
+}
+
+// TestOpenAttachment tests reading attachments from files.
+func TestOpenAttachment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chart.png")
+	os.WriteFile(path, png, 0o600)
+
+	attachment, err := talkative.OpenAttachment(path)
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "chart.png", attachment.Name)
+		assert.Equal(t, talkative.ATTACHMENT_IMAGE, attachment.Kind())
+	}
+
+	_, err = talkative.OpenAttachment(filepath.Join(t.TempDir(), "missing.png"))
+	{
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,52 @@
+package talkative
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ErrAuth is returned when the credentials of a request cannot be obtained.
+var ErrAuth = newError("unable to obtain credentials")
+
+// TokenSource returns the bearer token of a request, i.e: refreshing an expired OAuth token.
+//
+// It is invoked before every request, so it should cache the token while it is valid.
+type TokenSource func(ctx context.Context) (string, error)
+
+// WithBearerToken sets the static bearer token in the "Authorization" header of every request.
+func WithBearerToken(token string) Option {
+	return WithTokenSource(func(ctx context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// WithTokenSource sets the bearer token returned by the source in the "Authorization" header of every request.
+//
+// Requests fail with ErrAuth when the source returns an error.
+func WithTokenSource(source TokenSource) Option {
+	return func(c *Client) {
+		c.auth = func(req *http.Request) error {
+			token, err := source(req.Context())
+
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrAuth, err)
+			}
+
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			return nil
+		}
+	}
+}
+
+// WithBasicAuth sets the basic authentication credentials of every request.
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) {
+		c.auth = func(req *http.Request) error {
+			req.SetBasicAuth(username, password)
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithBearerToken tests setting bearer tokens, static or refreshed, on streamed requests.
+func TestWithBearerToken(t *testing.T) {
+	authorizations := make(chan string, 1)
+
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		authorizations <- r.Header.Get("Authorization")
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Done: true})
+	})
+	defer server.Close()
+
+	message := talkative.ChatMessage{Role: talkative.USER, Content: "Hi"}
+
+	client, _ := talkative.New(server.URL, talkative.WithBearerToken("secret"))
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+	assert.NoError(t, err)
+	<-done
+	assert.Equal(t, "Bearer secret", <-authorizations)
+
+	refreshes := 0
+	client, _ = talkative.New(server.URL, talkative.WithTokenSource(func(ctx context.Context) (string, error) {
+		refreshes++
+
+		return fmt.Sprintf("token-%d", refreshes), nil
+	}))
+
+	for _, expected := range []string{"Bearer token-1", "Bearer token-2"} {
+		done, err = client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+		assert.NoError(t, err)
+		<-done
+		assert.Equal(t, expected, <-authorizations)
+	}
+
+	client, _ = talkative.New(server.URL, talkative.WithTokenSource(func(ctx context.Context) (string, error) {
+		return "", errors.New("expired refresh token")
+	}))
+	_, err = client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+	assert.ErrorIs(t, err, talkative.ErrAuth)
+}
+
+// TestWithBasicAuth tests setting basic authentication credentials.
+func TestWithBasicAuth(t *testing.T) {
+	credentials := make(chan [2]string, 1)
+
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		credentials <- [2]string{username, password}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]any{"models": []any{}})
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithBasicAuth("user", "pass"))
+
+	_, err := client.ListModels()
+	assert.NoError(t, err)
+	assert.Equal(t, [2]string{"user", "pass"}, <-credentials)
+}
@@ -0,0 +1,106 @@
+package talkative
+
+import (
+	"strings"
+	"time"
+)
+
+// WithMetricsBackfill estimates the metrics of the final chat and completion responses when they are missing,
+// i.e: stripped by a gateway, instead of leaving them zero.
+//
+// Durations are measured with the wall clock from the time the request is sent, and token counts are estimated
+// with a whitespace tokenization of the prompt and output. Estimated metrics are flagged with Estimated.
+func WithMetricsBackfill() Option {
+	return func(c *Client) {
+		c.backfill = true
+	}
+}
+
+// metered is implemented by the responses whose final chunk carries the metrics of the generation.
+type metered interface {
+	chunk() (content string, done bool)
+	tokens() (prompt, eval int)
+	backfill(estimated ChatMetrics)
+}
+
+// chunk returns the content of the response and whether it is the final one.
+func (r *ChatResponse) chunk() (string, bool) {
+	return r.Message.Content, r.Done
+}
+
+// tokens returns the number of prompt and generated tokens of the final response.
+func (r *ChatResponse) tokens() (int, int) {
+	return r.PromptEvalCount, r.EvalCount
+}
+
+// backfill sets the estimated metrics when the response has none.
+func (r *ChatResponse) backfill(estimated ChatMetrics) {
+	if r.TotalDuration == 0 && r.EvalCount == 0 {
+		r.ChatMetrics = estimated
+	}
+}
+
+// chunk returns the content of the response and whether it is the final one.
+func (r *CompletionResponse) chunk() (string, bool) {
+	return r.Response, r.Done
+}
+
+// tokens returns the number of prompt and generated tokens of the final response.
+func (r *CompletionResponse) tokens() (int, int) {
+	return r.PromptEvalCount, r.EvalCount
+}
+
+// backfill sets the estimated metrics when the response has none.
+func (r *CompletionResponse) backfill(estimated ChatMetrics) {
+	if r.TotalDuration == 0 && r.EvalCount == 0 {
+		r.CompletionMetrics = CompletionMetrics{
+			TotalDuration:      estimated.TotalDuration,
+			PromptEvalCount:    estimated.PromptEvalCount,
+			PromptEvalDuration: estimated.PromptEvalDuration,
+			EvalCount:          estimated.EvalCount,
+			EvalDuration:       estimated.EvalDuration,
+			Context:            r.Context,
+			Estimated:          true,
+		}
+	}
+}
+
+// estimate wraps the callback so the metrics missing from the final response are estimated from the prompt,
+// the output and the wall clock since start, the time the request was sent. Durations are in nanoseconds, like
+// the ones reported by the server.
+func estimate[T any](enabled bool, start time.Time, prompt func() string, cb func(T, error)) func(T, error) {
+	if !enabled {
+		return cb
+	}
+
+	var (
+		first  time.Time
+		output strings.Builder
+	)
+
+	return func(response T, err error) {
+		if r, ok := any(response).(metered); ok && err == nil {
+			now := time.Now()
+			content, done := r.chunk()
+
+			if first.IsZero() {
+				first = now
+			}
+
+			output.WriteString(content)
+
+			if done {
+				r.backfill(ChatMetrics{
+					TotalDuration:      int(now.Sub(start)),
+					PromptEvalCount:    len(strings.Fields(prompt())),
+					PromptEvalDuration: int(first.Sub(start)),
+					EvalCount:          len(strings.Fields(output.String())),
+					EvalDuration:       int(now.Sub(first)),
+					Estimated:          true,
+				})
+			}
+		}
+
+		cb(response, err)
+	}
+}
@@ -0,0 +1,93 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithMetricsBackfill tests estimating the metrics stripped from the final chat response.
+func TestWithMetricsBackfill(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "The sky "}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "is bl"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "ue."}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithMetricsBackfill())
+
+	var metrics talkative.ChatMetrics
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {
+		if cr.Done {
+			metrics = cr.ChatMetrics
+		}
+	}, nil, talkative.ChatMessage{Role: talkative.SYSTEM, Content: "Be brief."}, talkative.ChatMessage{Role: talkative.USER, Content: "Why is the sky blue?"})
+
+	assert.NoError(t, err)
+	<-done
+
+	assert.True(t, metrics.Estimated)
+	assert.Equal(t, 7, metrics.PromptEvalCount)
+	assert.Equal(t, 4, metrics.EvalCount)
+	assert.Greater(t, metrics.TotalDuration, 0)
+	assert.GreaterOrEqual(t, metrics.TotalDuration, metrics.PromptEvalDuration+metrics.EvalDuration)
+}
+
+// TestWithMetricsBackfillReported tests the metrics reported by the server are kept.
+func TestWithMetricsBackfillReported(t *testing.T) {
+	server := streamServer(talkative.CompletionResponse{
+		Response:          "Paris",
+		Done:              true,
+		CompletionMetrics: talkative.CompletionMetrics{TotalDuration: 42, EvalCount: 1, Context: []int{1, 2}},
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithMetricsBackfill())
+
+	var metrics talkative.CompletionMetrics
+
+	done, err := client.Completion("", func(cr *talkative.CompletionResponse, err error) {
+		metrics = cr.CompletionMetrics
+	}, &talkative.CompletionMessage{Prompt: "Capital of France?"})
+
+	assert.NoError(t, err)
+	<-done
+
+	assert.False(t, metrics.Estimated)
+	assert.Equal(t, 42, metrics.TotalDuration)
+	assert.Equal(t, []int{1, 2}, metrics.Context)
+}
+
+// TestWithMetricsBackfillHeaderDelay tests estimated durations include the time until the server answered.
+func TestWithMetricsBackfillHeaderDelay(t *testing.T) {
+	delay := 100 * time.Millisecond
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+
+		json.NewEncoder(w).Encode(talkative.CompletionResponse{Response: "Paris", Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithMetricsBackfill())
+
+	var metrics talkative.CompletionMetrics
+
+	done, err := client.Completion("", func(cr *talkative.CompletionResponse, err error) {
+		metrics = cr.CompletionMetrics
+	}, &talkative.CompletionMessage{Prompt: "Capital of France?"})
+
+	assert.NoError(t, err)
+	<-done
+
+	assert.True(t, metrics.Estimated)
+	assert.GreaterOrEqual(t, metrics.TotalDuration, int(delay))
+	assert.GreaterOrEqual(t, metrics.PromptEvalDuration, int(delay))
+}
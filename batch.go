@@ -0,0 +1,223 @@
+package talkative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DEFAULT_EMBED_BATCH_SIZE is the number of inputs embedded per request when none is configured.
+const DEFAULT_EMBED_BATCH_SIZE = 16
+
+// ErrBatch is wrapped by the errors of the items of a batch.
+var ErrBatch = newError("batch item failed")
+
+// BatchOptions configures the batch APIs.
+type BatchOptions struct {
+	Concurrency int // Maximum number of items processed concurrently, defaults to DEFAULT_MAP_CONCURRENCY.
+	Retries     int // Number of additional attempts made for a failing item.
+
+	// Size is the number of inputs embedded per request by EmbedBatch, defaults to DEFAULT_EMBED_BATCH_SIZE.
+	Size int
+}
+
+// BatchResult is the outcome of a single item of a batch.
+type BatchResult[T any] struct {
+	Index    int           // The index of the item in the batch.
+	Value    T             // The value produced for the item, the zero value when it failed.
+	Err      error         // The error of the item, if it failed after all retries.
+	Attempts int           // The number of attempts made for the item.
+	Duration time.Duration // Time spent on the item, including all its attempts.
+}
+
+// OK reports whether the item succeeded.
+func (r BatchResult[T]) OK() bool {
+	return r.Err == nil
+}
+
+// BatchResults are the outcomes of the items of a batch, in the order of the items.
+type BatchResults[T any] []BatchResult[T]
+
+// Succeeded returns the results of the items which succeeded.
+func (r BatchResults[T]) Succeeded() BatchResults[T] {
+	return r.filter(true)
+}
+
+// Failed returns the results of the items which failed.
+func (r BatchResults[T]) Failed() BatchResults[T] {
+	return r.filter(false)
+}
+
+// Values returns the values of the items which succeeded, in the order of the items.
+func (r BatchResults[T]) Values() []T {
+	var values []T
+
+	for _, result := range r {
+		if result.OK() {
+			values = append(values, result.Value)
+		}
+	}
+
+	return values
+}
+
+// Err returns the errors of the failed items joined together, nil when all items succeeded.
+func (r BatchResults[T]) Err() error {
+	var errs []error
+
+	for _, result := range r {
+		if !result.OK() {
+			errs = append(errs, result.Err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// filter returns the results which succeeded or failed.
+func (r BatchResults[T]) filter(ok bool) BatchResults[T] {
+	var results BatchResults[T]
+
+	for _, result := range r {
+		if result.OK() == ok {
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+// Batch applies fn to every item concurrently and reports the outcome of each item, a failing item never
+// fails the whole batch.
+//
+// Failing items are retried up to opts.Retries times, unless the context is done. The results are always
+// ordered like the items, regardless of the order they complete in.
+func Batch[T, R any](ctx context.Context, items []T, opts BatchOptions, fn func(context.Context, T) (R, error)) BatchResults[R] {
+	concurrency := opts.Concurrency
+
+	if concurrency <= 0 {
+		concurrency = DEFAULT_MAP_CONCURRENCY
+	}
+
+	var wg sync.WaitGroup
+
+	results := make(BatchResults[R], len(items))
+	slots := make(chan struct{}, concurrency)
+
+	for i, item := range items {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func(i int, item T) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			results[i] = batchStep(ctx, i, item, opts.Retries, fn)
+		}(i, item)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// batchStep processes a single item of a batch, retrying failed attempts.
+func batchStep[T, R any](ctx context.Context, i int, item T, retries int, fn func(context.Context, T) (R, error)) BatchResult[R] {
+	result := BatchResult[R]{Index: i}
+	start := time.Now()
+
+	for attempt := 0; attempt <= retries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			result.Err = err
+
+			break
+		}
+
+		result.Attempts++
+		result.Value, result.Err = fn(ctx, item)
+
+		if result.Err == nil {
+			break
+		}
+	}
+
+	result.Duration = time.Since(start)
+
+	if result.Err != nil {
+		var zero R
+
+		result.Value = zero
+		result.Err = fmt.Errorf("%w: item %d: %w", ErrBatch, i, result.Err)
+	}
+
+	return result
+}
+
+// ChatBatch sends every conversation to the model concurrently and reports the aggregated response
+// of each conversation, see Batch.
+//
+// When model is empty, the default model of the client is used.
+func (c *Client) ChatBatch(ctx context.Context, model string, params *ChatParams, conversations [][]ChatMessage, opts BatchOptions) BatchResults[string] {
+	if model == "" {
+		model = c.DefaultModel()
+	}
+
+	return Batch(ctx, conversations, opts, func(ctx context.Context, msgs []ChatMessage) (string, error) {
+		return c.chatTextContext(ctx, model, params, msgs...)
+	})
+}
+
+// EmbedBatch generates the embeddings of the inputs and reports the embedding of each input, see Batch.
+//
+// The inputs are embedded opts.Size at a time, the inputs of a failing request share its error and attempts.
+// When model is empty, the default model of the client is used.
+func (c *Client) EmbedBatch(ctx context.Context, model string, params *EmbeddingsParams, inputs []string, opts BatchOptions) BatchResults[[]float64] {
+	size := opts.Size
+
+	if size <= 0 {
+		size = DEFAULT_EMBED_BATCH_SIZE
+	}
+
+	var groups [][]string
+
+	for start := 0; start < len(inputs); start += size {
+		groups = append(groups, inputs[start:min(start+size, len(inputs))])
+	}
+
+	batches := Batch(ctx, groups, opts, func(ctx context.Context, group []string) ([][]float64, error) {
+		response, err := c.EmbeddingsContext(ctx, model, params, group...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(response.Embeddings) != len(group) {
+			return nil, fmt.Errorf("%w: %d embeddings for %d inputs", ErrDecoding, len(response.Embeddings), len(group))
+		}
+
+		return response.Embeddings, nil
+	})
+
+	results := make(BatchResults[[]float64], 0, len(inputs))
+
+	for g, batch := range batches {
+		for j := range groups[g] {
+			result := BatchResult[[]float64]{
+				Index:    len(results),
+				Err:      batch.Err,
+				Attempts: batch.Attempts,
+				Duration: batch.Duration,
+			}
+
+			if batch.OK() {
+				result.Value = batch.Value[j]
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,140 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBatch tests that failing items are retried and reported without failing the whole batch.
+func TestBatch(t *testing.T) {
+	var calls atomic.Int32
+
+	errOdd := errors.New("odd")
+
+	results := talkative.Batch(context.Background(), []int{1, 2, 3, 4}, talkative.BatchOptions{Concurrency: 2, Retries: 1}, func(ctx context.Context, n int) (int, error) {
+		calls.Add(1)
+
+		if n%2 == 1 {
+			return n, errOdd
+		}
+
+		return n * 10, nil
+	})
+	{
+		assert.Len(t, results, 4)
+		assert.Equal(t, int32(6), calls.Load())
+		assert.Equal(t, []int{20, 40}, results.Values())
+
+		assert.Len(t, results.Succeeded(), 2)
+		assert.Equal(t, 1, results.Succeeded()[0].Index)
+		assert.Equal(t, 1, results.Succeeded()[0].Attempts)
+
+		failed := results.Failed()
+
+		assert.Len(t, failed, 2)
+		assert.Equal(t, 2, failed[1].Index)
+		assert.Equal(t, 2, failed[1].Attempts)
+		assert.Zero(t, failed[1].Value)
+		assert.ErrorIs(t, failed[1].Err, talkative.ErrBatch)
+		assert.ErrorIs(t, failed[1].Err, errOdd)
+		assert.ErrorIs(t, results.Err(), errOdd)
+		assert.NoError(t, results.Succeeded().Err())
+	}
+}
+
+// TestBatchCancelled tests that no attempt is made once the context is done.
+func TestBatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := talkative.Batch(ctx, []string{"a"}, talkative.BatchOptions{Retries: 3}, func(ctx context.Context, s string) (string, error) {
+		return s, nil
+	})
+	{
+		assert.ErrorIs(t, results[0].Err, context.Canceled)
+		assert.Equal(t, 0, results[0].Attempts)
+	}
+}
+
+// TestChatBatch tests reporting the response of every conversation.
+func TestChatBatch(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		prompt := request.Messages[0].Content
+
+		if prompt == "fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid prompt"})
+
+			return
+		}
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: strings.ToUpper(prompt)}, Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	results := client.ChatBatch(context.Background(), "", nil, [][]talkative.ChatMessage{
+		{{Role: talkative.USER, Content: "hello"}},
+		{{Role: talkative.USER, Content: "fail"}},
+		{{Role: talkative.USER, Content: "world"}},
+	}, talkative.BatchOptions{})
+	{
+		assert.Equal(t, []string{"HELLO", "WORLD"}, results.Values())
+		assert.Len(t, results.Failed(), 1)
+		assert.Equal(t, 1, results.Failed()[0].Index)
+		assert.ErrorIs(t, results.Failed()[0].Err, talkative.ErrBadRequest)
+	}
+}
+
+// TestEmbedBatch tests embedding the inputs in groups and reporting the embedding of every input.
+func TestEmbedBatch(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.EmbeddingsRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		if request.Input[0] == "c" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		embeddings := [][]float64{}
+
+		for _, input := range request.Input {
+			embeddings = append(embeddings, []float64{float64(len(input))})
+		}
+
+		json.NewEncoder(w).Encode(talkative.EmbeddingsResponse{Embeddings: embeddings})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	results := client.EmbedBatch(context.Background(), "all-minilm", nil, []string{"a", "bb", "c", "dddd", "eeeee"}, talkative.BatchOptions{Size: 2})
+	{
+		assert.Len(t, results, 5)
+		assert.Equal(t, [][]float64{{1}, {2}, {5}}, results.Values())
+		assert.Equal(t, 4, results[4].Index)
+
+		failed := results.Failed()
+
+		assert.Len(t, failed, 2)
+		assert.Equal(t, 2, failed[0].Index)
+		assert.Equal(t, 3, failed[1].Index)
+		assert.Error(t, results.Err())
+	}
+}
@@ -0,0 +1,87 @@
+package talkative
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+)
+
+// BlobDigest computes the digest of the content as expected by the blob endpoints, i.e: "sha256:29fdb92e57cf...".
+func BlobDigest(r io.Reader) (string, error) {
+	hash := sha256.New()
+
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// HasBlob reports whether the blob with the digest exists on the server, i.e: a GGUF layer used in a Modelfile.
+func (c *Client) HasBlob(digest string) (bool, error) {
+	return c.HasBlobContext(context.Background(), digest)
+}
+
+// HasBlobContext is identical to HasBlob(), except that the request is bound to the context.
+func (c *Client) HasBlobContext(ctx context.Context, digest string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.urls["blobs"]+"/"+digest, nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	res, err := c.roundTrip(req)
+
+	if err != nil {
+		return false, wrapError("blob", req.URL.String(), "", "", err)
+	}
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, res.Body.Close()
+	case http.StatusNotFound:
+		return false, res.Body.Close()
+	default:
+		_, err := check(res)
+
+		return false, wrapError("blob", req.URL.String(), "", "", err)
+	}
+}
+
+// PushBlob uploads the content of the reader as the blob with the digest, which the server verifies.
+//
+// Use BlobDigest to compute the digest of a file before uploading it.
+func (c *Client) PushBlob(digest string, r io.Reader) error {
+	return c.PushBlobContext(context.Background(), digest, r)
+}
+
+// PushBlobContext is identical to PushBlob(), except that the request is bound to the context.
+func (c *Client) PushBlobContext(ctx context.Context, digest string, r io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.urls["blobs"]+"/"+digest, r)
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	res, err := c.roundTrip(req)
+
+	if err != nil {
+		return wrapError("blob", req.URL.String(), "", "", err)
+	}
+
+	if res.StatusCode == http.StatusCreated {
+		return res.Body.Close()
+	}
+
+	res, err = check(res)
+
+	if err != nil {
+		return wrapError("blob", req.URL.String(), "", "", err)
+	}
+
+	return res.Body.Close()
+}
@@ -0,0 +1,66 @@
+package talkative_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBlobs tests checking and uploading blobs.
+func TestBlobs(t *testing.T) {
+	blobs := map[string]string{}
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		digest := strings.TrimPrefix(r.URL.Path, "/api/blobs/")
+
+		switch r.Method {
+		case http.MethodHead:
+			if _, ok := blobs[digest]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case http.MethodPost:
+			data, _ := io.ReadAll(r.Body)
+
+			if computed, _ := talkative.BlobDigest(strings.NewReader(string(data))); computed != digest {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("digest mismatch"))
+
+				return
+			}
+
+			blobs[digest] = string(data)
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	digest, _ := talkative.BlobDigest(strings.NewReader("GGUF layer"))
+
+	assert.True(t, strings.HasPrefix(digest, "sha256:"))
+
+	exists, err := client.HasBlob(digest)
+	{
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	}
+
+	assert.NoError(t, client.PushBlob(digest, strings.NewReader("GGUF layer")))
+
+	exists, err = client.HasBlob(digest)
+	{
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	}
+
+	err = client.PushBlob(digest, strings.NewReader("tampered"))
+	{
+		assert.ErrorIs(t, err, talkative.ErrBadRequest)
+		assert.Contains(t, err.Error(), "digest mismatch")
+	}
+}
@@ -0,0 +1,108 @@
+package talkative
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrStreamClosed is the error ending a stream stopped with Call.Close.
+var ErrStreamClosed = newError("stream closed")
+
+// Call is a handle over a streaming request, allowing the consumer to stop the generation early,
+// i.e: when the user hits "stop" in a chat UI.
+type Call struct {
+	cancel     context.CancelCauseFunc
+	finished   chan struct{}
+	err        error
+	aggregator *aggregator
+}
+
+// StartChat is identical to ChatDone(), except that it returns a handle over the request.
+//
+// The callback may be nil when only the result of the chat is needed, see Call.Result.
+func (c *Client) StartChat(ctx context.Context, model string, cb ChatCallBack, params *ChatParams, msgs ...ChatMessage) (*Call, error) {
+	if cb == nil {
+		cb = func(*ChatResponse, error) {}
+	}
+
+	a := newAggregator()
+	ctx, cancel := context.WithCancelCause(ctx)
+	done, err := c.ChatDone(ctx, model, accumulate(a, cb), params, msgs...)
+
+	if err != nil {
+		cancel(err)
+
+		return nil, err
+	}
+
+	return newCall(cancel, done, a), nil
+}
+
+// StartCompletion is identical to CompletionDone(), except that it returns a handle over the request.
+//
+// The callback may be nil when only the result of the completion is needed, see Call.Result.
+func (c *Client) StartCompletion(ctx context.Context, model string, cb CompletionCallback, msg *CompletionMessage) (*Call, error) {
+	if cb == nil {
+		cb = func(*CompletionResponse, error) {}
+	}
+
+	a := newAggregator()
+	ctx, cancel := context.WithCancelCause(ctx)
+	done, err := c.CompletionDone(ctx, model, accumulate(a, cb), msg)
+
+	if err != nil {
+		cancel(err)
+
+		return nil, err
+	}
+
+	return newCall(cancel, done, a), nil
+}
+
+// newCall creates a new Call over the request cancelled by cancel, ending with done and accumulated by a.
+func newCall(cancel context.CancelCauseFunc, done <-chan error, a *aggregator) *Call {
+	call := &Call{cancel: cancel, finished: make(chan struct{}), aggregator: a}
+
+	go func() {
+		call.err = <-done
+		cancel(nil)
+		close(call.finished)
+	}()
+
+	return call
+}
+
+// Done returns a channel closed once the stream ended and the callback returned for the last time.
+func (c *Call) Done() <-chan struct{} {
+	return c.finished
+}
+
+// Wait waits for the stream to end and returns the error which ended it, nil when it completed successfully.
+func (c *Call) Wait() error {
+	<-c.finished
+
+	return c.err
+}
+
+// Result waits for the stream to end and returns the response accumulated from its chunks, along with the
+// error which ended the stream. The response holds the partial content when the stream failed or was closed.
+func (c *Call) Result() (*AggregatedResponse, error) {
+	err := c.Wait()
+
+	return c.aggregator.result(), err
+}
+
+// Close stops the generation, closing the response body, and waits for the stream to end. The callback
+// receives ErrStreamClosed unless the stream already ended.
+//
+// It returns the error which ended the stream before it was closed, if any. Close is safe to call
+// several times and concurrently with Wait.
+func (c *Call) Close() error {
+	c.cancel(ErrStreamClosed)
+
+	if err := c.Wait(); !errors.Is(err, ErrStreamClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStartChat tests stopping a chat early through its handle.
+func TestStartChat(t *testing.T) {
+	release := make(chan struct{})
+
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}})
+		w.(http.Flusher).Flush()
+
+		<-release
+	})
+	defer server.Close()
+	defer close(release)
+
+	client, _ := talkative.New(server.URL)
+	received := make(chan string, 1)
+
+	var lastErr error
+
+	call, err := client.StartChat(context.Background(), "", func(cr *talkative.ChatResponse, err error) {
+		if err != nil {
+			lastErr = err
+
+			return
+		}
+
+		received <- cr.Message.Content
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello", <-received)
+	}
+
+	assert.NoError(t, call.Close())
+	assert.NoError(t, call.Close())
+	assert.ErrorIs(t, call.Wait(), talkative.ErrStreamClosed)
+	assert.ErrorIs(t, lastErr, talkative.ErrStreamClosed)
+
+	select {
+	case <-call.Done():
+	default:
+		assert.Fail(t, "call should be done")
+	}
+
+	_, err = client.StartChat(context.Background(), "", nil, nil)
+	assert.ErrorIs(t, err, talkative.ErrMessage)
+
+	result, err := call.Result()
+	{
+		assert.ErrorIs(t, err, talkative.ErrStreamClosed)
+		assert.Equal(t, "Hello", result.Content)
+		assert.False(t, result.Done)
+		assert.Equal(t, 1, result.Chunks)
+	}
+}
+
+// TestStartCompletion tests closing a completion which already ended.
+func TestStartCompletion(t *testing.T) {
+	server := streamServer(talkative.CompletionResponse{Response: "Hello", Done: true})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	content := ""
+
+	call, err := client.StartCompletion(context.Background(), "", func(cr *talkative.CompletionResponse, err error) {
+		if err == nil {
+			content += cr.Response
+		}
+	}, &talkative.CompletionMessage{Prompt: "Hi"})
+	{
+		assert.NoError(t, err)
+		assert.NoError(t, call.Wait())
+		assert.NoError(t, call.Close())
+		assert.Equal(t, "Hello", content)
+	}
+}
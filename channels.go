@@ -0,0 +1,75 @@
+package talkative
+
+import "context"
+
+// ChatStream is identical to ChatContext(), except that the responses are delivered on a channel instead of a callback,
+// so they can be ranged over or composed with select loops and pipelines.
+//
+// The responses channel is closed once the stream ended. The error channel then delivers the error which ended
+// the stream, if any, before being closed. Cancel the context to stop consuming the stream early, so the request
+// is aborted and its resources released.
+func (c *Client) ChatStream(ctx context.Context, model string, params *ChatParams, msgs ...ChatMessage) (<-chan ChatResponse, <-chan error) {
+	responses, errs, cb := channels[ChatResponse](ctx)
+
+	done, err := c.ChatContext(ctx, model, cb, params, msgs...)
+
+	go forward(done, err, responses, errs)
+
+	return responses, errs
+}
+
+// CompletionStream is identical to CompletionContext(), except that the responses are delivered on a channel
+// instead of a callback, like ChatStream() does.
+func (c *Client) CompletionStream(ctx context.Context, model string, msg *CompletionMessage) (<-chan CompletionResponse, <-chan error) {
+	responses, errs, cb := channels[CompletionResponse](ctx)
+
+	done, err := c.CompletionContext(ctx, model, cb, msg)
+
+	go forward(done, err, responses, errs)
+
+	return responses, errs
+}
+
+// channels returns the responses and error channels of a stream, along with the callback feeding them.
+//
+// The callback stops delivering responses once the context is cancelled, so abandoned streams never block.
+func channels[T any](ctx context.Context) (chan T, chan error, func(*T, error)) {
+	responses := make(chan T)
+	errs := make(chan error, 1)
+	failed := false
+
+	return responses, errs, func(response *T, err error) {
+		if failed {
+			return
+		}
+
+		if err != nil {
+			// Errors caused by the cancellation are reported as such.
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+
+			failed = true
+			errs <- err
+
+			return
+		}
+
+		select {
+		case responses <- *response:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// forward closes the channels once the request ended, reporting the error of a request which couldn't start.
+func forward[T any](done <-chan bool, err error, responses chan T, errs chan error) {
+	if err != nil {
+		errs <- err
+	} else {
+		<-done
+	}
+
+	close(responses)
+	close(errs)
+}
@@ -0,0 +1,74 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChatStream tests ranging over the responses of a chat.
+func TestChatStream(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "!"}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	responses, errs := client.ChatStream(context.Background(), "", nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+
+	content := ""
+
+	for response := range responses {
+		content += response.Message.Content
+	}
+
+	assert.Equal(t, "Hello!", content)
+	assert.NoError(t, <-errs)
+
+	_, errs = client.ChatStream(context.Background(), "", nil)
+	assert.ErrorIs(t, <-errs, talkative.ErrMessage)
+}
+
+// TestCompletionStream tests stopping the consumption of a stream early and receiving its error.
+func TestCompletionStream(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+
+		for i := 0; i < 100; i++ {
+			encoder.Encode(talkative.CompletionResponse{Response: "x"})
+		}
+
+		w.Write([]byte("{invalid"))
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	message := &talkative.CompletionMessage{Prompt: "Hi"}
+
+	responses, errs := client.CompletionStream(context.Background(), "", message)
+	count := 0
+
+	for range responses {
+		count++
+	}
+
+	assert.Equal(t, 100, count)
+	assert.ErrorIs(t, <-errs, talkative.ErrDecoding)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	responses, errs = client.CompletionStream(ctx, "", message)
+
+	<-responses
+	cancel()
+
+	for range responses {
+	}
+
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
@@ -1,18 +1,16 @@
 package talkative
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
+	"context"
+	"strings"
 	"time"
 )
 
 // ChatMessage struct represents a single message sent or received in the chat.
 type ChatMessage struct {
-	Role    Role   `json:"role"`    // Role of the sender (user or assistant).
-	Content string `json:"content"` // Content of the message.
+	Role    Role     `json:"role"`             // Role of the sender (user or assistant).
+	Content string   `json:"content"`          // Content of the message.
+	Images  []string `json:"images,omitempty"` // Base64 encoded images of the message, for multimodal models.
 }
 
 // CompletionParams represents the advanced parameters (Optional) to be supplied to the completion request.
@@ -22,6 +20,8 @@ type ChatParams struct {
 	Template  string                 `json:"template,omitempty"`   // The prompt template to use (overrides what is defined in the Modelfile)
 	Stream    *bool                  `json:"stream,omitempty"`     // Whether to get response in single shot rather than streaming
 	KeepAlive string                 `json:"keep_alive,omitempty"` // How long to keep the model will stay loaded into the memory. Default to 5m(inutes)
+
+	SkipDefaultSystem bool `json:"-"` // Whether to skip the default system prompt configured with WithDefaultSystemPrompt for this request
 }
 
 // Callback function type used for handling individual chat responses and errors.
@@ -42,10 +42,12 @@ type ChatRequest struct {
 
 // ChatResponse struct represents the response received from the Ollama API after processing chat messages.
 type ChatResponse struct {
-	Model       string      `json:"model"`      // The model used for processing.
-	Message     ChatMessage `json:"message"`    // The response message.
-	CreatedAt   time.Time   `json:"created_at"` // Time the response was created on the server.
-	Done        bool        `json:"done"`       // Indicates if processing is complete.
+	Model       string      `json:"model"`                 // The model used for processing.
+	Message     ChatMessage `json:"message"`               // The response message.
+	CreatedAt   time.Time   `json:"created_at"`            // Time the response was created on the server.
+	Done        bool        `json:"done"`                  // Indicates if processing is complete.
+	DoneReason  string      `json:"done_reason,omitempty"` // Why processing completed, i.e: "stop" or "length", only set on the final response.
+	Degraded    bool        `json:"-"`                     // Indicates the response is the fallback of an unavailable backend.
 	ChatMetrics             // The metrics associated about the chat
 }
 
@@ -56,6 +58,8 @@ type ChatMetrics struct {
 	PromptEvalDuration int `json:"prompt_eval_duration"` // Time spent on prompt evaluation (milliseconds).
 	EvalCount          int `json:"eval_count"`           // Number of overall evaluations performed.
 	EvalDuration       int `json:"eval_duration"`        // Time spent on overall evaluation (milliseconds).
+
+	Estimated bool `json:"-"` // Whether the metrics were estimated by the client, see WithMetricsBackfill.
 }
 
 // Initiates a chat process and asynchronously handles responses through a callback function.
@@ -63,7 +67,7 @@ type ChatMetrics struct {
 // This function takes model name, callback function (`cb`) and a variable number of messages (`msgs`) as arguments.
 // It performs the following steps:
 //  1. Validates the model, callback and message arguments.
-//  2. When model is empty, it uses the model to DEFAULT_MODEL to perform the operation.
+//  2. When model is empty, it uses the default model of the client to perform the operation, see WithDefaultModel.
 //  3. Prepares a request body with the messages and model information.
 //  4. Sends a POST request to the chat endpoint from this client.
 //  5. Handles the response status code and potential errors.
@@ -73,58 +77,90 @@ type ChatMetrics struct {
 // The callback function (`cb`) is responsible for handling individual chat responses and errors.
 // The completion channel (`<-chan bool`) allows the caller to track the progress of the chat process if needed.
 //
-// Note that the channel (`chDone`) is not explicitly closed in this example. However, the goroutine
-// running `processChat` terminates naturally after sending the completion signal (`true`),
-// effectively indicating no more data will be received on the channel.
+// The completion channel receives `true` once the chat is processed and is closed right after, so it can be
+// waited for by several receivers or in select statements, and the goroutine never blocks on it when the
+// caller stops listening.
 func (c *Client) Chat(model string, cb ChatCallBack, params *ChatParams, msgs ...ChatMessage) (<-chan bool, error) {
+	return c.ChatContext(context.Background(), model, cb, params, msgs...)
+}
+
+// ChatContext is identical to Chat(), except that the request is bound to the context.
+//
+// Cancelling the context aborts the request and its stream, the callback then receives the resulting error.
+func (c *Client) ChatContext(ctx context.Context, model string, cb ChatCallBack, params *ChatParams, msgs ...ChatMessage) (<-chan bool, error) {
+	return c.SendChat(ctx, ChatRequest{Model: model, Messages: msgs, ChatParams: params}, cb)
+}
+
+// SendChat sends the chat described by the request, bound to the context, and handles its responses through the
+// callback like ChatContext() does, which is a wrapper of it. The default model of the client is used when the
+// request has no model.
+func (c *Client) SendChat(ctx context.Context, request ChatRequest, cb ChatCallBack) (<-chan bool, error) {
 	if cb == nil {
 		return nil, ErrCallback
 	}
 
-	if len(msgs) == 0 {
+	if len(request.Messages) == 0 {
 		return nil, ErrMessage
 	}
 
-	if model == "" {
-		model = DEFAULT_MODEL
+	if request.Model == "" {
+		request.Model = c.DefaultModel()
 	}
 
-	request := ChatRequest{
-		Model:      model,
-		Messages:   msgs,
-		ChatParams: params,
-	}
-	body := &bytes.Buffer{}
+	held, err := c.prepareChat(ctx, &request)
 
-	if err := json.NewEncoder(body).Encode(request); err != nil {
-		return nil, fmt.Errorf("%w:%v", ErrEncoding, err)
+	if err != nil {
+		return nil, err
 	}
 
-	res, err := c.client.Post(c.urls["chat"], "application/json", body)
+	l := c.begin("chat", request.Model, c.urls["chat"])
+	c.release(held)
+
+	res, err := c.generate(ctx, l, c.urls["chat"], request)
 
 	if err != nil {
+		err = l.wrap(err)
+		l.abort(err)
+
+		if chDone, ok := c.degradedChat(ctx, &request, cb, err); ok {
+			return chDone, nil
+		}
+
 		return nil, err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		switch res.StatusCode {
-		case http.StatusBadRequest:
-			defer res.Body.Close()
+	chDone := make(chan bool, 1)
+
+	go func() {
+		defer c.guard("chat", func(err error) {
+			l.abort(err)
+			notify(cb, l.wrap(err))
+
+			signalDone(chDone)
+		})()
+
+		paced, wait := pace(c.pacing, cb, c.guard("chat", func(err error) {
+			l.fail(err)
+			notify(cb, l.wrap(err))
+		}))
+
+		prompt := func() string {
+			contents := make([]string, len(request.Messages))
 
-			body, _ := io.ReadAll(res.Body)
+			for i, msg := range request.Messages {
+				contents[i] = msg.Content
+			}
 
-			return nil, fmt.Errorf("%w\n%v", ErrBadRequest, body)
-		default:
-			return nil, fmt.Errorf("%w: please make sure ollama server is running and url is correct", ErrInvoke)
+			return strings.Join(contents, "\n")
 		}
-	}
 
-	chDone := make(chan bool)
+		watched := c.watch(res)
 
-	go func() {
-		StreamResponse(res.Body, cb)
+		DecodeStream(res.Body, stall(watched, settle(c, track(l, deskew(l, c.skewTolerance, estimate(c.backfill, l.start, prompt, paced))))), c.decode...)
+		wait()
+		l.complete()
 
-		chDone <- true
+		signalDone(chDone)
 	}()
 
 	return chDone, nil
@@ -133,6 +169,7 @@ func (c *Client) Chat(model string, cb ChatCallBack, params *ChatParams, msgs ..
 // Initiates a plain chat process and asynchronously handles responses through a callback function.
 //
 // This method is identical to Chat(), except that it invokes the callback with plain json string without further processing.
+// Lines received after the final response are ignored, see IgnoredChunks.
 func (c *Client) PlainChat(model string, cb PlainChatCallBack, params *ChatParams, msgs ...ChatMessage) (<-chan bool, error) {
 	if cb == nil {
 		return nil, ErrCallback
@@ -143,7 +180,7 @@ func (c *Client) PlainChat(model string, cb PlainChatCallBack, params *ChatParam
 	}
 
 	if model == "" {
-		model = DEFAULT_MODEL
+		model = c.DefaultModel()
 	}
 
 	request := ChatRequest{
@@ -152,38 +189,78 @@ func (c *Client) PlainChat(model string, cb PlainChatCallBack, params *ChatParam
 		ChatParams: params,
 	}
 
-	body := &bytes.Buffer{}
+	held, err := c.prepareChat(context.Background(), &request)
 
-	if err := json.NewEncoder(body).Encode(request); err != nil {
-		return nil, fmt.Errorf("%w:%v", ErrEncoding, err)
+	if err != nil {
+		return nil, err
 	}
 
-	res, err := c.client.Post(c.urls["chat"], "application/json", body)
+	l := c.begin("chat", request.Model, c.urls["chat"])
+	c.release(held)
+
+	res, err := c.generate(context.Background(), l, c.urls["chat"], request)
 
 	if err != nil {
+		err = l.wrap(err)
+		l.abort(err)
+
 		return nil, err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		switch res.StatusCode {
-		case http.StatusBadRequest:
-			defer res.Body.Close()
+	chDone := make(chan bool, 1)
 
-			body, _ := io.ReadAll(res.Body)
+	go func() {
+		defer c.guard("chat", func(err error) {
+			l.abort(err)
+			notify(cb, l.wrap(err))
 
-			return nil, fmt.Errorf("%w\n%v", ErrBadRequest, body)
-		default:
-			return nil, fmt.Errorf("%w: please make sure ollama server is running and url is correct", ErrInvoke)
-		}
-	}
+			signalDone(chDone)
+		})()
 
-	chDone := make(chan bool)
+		paced, wait := pace(c.pacing, cb, c.guard("chat", func(err error) {
+			l.fail(err)
+			notify(cb, l.wrap(err))
+		}))
 
-	go func() {
-		StreamPlainResponse(res.Body, cb)
+		watched := c.watch(res)
+
+		StreamPlainResponse(res.Body, stall(watched, settle(c, track(l, paced))))
+		wait()
+		l.complete()
 
-		chDone <- true
+		signalDone(chDone)
 	}()
 
 	return chDone, nil
 }
+
+// chatText sends the chat and waits for the complete response, returning its aggregated content.
+func (c *Client) chatText(model string, params *ChatParams, msgs ...ChatMessage) (string, error) {
+	return c.chatTextContext(context.Background(), model, params, msgs...)
+}
+
+// chatTextContext is identical to chatText(), except that the request is bound to the context.
+func (c *Client) chatTextContext(ctx context.Context, model string, params *ChatParams, msgs ...ChatMessage) (string, error) {
+	var (
+		sb        strings.Builder
+		streamErr error
+	)
+
+	done, err := c.ChatContext(ctx, model, func(cr *ChatResponse, err error) {
+		if err != nil {
+			streamErr = err
+
+			return
+		}
+
+		sb.WriteString(cr.Message.Content)
+	}, params, msgs...)
+
+	if err != nil {
+		return "", err
+	}
+
+	<-done
+
+	return sb.String(), streamErr
+}
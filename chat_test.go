@@ -1,6 +1,7 @@
 package talkative_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -445,3 +446,109 @@ func TestPlainChatResponse(t *testing.T) {
 func mockServer(handler http.HandlerFunc) *httptest.Server {
 	return httptest.NewServer(handler)
 }
+
+// streamServer is a helper function that creates a mock HTTP server streaming the given responses in NDJSON format.
+//
+// Every response is encoded on its own line and flushed immediately, mimicking the streaming behaviour of Ollama.
+//
+// Parameters:
+// - responses: The responses to be streamed to the client, in order.
+//
+// Returns:
+// - A pointer to an httptest.Server representing the mock server.
+func streamServer(responses ...any) *httptest.Server {
+	return mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/x-ndjson")
+
+		flusher, _ := w.(http.Flusher)
+		writer := json.NewEncoder(w)
+
+		for _, response := range responses {
+			if err := writer.Encode(response); err != nil {
+				return
+			}
+
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}))
+}
+
+// TestChatContext tests cancelling the context of a chat aborts the stream.
+func TestChatContext(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}})
+		w.(http.Flusher).Flush()
+
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var streamErr error
+
+	done, err := client.ChatContext(ctx, "", func(cr *talkative.ChatResponse, err error) {
+		if err != nil {
+			streamErr = err
+
+			return
+		}
+
+		cancel()
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+	}
+
+	<-done
+	assert.Error(t, streamErr)
+}
+
+// TestSendChat tests sending chats and completions described by request structs.
+func TestSendChat(t *testing.T) {
+	requests := make(chan map[string]any, 2)
+
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+
+		json.NewDecoder(r.Body).Decode(&request)
+		requests <- request
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Done: true})
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithDefaultModel("llama3"))
+
+	done, err := client.SendChat(context.Background(), talkative.ChatRequest{
+		Messages:   []talkative.ChatMessage{{Role: talkative.USER, Content: "Hi"}},
+		ChatParams: &talkative.ChatParams{Format: "json"},
+	}, func(cr *talkative.ChatResponse, err error) {})
+	{
+		assert.NoError(t, err)
+		<-done
+
+		request := <-requests
+		assert.Equal(t, "llama3", request["model"])
+		assert.Equal(t, "json", request["format"])
+	}
+
+	done, err = client.SendCompletion(context.Background(), talkative.CompletionRequest{Model: "phi3", Prompt: "Hi"}, func(cr *talkative.CompletionResponse, err error) {})
+	{
+		assert.NoError(t, err)
+		<-done
+
+		request := <-requests
+		assert.Equal(t, "phi3", request["model"])
+		assert.Equal(t, "Hi", request["prompt"])
+	}
+
+	_, err = client.SendChat(context.Background(), talkative.ChatRequest{}, func(cr *talkative.ChatResponse, err error) {})
+	assert.ErrorIs(t, err, talkative.ErrMessage)
+
+	_, err = client.SendCompletion(context.Background(), talkative.CompletionRequest{}, nil)
+	assert.ErrorIs(t, err, talkative.ErrCallback)
+}
@@ -0,0 +1,81 @@
+package talkative
+
+import "slices"
+
+// Clone derives a new client from this client with the options applied on top of its configuration,
+// i.e: to set the auth headers, default model or timeouts of a tenant.
+//
+// The clone shares the HTTP client, and thus the connections, of this client unless the options replace
+// its transport. Its headers, hooks, aliases, endpoints and features are copies, while event subscriptions,
+// in-flight request tracking and prompt prefix statistics start empty.
+//
+// Options failing like New does, i.e: with ErrTransport, make every request of the clone fail with their error.
+func (c *Client) Clone(opts ...Option) *Client {
+	clone := &Client{
+		base:               c.base,
+		fallbackURLs:       slices.Clip(c.fallbackURLs),
+		urls:               make(map[string]string, len(c.urls)),
+		client:             c.client,
+		headers:            c.headers.Clone(),
+		auth:               c.auth,
+		compression:        c.compression,
+		compressionMinSize: c.compressionMinSize,
+		chatHooks:          slices.Clip(c.chatHooks),
+		completionHooks:    slices.Clip(c.completionHooks),
+		pacing:             c.pacing,
+		skewTolerance:      c.skewTolerance,
+		backfill:           c.backfill,
+		defaultModel:       c.defaultModel,
+		ids:                c.ids,
+		fallback:           c.fallback,
+		panics:             c.panics,
+		requestTimeout:     c.requestTimeout,
+		streamIdleTimeout:  c.streamIdleTimeout,
+		stallTimeout:       c.stallTimeout,
+		loadingRetry:       c.loadingRetry,
+		socket:             c.socket,
+		decode:             slices.Clip(c.decode),
+		err:                c.err,
+	}
+
+	clone.compressionRejected.Store(c.compressionRejected.Load())
+
+	if c.prefix != nil {
+		clone.prefix = &promptPrefix{config: c.prefix.config}
+	}
+
+	if c.sizing != nil {
+		clone.sizing = &contextSizing{config: c.sizing.config}
+	}
+
+	for name, url := range c.urls {
+		clone.urls[name] = url
+	}
+
+	if c.features != nil {
+		clone.features = make(map[Feature]bool, len(c.features))
+
+		for feature, enabled := range c.features {
+			clone.features[feature] = enabled
+		}
+	}
+
+	c.models.mu.Lock()
+
+	for alias, model := range c.models.aliases {
+		clone.SetAlias(alias, model)
+	}
+
+	c.models.mu.Unlock()
+
+	for _, opt := range opts {
+		opt(clone)
+	}
+
+	// The shared HTTP client dials the socket already, unless the options replaced it or the socket.
+	if clone.socket != "" && (clone.client != c.client || clone.socket != c.socket) {
+		clone.dialSocket()
+	}
+
+	return clone
+}
@@ -0,0 +1,55 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClone tests deriving per-tenant clients sharing the HTTP client of their parent.
+func TestClone(t *testing.T) {
+	type received struct {
+		model, key, trace string
+	}
+
+	requests := make(chan received, 2)
+
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		requests <- received{request.Model, r.Header.Get("X-API-Key"), r.Header.Get("X-Trace")}
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Done: true})
+	})
+	defer server.Close()
+
+	transport := &roundTripper{}
+	parent, _ := talkative.New(server.URL,
+		talkative.WithHTTPClient(&http.Client{Transport: transport}),
+		talkative.WithHeaders(map[string]string{"X-API-Key": "parent", "X-Trace": "on"}),
+	)
+	parent.SetAlias("fast", "llama3.1")
+
+	tenant := parent.Clone(talkative.WithHeaders(map[string]string{"X-API-Key": "tenant"}), talkative.WithDefaultModel("mistral"))
+	tenant.SetAlias("fast", "phi3")
+
+	message := talkative.ChatMessage{Role: talkative.USER, Content: "Hi"}
+
+	for _, client := range []*talkative.Client{tenant, parent} {
+		done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+		assert.NoError(t, err)
+		<-done
+	}
+
+	assert.Equal(t, received{"mistral", "tenant", "on"}, <-requests)
+	assert.Equal(t, received{talkative.DEFAULT_MODEL, "parent", "on"}, <-requests)
+	assert.Len(t, transport.requests, 2)
+
+	model, _ := parent.Alias("fast")
+	assert.Equal(t, "llama3.1", model)
+}
@@ -0,0 +1,126 @@
+package talkative
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Define an enum-like type to represent the boundaries at which coalesced content is delivered.
+type CoalesceBoundary int
+
+const (
+	// The content is delivered up to the last complete word, including the whitespace following it.
+	COALESCE_WORD CoalesceBoundary = iota
+
+	// The content is delivered up to the last complete sentence or line, see HintOf.
+	COALESCE_SENTENCE
+
+	// The content is delivered once at least CoalesceOptions.Size bytes are buffered.
+	COALESCE_SIZE
+)
+
+// CoalesceOptions configures CoalesceChat and CoalesceCompletion.
+type CoalesceOptions struct {
+	Boundary CoalesceBoundary // The boundary at which the content is delivered, defaults to COALESCE_WORD.
+
+	// Size is the number of bytes after which the buffered content is delivered even without a boundary,
+	// i.e: a long line of code without spaces. It is required by COALESCE_SIZE, 0 disables it otherwise.
+	Size int
+}
+
+// CoalesceChat wraps the callback so the token-level deltas of the chat are buffered and delivered at word,
+// sentence or size boundaries, i.e: for terminals or text-to-speech engines stuttering on per-token chunks.
+//
+// Every delivered response is the response of the last buffered chunk, carrying the content buffered since
+// the previous delivery. Chunks which don't complete a boundary are not delivered. The final response is
+// always delivered with the remaining content, along with the metrics of the chat. When the stream fails,
+// the buffered content is delivered before the error.
+//
+// The wrapped callback keeps the state of a single stream, a new one must be created for every chat.
+func CoalesceChat(cb ChatCallBack, opts CoalesceOptions) ChatCallBack {
+	return coalesce(cb, opts)
+}
+
+// CoalesceCompletion wraps the callback so the token-level deltas of the completion are buffered and delivered
+// at word, sentence or size boundaries, like CoalesceChat() does.
+func CoalesceCompletion(cb CompletionCallback, opts CoalesceOptions) CompletionCallback {
+	return coalesce(cb, opts)
+}
+
+// coalesce wraps the callback so the content of the responses is buffered and delivered at the boundaries of opts.
+func coalesce[T any](cb func(*T, error), opts CoalesceOptions) func(*T, error) {
+	var (
+		pending string
+		last    *T
+	)
+
+	return func(response *T, err error) {
+		r, ok := any(response).(interface {
+			streamed
+			metered
+		})
+
+		if err != nil || !ok {
+			if last != nil && pending != "" {
+				*any(last).(streamed).delta() = pending
+				pending = ""
+
+				cb(last, nil)
+			}
+
+			cb(response, err)
+
+			return
+		}
+
+		content, done := r.chunk()
+		pending += content
+		last = response
+
+		if done {
+			*r.delta() = pending
+			pending = ""
+
+			cb(response, nil)
+
+			return
+		}
+
+		cut := boundary(pending, opts)
+
+		if cut == 0 {
+			return
+		}
+
+		*r.delta() = pending[:cut]
+		pending = pending[cut:]
+
+		cb(response, nil)
+	}
+}
+
+// boundary returns the length of the content to be delivered, 0 when the content must remain buffered.
+func boundary(content string, opts CoalesceOptions) int {
+	if opts.Size > 0 && len(content) >= opts.Size {
+		return len(content)
+	}
+
+	cut := 0
+
+	switch opts.Boundary {
+	case COALESCE_WORD:
+		for i, r := range content {
+			if unicode.IsSpace(r) {
+				cut = i + utf8.RuneLen(r)
+			}
+		}
+	case COALESCE_SENTENCE:
+		for i, r := range content {
+			if unicode.IsSpace(r) && (r == '\n' || HintOf(content[:i]) >= FLUSH_SENTENCE) {
+				cut = i + utf8.RuneLen(r)
+			}
+		}
+	}
+
+	return cut
+}
@@ -0,0 +1,88 @@
+package talkative_test
+
+import (
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCoalesceChat tests delivering the content of a chat at word boundaries.
+func TestCoalesceChat(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hel"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "lo wo"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "rld"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "!"}, Done: true, ChatMetrics: talkative.ChatMetrics{EvalCount: 4}},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	contents := []string{}
+	var final *talkative.ChatResponse
+
+	done, err := client.Chat("", talkative.CoalesceChat(func(cr *talkative.ChatResponse, err error) {
+		assert.NoError(t, err)
+
+		contents = append(contents, cr.Message.Content)
+		final = cr
+	}, talkative.CoalesceOptions{}), nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+		<-done
+		assert.Equal(t, []string{"Hello ", "world!"}, contents)
+		assert.True(t, final.Done)
+		assert.Equal(t, 4, final.EvalCount)
+	}
+}
+
+// TestCoalesceCompletion tests delivering the content of a completion at sentence and size boundaries.
+func TestCoalesceCompletion(t *testing.T) {
+	responses := []*talkative.CompletionResponse{
+		{Response: "Pi is 3."},
+		{Response: "14. It"},
+		{Response: " is irrational.\nNext"},
+		{Response: " line", Done: true},
+	}
+
+	run := func(opts talkative.CoalesceOptions) []string {
+		contents := []string{}
+
+		cb := talkative.CoalesceCompletion(func(cr *talkative.CompletionResponse, err error) {
+			contents = append(contents, cr.Response)
+		}, opts)
+
+		for _, response := range responses {
+			copied := *response
+			cb(&copied, nil)
+		}
+
+		return contents
+	}
+
+	assert.Equal(t, []string{"Pi is 3.14. ", "It is irrational.\n", "Next line"}, run(talkative.CoalesceOptions{Boundary: talkative.COALESCE_SENTENCE}))
+	assert.Equal(t, []string{"Pi is 3.14. It", " is irrational.\nNext", " line"}, run(talkative.CoalesceOptions{Boundary: talkative.COALESCE_SIZE, Size: 10}))
+}
+
+// TestCoalesceError tests delivering the buffered content before the error of a failing stream.
+func TestCoalesceError(t *testing.T) {
+	contents := []string{}
+	var streamErr error
+
+	cb := talkative.CoalesceCompletion(func(cr *talkative.CompletionResponse, err error) {
+		if err != nil {
+			streamErr = err
+
+			return
+		}
+
+		contents = append(contents, cr.Response)
+	}, talkative.CoalesceOptions{})
+
+	cb(&talkative.CompletionResponse{Response: "Hello wor"}, nil)
+	cb(nil, talkative.ErrDecoding)
+
+	assert.Equal(t, []string{"Hello ", "wor"}, contents)
+	assert.ErrorIs(t, streamErr, talkative.ErrDecoding)
+}
@@ -1,11 +1,7 @@
 package talkative
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
+	"context"
 )
 
 // CompletionRequest represents a request for completion.
@@ -40,10 +36,12 @@ type CompletionParams struct {
 //
 // It also embeds CompletionMetrics which includes upon completion
 type CompletionResponse struct {
-	Model     string `json:"model"`      // The model used for the completion.
-	Response  string `json:"response"`   // The generated response based on the prompt.
-	CreatedAt string `json:"created_at"` // The timestamp when the response was created.
-	Done      bool   `json:"done"`       // A boolean indicating if the completion process is finished.
+	Model      string `json:"model"`                 // The model used for the completion.
+	Response   string `json:"response"`              // The generated response based on the prompt.
+	CreatedAt  string `json:"created_at"`            // The timestamp when the response was created.
+	Done       bool   `json:"done"`                  // A boolean indicating if the completion process is finished.
+	DoneReason string `json:"done_reason,omitempty"` // Why the completion process finished, i.e: "stop" or "length", only set on the final response.
+	Degraded   bool   `json:"-"`                     // A boolean indicating the response is the fallback of an unavailable backend.
 
 	CompletionMetrics // embeds CompletionMetrics
 }
@@ -59,6 +57,8 @@ type CompletionMetrics struct {
 	EvalCount          int   `json:"eval_count"`           // Number of overall evaluations performed.
 	EvalDuration       int   `json:"eval_duration"`        // Time spent on overall evaluation (milliseconds).
 	Context            []int `json:"context"`              // Encoding of the conversation used in this response.
+
+	Estimated bool `json:"-"` // Whether the metrics were estimated by the client, see WithMetricsBackfill.
 }
 
 // CompletionCallback defines a function type that is used as a callback for handling completion responses.
@@ -95,6 +95,13 @@ type PlainCompletionCallback func(string, error)
 // It handles HTTP response status codes, specifically checking for a BadRequest (400) to return any server-side error messages.
 // Upon a successful request, it starts a goroutine to stream the response and invoke the provided callback function, signaling completion through the returned channel.
 func (c *Client) Completion(model string, cb CompletionCallback, msg *CompletionMessage) (<-chan bool, error) {
+	return c.CompletionContext(context.Background(), model, cb, msg)
+}
+
+// CompletionContext is identical to Completion(), except that the request is bound to the context.
+//
+// Cancelling the context aborts the request and its stream, the callback then receives the resulting error.
+func (c *Client) CompletionContext(ctx context.Context, model string, cb CompletionCallback, msg *CompletionMessage) (<-chan bool, error) {
 	if cb == nil {
 		return nil, ErrCallback
 	}
@@ -103,47 +110,78 @@ func (c *Client) Completion(model string, cb CompletionCallback, msg *Completion
 		return nil, ErrMessage
 	}
 
-	if model == "" {
-		model = DEFAULT_MODEL
-	}
-
-	request := CompletionRequest{
+	return c.SendCompletion(ctx, CompletionRequest{
 		Model:            model,
 		Prompt:           msg.Prompt,
 		Images:           msg.Images,
 		CompletionParams: msg.CompletionParams,
+	}, cb)
+}
+
+// SendCompletion sends the completion described by the request, bound to the context, and handles its responses
+// through the callback like CompletionContext() does, which is a wrapper of it. The default model of the client
+// is used when the request has no model.
+func (c *Client) SendCompletion(ctx context.Context, request CompletionRequest, cb CompletionCallback) (<-chan bool, error) {
+	if cb == nil {
+		return nil, ErrCallback
 	}
-	body := &bytes.Buffer{}
 
-	if err := json.NewEncoder(body).Encode(request); err != nil {
-		return nil, fmt.Errorf("%w:%v", ErrEncoding, err)
+	if request.Model == "" {
+		request.Model = c.DefaultModel()
 	}
 
-	res, err := c.client.Post(c.urls["completion"], "application/json", body)
+	held, err := c.prepareCompletion(ctx, &request)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		switch res.StatusCode {
-		case http.StatusBadRequest:
-			defer res.Body.Close()
+	l := c.begin("completion", request.Model, c.urls["completion"])
+	c.release(held)
+
+	res, err := c.generate(ctx, l, c.urls["completion"], request)
 
-			body, _ := io.ReadAll(res.Body)
+	if err != nil {
+		err = l.wrap(err)
+		l.abort(err)
 
-			return nil, fmt.Errorf("%w%s", ErrBadRequest, body)
-		default:
-			return nil, fmt.Errorf("%w: please make sure ollama server is running and url is correct", ErrInvoke)
+		if chDone, ok := c.degradedCompletion(ctx, &request, cb, err); ok {
+			return chDone, nil
 		}
+
+		return nil, err
 	}
 
 	chDone := make(chan bool, 1)
 
 	go func() {
-		StreamResponse(res.Body, cb)
+		defer c.guard("completion", func(err error) {
+			l.abort(err)
+			notify(cb, l.wrap(err))
+
+			signalDone(chDone)
+		})()
 
-		chDone <- true
+		paced, wait := pace(c.pacing, cb, c.guard("completion", func(err error) {
+			l.fail(err)
+			notify(cb, l.wrap(err))
+		}))
+
+		prompt := func() string {
+			if request.CompletionParams != nil {
+				return request.System + "\n" + request.Prompt
+			}
+
+			return request.Prompt
+		}
+
+		watched := c.watch(res)
+
+		DecodeStream(res.Body, stall(watched, settle(c, track(l, deskew(l, c.skewTolerance, estimate(c.backfill, l.start, prompt, paced))))), c.decode...)
+		wait()
+		l.complete()
+
+		signalDone(chDone)
 	}()
 
 	return chDone, nil
@@ -152,6 +190,7 @@ func (c *Client) Completion(model string, cb CompletionCallback, msg *Completion
 // Completion initiates a plain completion request to the server and returns a channel that signals when the operation is done.
 //
 // This method is identical to Completion(), except that it invokes the callback with plain json string without further processing.
+// Lines received after the final response are ignored, see IgnoredChunks.
 func (c *Client) PlainCompletion(model string, cb PlainCompletionCallback, msg *CompletionMessage) (<-chan bool, error) {
 	if cb == nil {
 		return nil, ErrCallback
@@ -162,7 +201,7 @@ func (c *Client) PlainCompletion(model string, cb PlainCompletionCallback, msg *
 	}
 
 	if model == "" {
-		model = DEFAULT_MODEL
+		model = c.DefaultModel()
 	}
 
 	request := CompletionRequest{
@@ -171,37 +210,47 @@ func (c *Client) PlainCompletion(model string, cb PlainCompletionCallback, msg *
 		Images:           msg.Images,
 		CompletionParams: msg.CompletionParams,
 	}
-	body := &bytes.Buffer{}
-
-	if err := json.NewEncoder(body).Encode(request); err != nil {
-		return nil, fmt.Errorf("%w:%v", ErrEncoding, err)
-	}
 
-	res, err := c.client.Post(c.urls["completion"], "application/json", body)
+	held, err := c.prepareCompletion(context.Background(), &request)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if res.StatusCode != http.StatusOK {
-		switch res.StatusCode {
-		case http.StatusBadRequest:
-			defer res.Body.Close()
+	l := c.begin("completion", request.Model, c.urls["completion"])
+	c.release(held)
 
-			body, _ := io.ReadAll(res.Body)
+	res, err := c.generate(context.Background(), l, c.urls["completion"], request)
 
-			return nil, fmt.Errorf("%w\n%v", ErrBadRequest, body)
-		default:
-			return nil, fmt.Errorf("%w: please make sure ollama server is running and url is correct", ErrInvoke)
-		}
+	if err != nil {
+		err = l.wrap(err)
+		l.abort(err)
+
+		return nil, err
 	}
 
 	chDone := make(chan bool, 1)
 
 	go func() {
-		StreamPlainResponse(res.Body, cb)
+		defer c.guard("completion", func(err error) {
+			l.abort(err)
+			notify(cb, l.wrap(err))
+
+			signalDone(chDone)
+		})()
+
+		paced, wait := pace(c.pacing, cb, c.guard("completion", func(err error) {
+			l.fail(err)
+			notify(cb, l.wrap(err))
+		}))
+
+		watched := c.watch(res)
+
+		StreamPlainResponse(res.Body, stall(watched, settle(c, track(l, paced))))
+		wait()
+		l.complete()
 
-		chDone <- true
+		signalDone(chDone)
 	}()
 
 	return chDone, nil
@@ -0,0 +1,266 @@
+package talkative
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrConversationNotFound is returned by conversation stores for unknown conversation IDs.
+var ErrConversationNotFound = newError("conversation not found")
+
+// Conversation represents a persisted chat conversation.
+type Conversation struct {
+	ID         string            `json:"id"`                   // The conversation ID.
+	Model      string            `json:"model"`                // The model used by the conversation.
+	Messages   []ChatMessage     `json:"messages"`             // The messages of the conversation, in order.
+	Metadata   map[string]string `json:"metadata,omitempty"`   // Arbitrary metadata of the conversation.
+	Owner      string            `json:"owner,omitempty"`      // The principal owning the conversation, enforced by ACLConversationStore.
+	ACL        map[string]Access `json:"acl,omitempty"`        // The access granted to other principals, enforced by ACLConversationStore.
+	Encryption *Encryption       `json:"encryption,omitempty"` // The envelope encryption details, set when the messages are encrypted.
+	Endpoint   string            `json:"endpoint,omitempty"`   // The base URL of the endpoint the conversation is pinned to, see Shards.Pin.
+	CreatedAt  time.Time         `json:"created_at"`           // Time the conversation was created.
+	UpdatedAt  time.Time         `json:"updated_at"`           // Time the conversation was last updated.
+	DeletedAt  *time.Time        `json:"deleted_at,omitempty"` // Time the conversation was soft-deleted by a RetentionConversationStore.
+}
+
+// Append adds the messages to the conversation.
+func (c *Conversation) Append(msgs ...ChatMessage) {
+	c.Messages = append(c.Messages, msgs...)
+	c.UpdatedAt = time.Now()
+}
+
+// clone returns a deep copy of the conversation, so stores do not share state with callers.
+func (c *Conversation) clone() *Conversation {
+	clone := *c
+	clone.Messages = append([]ChatMessage(nil), c.Messages...)
+
+	if c.Metadata != nil {
+		clone.Metadata = make(map[string]string, len(c.Metadata))
+
+		for key, value := range c.Metadata {
+			clone.Metadata[key] = value
+		}
+	}
+
+	if c.ACL != nil {
+		clone.ACL = make(map[string]Access, len(c.ACL))
+
+		for principal, access := range c.ACL {
+			clone.ACL[principal] = access
+		}
+	}
+
+	if c.DeletedAt != nil {
+		deleted := *c.DeletedAt
+		clone.DeletedAt = &deleted
+	}
+
+	if c.Encryption != nil {
+		encryption := *c.Encryption
+		clone.Encryption = &encryption
+	}
+
+	return &clone
+}
+
+// ConversationStore persists conversations. Implementations must be safe for concurrent use.
+type ConversationStore interface {
+	// Save adds the conversation, or replaces the conversation with the same ID.
+	Save(conversation *Conversation) error
+
+	// Load returns the conversation with the given ID, or ErrConversationNotFound.
+	Load(id string) (*Conversation, error)
+
+	// List returns all conversations ordered by creation time.
+	List() ([]*Conversation, error)
+
+	// Delete removes the conversation with the given ID, deleting a missing conversation is not an error.
+	Delete(id string) error
+}
+
+// MemoryConversationStore is a ConversationStore keeping the conversations in memory.
+type MemoryConversationStore struct {
+	mu            sync.RWMutex
+	conversations map[string]*Conversation
+}
+
+// NewMemoryConversationStore creates a new, empty MemoryConversationStore.
+func NewMemoryConversationStore() *MemoryConversationStore {
+	return &MemoryConversationStore{conversations: map[string]*Conversation{}}
+}
+
+// Save adds or replaces the conversation.
+func (s *MemoryConversationStore) Save(conversation *Conversation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conversations[conversation.ID] = conversation.clone()
+
+	return nil
+}
+
+// Load returns the conversation with the given ID.
+func (s *MemoryConversationStore) Load(id string) (*Conversation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	conversation, ok := s.conversations[id]
+
+	if !ok {
+		return nil, ErrConversationNotFound
+	}
+
+	return conversation.clone(), nil
+}
+
+// List returns all conversations ordered by creation time.
+func (s *MemoryConversationStore) List() ([]*Conversation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	conversations := make([]*Conversation, 0, len(s.conversations))
+
+	for _, conversation := range s.conversations {
+		conversations = append(conversations, conversation.clone())
+	}
+
+	sortConversations(conversations)
+
+	return conversations, nil
+}
+
+// Delete removes the conversation with the given ID.
+func (s *MemoryConversationStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.conversations, id)
+
+	return nil
+}
+
+// FileConversationStore is a durable ConversationStore keeping every conversation as a JSON file in a directory.
+type FileConversationStore struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+// NewFileConversationStore creates a new FileConversationStore in dir, creating the directory when missing.
+func NewFileConversationStore(dir string) (*FileConversationStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileConversationStore{dir: dir}, nil
+}
+
+// Save atomically writes the conversation to its file.
+func (s *FileConversationStore) Save(conversation *Conversation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(conversation)
+
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path(conversation.ID) + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path(conversation.ID))
+}
+
+// Load reads the conversation with the given ID.
+func (s *FileConversationStore) Load(id string) (*Conversation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.read(s.path(id))
+}
+
+// List reads all conversations ordered by creation time.
+func (s *FileConversationStore) List() ([]*Conversation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	conversations := make([]*Conversation, 0, len(paths))
+
+	for _, path := range paths {
+		conversation, err := s.read(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		conversations = append(conversations, conversation)
+	}
+
+	sortConversations(conversations)
+
+	return conversations, nil
+}
+
+// Delete removes the file of the conversation with the given ID.
+func (s *FileConversationStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// path returns the file path of the conversation with the given ID, named after the hash of the ID so
+// distinct IDs never share a file.
+func (s *FileConversationStore) path(id string) string {
+	sum := sha256.Sum256([]byte(id))
+
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// read decodes the conversation stored in the file at path.
+func (s *FileConversationStore) read(path string) (*Conversation, error) {
+	data, err := os.ReadFile(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrConversationNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	conversation := &Conversation{}
+
+	if err := json.Unmarshal(data, conversation); err != nil {
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+// sortConversations orders the conversations by creation time.
+func sortConversations(conversations []*Conversation) {
+	sort.SliceStable(conversations, func(i, j int) bool {
+		return conversations[i].CreatedAt.Before(conversations[j].CreatedAt)
+	})
+}
@@ -0,0 +1,75 @@
+package talkative_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConversationStore tests the memory and file based conversation stores.
+func TestConversationStore(t *testing.T) {
+	fileStore, err := talkative.NewFileConversationStore(t.TempDir())
+	{
+		assert.NoError(t, err)
+	}
+
+	stores := map[string]talkative.ConversationStore{
+		"memory": talkative.NewMemoryConversationStore(),
+		"file":   fileStore,
+	}
+
+	for name, store := range stores {
+		t.Run("conversation-store-"+name, func(t *testing.T) {
+			_, err := store.Load("missing")
+			assert.ErrorIs(t, err, talkative.ErrConversationNotFound)
+
+			first := &talkative.Conversation{ID: "first", Model: "llama3", CreatedAt: time.Now()}
+			first.Append(talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+
+			second := &talkative.Conversation{ID: "second", CreatedAt: first.CreatedAt.Add(time.Second)}
+
+			assert.NoError(t, store.Save(second))
+			assert.NoError(t, store.Save(first))
+
+			loaded, err := store.Load("first")
+			assert.NoError(t, err)
+			assert.Equal(t, first.Messages, loaded.Messages)
+
+			conversations, _ := store.List()
+			assert.Len(t, conversations, 2)
+			assert.Equal(t, "first", conversations[0].ID)
+
+			assert.NoError(t, store.Delete("first"))
+			assert.NoError(t, store.Delete("first"))
+
+			conversations, _ = store.List()
+			assert.Len(t, conversations, 1)
+		})
+	}
+}
+
+// TestFileConversationStoreCollision tests that IDs differing only by path separators are stored separately.
+func TestFileConversationStoreCollision(t *testing.T) {
+	store, err := talkative.NewFileConversationStore(t.TempDir())
+	{
+		assert.NoError(t, err)
+	}
+
+	ids := []string{"a/b", "a\\b", "a_b"}
+
+	for _, id := range ids {
+		assert.NoError(t, store.Save(&talkative.Conversation{ID: id, Model: id}))
+	}
+
+	for _, id := range ids {
+		loaded, err := store.Load(id)
+		assert.NoError(t, err)
+		assert.Equal(t, id, loaded.Model)
+	}
+
+	conversations, _ := store.List()
+	assert.Len(t, conversations, 3)
+}
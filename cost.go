@@ -0,0 +1,235 @@
+package talkative
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Rate represents the price of using a model.
+//
+// Token based and time based prices can be combined, the cost of a request is the sum of all of them.
+type Rate struct {
+	PromptToken float64 // Price of a single prompt token (prompt_eval_count).
+	EvalToken   float64 // Price of a single generated token (eval_count).
+	Second      float64 // Price of a second of total processing time (total_duration).
+}
+
+// Usage represents the resource usage, and its cost, of a single request.
+type Usage struct {
+	Model        string        // The model used by the request.
+	Tag          string        // The budget tag the usage is accounted to.
+	PromptTokens int           // Number of prompt tokens evaluated.
+	EvalTokens   int           // Number of tokens generated.
+	Duration     time.Duration // Total processing time reported by the server.
+	Cost         float64       // The computed cost of the request.
+}
+
+// CostModel computes the cost of requests from per model rates.
+//
+// A CostModel is safe for concurrent use.
+type CostModel struct {
+	mu       sync.RWMutex
+	rates    map[string]Rate
+	fallback *Rate
+}
+
+// NewCostModel creates a new CostModel with the given per model rates.
+func NewCostModel(rates map[string]Rate) *CostModel {
+	m := &CostModel{rates: map[string]Rate{}}
+
+	for model, rate := range rates {
+		m.rates[model] = rate
+	}
+
+	return m
+}
+
+// SetRate sets the rate of the given model.
+func (m *CostModel) SetRate(model string, rate Rate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rates[model] = rate
+}
+
+// SetDefaultRate sets the rate used for models without a specific rate.
+func (m *CostModel) SetDefaultRate(rate Rate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.fallback = &rate
+}
+
+// Rate returns the rate of the given model, the boolean reports whether a rate is configured.
+func (m *CostModel) Rate(model string) (Rate, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if rate, ok := m.rates[model]; ok {
+		return rate, true
+	}
+
+	if m.fallback != nil {
+		return *m.fallback, true
+	}
+
+	return Rate{}, false
+}
+
+// Cost computes the cost of the usage and returns it annotated with the cost.
+func (m *CostModel) Cost(usage Usage) Usage {
+	rate, _ := m.Rate(usage.Model)
+
+	usage.Cost = float64(usage.PromptTokens)*rate.PromptToken +
+		float64(usage.EvalTokens)*rate.EvalToken +
+		usage.Duration.Seconds()*rate.Second
+
+	return usage
+}
+
+// Chat computes the usage and cost of the final chat response.
+func (m *CostModel) Chat(cr *ChatResponse) Usage {
+	return m.Cost(chatUsage(cr))
+}
+
+// Completion computes the usage and cost of the final completion response.
+func (m *CostModel) Completion(cr *CompletionResponse) Usage {
+	return m.Cost(completionUsage(cr))
+}
+
+// chatUsage returns the usage of the final chat response, without cost.
+func chatUsage(cr *ChatResponse) Usage {
+	return Usage{
+		Model:        cr.Model,
+		PromptTokens: cr.PromptEvalCount,
+		EvalTokens:   cr.EvalCount,
+		Duration:     time.Duration(cr.TotalDuration),
+	}
+}
+
+// completionUsage returns the usage of the final completion response, without cost.
+func completionUsage(cr *CompletionResponse) Usage {
+	return Usage{
+		Model:        cr.Model,
+		PromptTokens: cr.PromptEvalCount,
+		EvalTokens:   cr.EvalCount,
+		Duration:     time.Duration(cr.TotalDuration),
+	}
+}
+
+// UsageReport aggregates the usage of a budget tag and model.
+type UsageReport struct {
+	Tag          string        // The budget tag.
+	Model        string        // The model.
+	Requests     int           // Number of requests.
+	PromptTokens int           // Total number of prompt tokens evaluated.
+	EvalTokens   int           // Total number of tokens generated.
+	Duration     time.Duration // Total processing time.
+	Cost         float64       // Total cost.
+}
+
+// Ledger accumulates usage per budget tag and model, i.e: for internal chargeback of shared GPU capacity.
+//
+// A Ledger is safe for concurrent use.
+type Ledger struct {
+	costs   *CostModel
+	mu      sync.Mutex
+	reports map[[2]string]*UsageReport
+}
+
+// NewLedger creates a new, empty Ledger pricing usage with the cost model, usage is recorded without cost
+// when the cost model is nil.
+func NewLedger(costs *CostModel) *Ledger {
+	return &Ledger{
+		costs:   costs,
+		reports: map[[2]string]*UsageReport{},
+	}
+}
+
+// Record accounts the usage to its tag and model, computing its cost when it has none.
+func (l *Ledger) Record(usage Usage) Usage {
+	if usage.Cost == 0 && l.costs != nil {
+		usage = l.costs.Cost(usage)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := [2]string{usage.Tag, usage.Model}
+	report, ok := l.reports[key]
+
+	if !ok {
+		report = &UsageReport{Tag: usage.Tag, Model: usage.Model}
+		l.reports[key] = report
+	}
+
+	report.Requests++
+	report.PromptTokens += usage.PromptTokens
+	report.EvalTokens += usage.EvalTokens
+	report.Duration += usage.Duration
+	report.Cost += usage.Cost
+
+	return usage
+}
+
+// Report returns the accumulated usage ordered by tag and model.
+func (l *Ledger) Report() []UsageReport {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	reports := make([]UsageReport, 0, len(l.reports))
+
+	for _, report := range l.reports {
+		reports = append(reports, *report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Tag != reports[j].Tag {
+			return reports[i].Tag < reports[j].Tag
+		}
+
+		return reports[i].Model < reports[j].Model
+	})
+
+	return reports
+}
+
+// TrackChat wraps the callback so the usage of the chat is recorded under the tag once the final response arrives.
+//
+// The optional onUsage function receives the priced usage of the request.
+func (l *Ledger) TrackChat(tag string, cb ChatCallBack, onUsage ...func(Usage)) ChatCallBack {
+	return func(cr *ChatResponse, err error) {
+		if err == nil && cr.Done {
+			usage := chatUsage(cr)
+			usage.Tag = tag
+
+			l.report(l.Record(usage), onUsage)
+		}
+
+		cb(cr, err)
+	}
+}
+
+// TrackCompletion wraps the callback so the usage of the completion is recorded under the tag once the final response arrives.
+//
+// The optional onUsage function receives the priced usage of the request.
+func (l *Ledger) TrackCompletion(tag string, cb CompletionCallback, onUsage ...func(Usage)) CompletionCallback {
+	return func(cr *CompletionResponse, err error) {
+		if err == nil && cr.Done {
+			usage := completionUsage(cr)
+			usage.Tag = tag
+
+			l.report(l.Record(usage), onUsage)
+		}
+
+		cb(cr, err)
+	}
+}
+
+// report hands the usage to the given handlers.
+func (l *Ledger) report(usage Usage, handlers []func(Usage)) {
+	for _, handler := range handlers {
+		handler(usage)
+	}
+}
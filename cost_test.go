@@ -0,0 +1,78 @@
+package talkative_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCostModel tests pricing of responses and the aggregation of usage per budget tag.
+func TestCostModel(t *testing.T) {
+	costs := talkative.NewCostModel(map[string]talkative.Rate{
+		"llama3": {PromptToken: 0.001, EvalToken: 0.002},
+	})
+	costs.SetDefaultRate(talkative.Rate{Second: 0.5})
+
+	usage := costs.Chat(&talkative.ChatResponse{
+		Model: "llama3",
+		Done:  true,
+		ChatMetrics: talkative.ChatMetrics{
+			PromptEvalCount: 100,
+			EvalCount:       50,
+		},
+	})
+
+	assert.InDelta(t, 0.2, usage.Cost, 1e-9)
+
+	usage = costs.Completion(&talkative.CompletionResponse{
+		Model: "mistral",
+		CompletionMetrics: talkative.CompletionMetrics{
+			TotalDuration: int(4 * time.Second),
+		},
+	})
+
+	assert.InDelta(t, 2.0, usage.Cost, 1e-9)
+
+	ledger := talkative.NewLedger(costs)
+	recorded := []talkative.Usage{}
+	cb := ledger.TrackChat("team-a", func(cr *talkative.ChatResponse, err error) {}, func(u talkative.Usage) {
+		recorded = append(recorded, u)
+	})
+
+	cb(&talkative.ChatResponse{Model: "llama3"}, nil)
+	cb(&talkative.ChatResponse{Model: "llama3", Done: true, ChatMetrics: talkative.ChatMetrics{EvalCount: 10}}, nil)
+	cb(&talkative.ChatResponse{Model: "llama3", Done: true, ChatMetrics: talkative.ChatMetrics{EvalCount: 20}}, nil)
+
+	assert.Len(t, recorded, 2)
+	assert.Equal(t, "team-a", recorded[0].Tag)
+
+	report := ledger.Report()
+
+	assert.Len(t, report, 1)
+	assert.Equal(t, 2, report[0].Requests)
+	assert.Equal(t, 30, report[0].EvalTokens)
+	assert.InDelta(t, 0.06, report[0].Cost, 1e-9)
+}
+
+// TestLedgerWithoutCostModel tests recording usage without cost when the ledger has no cost model.
+func TestLedgerWithoutCostModel(t *testing.T) {
+	ledger := talkative.NewLedger(nil)
+
+	chat := ledger.TrackChat("team-a", func(cr *talkative.ChatResponse, err error) {})
+	chat(&talkative.ChatResponse{Model: "llama3", Done: true, ChatMetrics: talkative.ChatMetrics{PromptEvalCount: 5, EvalCount: 10}}, nil)
+
+	completion := ledger.TrackCompletion("team-a", func(cr *talkative.CompletionResponse, err error) {})
+	completion(&talkative.CompletionResponse{Model: "llama3", Done: true, CompletionMetrics: talkative.CompletionMetrics{EvalCount: 20}}, nil)
+
+	report := ledger.Report()
+	{
+		assert.Len(t, report, 1)
+		assert.Equal(t, 2, report[0].Requests)
+		assert.Equal(t, 5, report[0].PromptTokens)
+		assert.Equal(t, 30, report[0].EvalTokens)
+		assert.Zero(t, report[0].Cost)
+	}
+}
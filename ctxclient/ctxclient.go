@@ -0,0 +1,107 @@
+// Package ctxclient is a context-first surface of the talkative Ollama client.
+//
+// Every request is bound to a context, described by a request struct and answered with a Stream
+// handle instead of a callback and a done channel. The package is built on top of SendChat and SendCompletion
+// of the talkative client, of which Chat, Completion and their variants are wrappers, so options, hooks, events
+// and aliases configured on it behave exactly the same:
+//
+//	client, _ := ctxclient.New("http://localhost:11434")
+//	stream, err := client.Chat(ctx, ctxclient.ChatRequest{Messages: messages})
+//	defer stream.Close()
+//
+//	for stream.Next() {
+//		fmt.Print(stream.Current().Message.Content)
+//	}
+//
+// Existing code can migrate incrementally, Unwrap returns the underlying talkative client.
+package ctxclient
+
+import (
+	"context"
+
+	"github.com/rifaideen/talkative"
+)
+
+// Aliases of the talkative types shared by both surfaces.
+type (
+	Role               = talkative.Role
+	Option             = talkative.Option
+	ChatMessage        = talkative.ChatMessage
+	ChatParams         = talkative.ChatParams
+	ChatResponse       = talkative.ChatResponse
+	CompletionParams   = talkative.CompletionParams
+	CompletionResponse = talkative.CompletionResponse
+	ChatRequest        = talkative.ChatRequest       // Describes a chat, the model defaults to the default model of the client.
+	CompletionRequest  = talkative.CompletionRequest // Describes a completion, the model defaults to the default model of the client.
+)
+
+// Roles of the chat messages.
+const (
+	USER      = talkative.USER
+	ASSISTANT = talkative.ASSISTANT
+	SYSTEM    = talkative.SYSTEM
+)
+
+// Client is a context-first client for the Ollama API.
+type Client struct {
+	client *talkative.Client
+}
+
+// New creates a new Client for the Ollama API at url, configured with the talkative options.
+func New(url string, opts ...Option) (*Client, error) {
+	client, err := talkative.New(url, opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{client: client}, nil
+}
+
+// Wrap creates a new Client on top of an existing talkative client.
+func Wrap(client *talkative.Client) *Client {
+	return &Client{client: client}
+}
+
+// Unwrap returns the underlying talkative client.
+func (c *Client) Unwrap() *talkative.Client {
+	return c.client
+}
+
+// Chat sends the chat request and returns a stream of its responses.
+//
+// The stream must be closed by the caller, cancelling the context aborts the request.
+func (c *Client) Chat(ctx context.Context, request ChatRequest) (*Stream[ChatResponse], error) {
+	ctx, stream := newStream[ChatResponse](ctx)
+
+	done, err := c.client.SendChat(ctx, request, stream.receive)
+
+	if err != nil {
+		stream.cancel()
+
+		return nil, err
+	}
+
+	go stream.wait(done)
+
+	return stream, nil
+}
+
+// Completion sends the completion request and returns a stream of its responses.
+//
+// The stream must be closed by the caller, cancelling the context aborts the request.
+func (c *Client) Completion(ctx context.Context, request CompletionRequest) (*Stream[CompletionResponse], error) {
+	ctx, stream := newStream[CompletionResponse](ctx)
+
+	done, err := c.client.SendCompletion(ctx, request, stream.receive)
+
+	if err != nil {
+		stream.cancel()
+
+		return nil, err
+	}
+
+	go stream.wait(done)
+
+	return stream, nil
+}
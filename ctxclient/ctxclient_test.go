@@ -0,0 +1,82 @@
+package ctxclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rifaideen/talkative/ctxclient"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChat tests consuming the responses of a chat through a stream.
+func TestChat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+
+		encoder.Encode(ctxclient.ChatResponse{Message: ctxclient.ChatMessage{Content: "Hello"}})
+		encoder.Encode(ctxclient.ChatResponse{Message: ctxclient.ChatMessage{Content: " world"}, Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := ctxclient.New(server.URL)
+
+	stream, err := client.Chat(context.Background(), ctxclient.ChatRequest{
+		Messages: []ctxclient.ChatMessage{{Role: ctxclient.USER, Content: "Hi"}},
+	})
+	{
+		assert.NoError(t, err)
+	}
+	defer stream.Close()
+
+	content := ""
+
+	for stream.Next() {
+		content += stream.Current().Message.Content
+	}
+
+	assert.NoError(t, stream.Err())
+	assert.Equal(t, "Hello world", content)
+}
+
+// TestCompletionClose tests closing a stream before it ended aborts the request.
+func TestCompletionClose(t *testing.T) {
+	aborted := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ctxclient.CompletionResponse{Response: "Once"})
+		w.(http.Flusher).Flush()
+
+		<-r.Context().Done()
+		close(aborted)
+	}))
+	defer server.Close()
+
+	client, _ := ctxclient.New(server.URL)
+
+	stream, err := client.Completion(context.Background(), ctxclient.CompletionRequest{Prompt: "Tell a story"})
+	{
+		assert.NoError(t, err)
+	}
+
+	assert.True(t, stream.Next())
+	assert.Equal(t, "Once", stream.Current().Response)
+	assert.NoError(t, stream.Close())
+	assert.False(t, stream.Next())
+	assert.NoError(t, stream.Err())
+
+	<-aborted
+}
+
+// TestChatValidation tests the errors of invalid requests being returned immediately.
+func TestChatValidation(t *testing.T) {
+	client, _ := ctxclient.New("http://localhost:11434")
+
+	_, err := client.Chat(context.Background(), ctxclient.ChatRequest{})
+	{
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,97 @@
+package ctxclient
+
+import (
+	"context"
+	"errors"
+)
+
+// Stream is a handle over the responses of a streaming request.
+//
+// Responses are consumed with Next and Current, the error which ended the stream (if any) is
+// reported by Err once Next returns false. A Stream is meant to be consumed by a single goroutine.
+type Stream[T any] struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	items   chan *T
+	current *T
+	err     error
+	closed  bool
+}
+
+// newStream creates a new stream bound to a cancellable child of ctx, which is returned for the request.
+func newStream[T any](ctx context.Context) (context.Context, *Stream[T]) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return ctx, &Stream[T]{ctx: ctx, cancel: cancel, items: make(chan *T)}
+}
+
+// Next advances the stream to the next response, returning false once the stream ended.
+func (s *Stream[T]) Next() bool {
+	item, ok := <-s.items
+
+	if !ok {
+		s.current = nil
+
+		return false
+	}
+
+	s.current = item
+
+	return true
+}
+
+// Current returns the response the stream was advanced to by Next.
+func (s *Stream[T]) Current() *T {
+	return s.current
+}
+
+// Err returns the error which ended the stream, nil when the stream ended normally or was closed.
+//
+// It must only be called once Next returned false.
+func (s *Stream[T]) Err() error {
+	if s.closed && errors.Is(s.err, context.Canceled) {
+		return nil
+	}
+
+	return s.err
+}
+
+// Close aborts the request if it is still running and releases its resources.
+func (s *Stream[T]) Close() error {
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+	s.cancel()
+
+	for range s.items {
+	}
+
+	return nil
+}
+
+// receive is the callback of the underlying talkative request.
+func (s *Stream[T]) receive(item *T, err error) {
+	if err != nil {
+		if s.ctx.Err() != nil {
+			err = s.ctx.Err()
+		}
+
+		s.err = err
+
+		return
+	}
+
+	select {
+	case s.items <- item:
+	case <-s.ctx.Done():
+	}
+}
+
+// wait closes the stream and releases its context once the underlying talkative request is done.
+func (s *Stream[T]) wait(done <-chan bool) {
+	<-done
+	close(s.items)
+	s.cancel()
+}
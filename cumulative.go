@@ -0,0 +1,48 @@
+package talkative
+
+import "strings"
+
+// CumulativeChat wraps the callback so every response carries the full content generated so far in its
+// message instead of the delta of its chunk, i.e: for UI frameworks re-rendering the whole message.
+//
+// The wrapped callback keeps the state of a single stream, a new one must be created for every chat.
+func CumulativeChat(cb ChatCallBack) ChatCallBack {
+	return cumulative(cb)
+}
+
+// CumulativeCompletion wraps the callback so every response carries the full response generated so far
+// instead of the delta of its chunk, like CumulativeChat() does.
+func CumulativeCompletion(cb CompletionCallback) CompletionCallback {
+	return cumulative(cb)
+}
+
+// streamed is implemented by the responses whose content is streamed in deltas.
+type streamed interface {
+	delta() *string
+}
+
+// delta returns the content of the chat response.
+func (r *ChatResponse) delta() *string {
+	return &r.Message.Content
+}
+
+// delta returns the content of the completion response.
+func (r *CompletionResponse) delta() *string {
+	return &r.Response
+}
+
+// cumulative wraps the callback so the content of every response is replaced with the content accumulated so far.
+func cumulative[T any](cb func(*T, error)) func(*T, error) {
+	var sb strings.Builder
+
+	return func(response *T, err error) {
+		if r, ok := any(response).(streamed); ok && err == nil {
+			content := r.delta()
+
+			sb.WriteString(*content)
+			*content = sb.String()
+		}
+
+		cb(response, err)
+	}
+}
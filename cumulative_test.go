@@ -0,0 +1,54 @@
+package talkative_test
+
+import (
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCumulativeChat tests receiving the full content generated so far with every chat response.
+func TestCumulativeChat(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: " world"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "!"}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	contents := []string{}
+
+	done, err := client.Chat("", talkative.CumulativeChat(func(cr *talkative.ChatResponse, err error) {
+		assert.NoError(t, err)
+
+		contents = append(contents, cr.Message.Content)
+	}), nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+		<-done
+		assert.Equal(t, []string{"Hello", "Hello world", "Hello world!"}, contents)
+	}
+}
+
+// TestCumulativeCompletion tests receiving the full response generated so far with every completion response.
+func TestCumulativeCompletion(t *testing.T) {
+	server := streamServer(
+		talkative.CompletionResponse{Response: "Hello"},
+		talkative.CompletionResponse{Response: "!", Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	contents := []string{}
+
+	done, err := client.Completion("", talkative.CumulativeCompletion(func(cr *talkative.CompletionResponse, err error) {
+		contents = append(contents, cr.Response)
+	}), &talkative.CompletionMessage{Prompt: "Hi"})
+	{
+		assert.NoError(t, err)
+		<-done
+		assert.Equal(t, []string{"Hello", "Hello!"}, contents)
+	}
+}
@@ -0,0 +1,201 @@
+package talkative
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// DecodeOption configures how DecodeStream decodes the messages of a stream.
+type DecodeOption func(*decodeOptions)
+
+// decodeOptions holds the configuration applied by DecodeStream.
+type decodeOptions struct {
+	strict    bool                                    // Fail on fields unknown to the target type.
+	useNumber bool                                    // Decode numbers into json.Number instead of float64.
+	unknown   func(fields map[string]json.RawMessage) // Receives the fields unknown to the target type.
+}
+
+// Strict makes the decoder fail with ErrDecoding when a message contains fields unknown to the target type.
+func Strict() DecodeOption {
+	return func(o *decodeOptions) {
+		o.strict = true
+	}
+}
+
+// UseNumber makes the decoder decode numbers held in interface{} values as json.Number instead of float64.
+func UseNumber() DecodeOption {
+	return func(o *decodeOptions) {
+		o.useNumber = true
+	}
+}
+
+// CaptureUnknown invokes fn with the fields of every message which are unknown to the target type.
+//
+// This is useful for gateways or newer server versions adding fields not yet modeled by this package.
+// The function is not invoked for messages without unknown fields.
+func CaptureUnknown(fn func(fields map[string]json.RawMessage)) DecodeOption {
+	return func(o *decodeOptions) {
+		o.unknown = fn
+	}
+}
+
+// WithDecodeOptions decodes the responses of chats, completions and Invoke with the options, i.e: to fail on
+// fields unknown to the response types or to capture them. The responses are decoded before they reach the
+// hooks, pacing and lifecycle tracking of the client, which thus apply to them unchanged.
+func WithDecodeOptions(opts ...DecodeOption) Option {
+	return func(c *Client) {
+		c.decode = append(c.decode, opts...)
+	}
+}
+
+// DecodeStream decodes a stream of JSON messages from body into values of type T, invoking cb for every message.
+//
+// It is the configurable counterpart of StreamResponse and can be used to consume any streaming endpoint,
+// including custom ones, with the same semantics: the callback is invoked with the error and processing stops
+// when a message cannot be decoded, and the body is closed before returning.
+func DecodeStream[T any](body io.ReadCloser, cb func(*T, error), opts ...DecodeOption) {
+	defer body.Close()
+
+	options := newDecodeOptions(opts)
+	decoder := options.decoder(body)
+
+	for {
+		response, err := decodeMessage[T](decoder, options)
+
+		if err == io.EOF {
+			return
+		}
+
+		if err != nil {
+			cb(nil, fmt.Errorf("%w: %w", ErrDecoding, err))
+
+			return
+		}
+
+		cb(response, nil)
+	}
+}
+
+// newDecodeOptions applies the options.
+func newDecodeOptions(opts []DecodeOption) *decodeOptions {
+	options := &decodeOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// decoder returns a JSON decoder of r configured with the options.
+func (o *decodeOptions) decoder(r io.Reader) *json.Decoder {
+	decoder := json.NewDecoder(r)
+
+	if o.strict {
+		decoder.DisallowUnknownFields()
+	}
+
+	if o.useNumber {
+		decoder.UseNumber()
+	}
+
+	return decoder
+}
+
+// decodeMessage decodes the next message of the stream into T.
+func decodeMessage[T any](decoder *json.Decoder, options *decodeOptions) (*T, error) {
+	var response T
+
+	if err := decodeValue(decoder, options, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// decodeValue decodes the next message of the stream into value, which must be a pointer.
+//
+// Messages are decoded straight from the stream, only their raw form is kept when the unknown fields are captured.
+func decodeValue(decoder *json.Decoder, options *decodeOptions, value any) error {
+	if options.unknown == nil {
+		return decoder.Decode(value)
+	}
+
+	var raw json.RawMessage
+
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	if err := options.decoder(bytes.NewReader(raw)).Decode(value); err != nil {
+		return err
+	}
+
+	if fields := unknownFields(raw, reflect.TypeOf(value)); len(fields) > 0 {
+		options.unknown(fields)
+	}
+
+	return nil
+}
+
+// unknownFields returns the top level fields of raw which are not mapped by the given type.
+func unknownFields(raw json.RawMessage, typ reflect.Type) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil
+	}
+
+	known := map[string]bool{}
+	jsonFields(typ, known)
+
+	for name := range fields {
+		if known[strings.ToLower(name)] {
+			delete(fields, name)
+		}
+	}
+
+	return fields
+}
+
+// jsonFields collects the lower cased JSON names of the fields of typ, including promoted fields of embedded structs.
+func jsonFields(typ reflect.Type, known map[string]bool) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			jsonFields(field.Type, known)
+
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		known[strings.ToLower(name)] = true
+	}
+}
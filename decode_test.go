@@ -0,0 +1,129 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDecodeStream tests decoding of a stream with the different decode options.
+func TestDecodeStream(t *testing.T) {
+	stream := `{"model":"llama2","message":{"role":"assistant","content":"Hi"},"done":false,"done_reason":"","extra":1}
+{"model":"llama2","message":{"role":"assistant","content":"!"},"done":true,"eval_count":2}
+`
+
+	t.Run("decode-lenient", func(t *testing.T) {
+		content := ""
+
+		talkative.DecodeStream(io.NopCloser(strings.NewReader(stream)), func(cr *talkative.ChatResponse, err error) {
+			assert.NoError(t, err)
+
+			content += cr.Message.Content
+		})
+
+		assert.Equal(t, "Hi!", content)
+	})
+
+	t.Run("decode-strict", func(t *testing.T) {
+		var errs []error
+
+		talkative.DecodeStream(io.NopCloser(strings.NewReader(stream)), func(cr *talkative.ChatResponse, err error) {
+			errs = append(errs, err)
+		}, talkative.Strict())
+
+		assert.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], talkative.ErrDecoding)
+	})
+
+	t.Run("decode-capture-unknown", func(t *testing.T) {
+		var captured []map[string]json.RawMessage
+
+		talkative.DecodeStream(io.NopCloser(strings.NewReader(stream)), func(cr *talkative.ChatResponse, err error) {
+			assert.NoError(t, err)
+		}, talkative.CaptureUnknown(func(fields map[string]json.RawMessage) {
+			captured = append(captured, fields)
+		}))
+
+		// eval_count is promoted from the embedded ChatMetrics and must not be reported
+		assert.Len(t, captured, 1)
+		assert.Contains(t, captured[0], "extra")
+		assert.NotContains(t, captured[0], "done_reason")
+	})
+
+	t.Run("decode-truncated", func(t *testing.T) {
+		var errs []error
+
+		talkative.DecodeStream(io.NopCloser(strings.NewReader(stream[:40])), func(cr *talkative.ChatResponse, err error) {
+			errs = append(errs, err)
+		})
+
+		assert.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], talkative.ErrDecoding)
+		assert.ErrorIs(t, errs[0], io.ErrUnexpectedEOF)
+	})
+
+	t.Run("decode-strict-number", func(t *testing.T) {
+		var decoded []map[string]any
+
+		talkative.DecodeStream(io.NopCloser(strings.NewReader(`{"a":1}
+{"a":2.5}
+`)), func(m *map[string]any, err error) {
+			assert.NoError(t, err)
+
+			decoded = append(decoded, *m)
+		}, talkative.Strict(), talkative.UseNumber())
+
+		assert.Equal(t, []map[string]any{{"a": json.Number("1")}, {"a": json.Number("2.5")}}, decoded)
+	})
+}
+
+// TestWithDecodeOptions tests decoding the responses of chats and Invoke with the decode options of the client.
+func TestWithDecodeOptions(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"message": {"role": "assistant", "content": "Hi"}, "done": true, "gateway": "eu-1"}` + "\n"))
+	})
+	defer server.Close()
+
+	var unknown map[string]json.RawMessage
+
+	client, _ := talkative.New(server.URL, talkative.WithDecodeOptions(talkative.CaptureUnknown(func(fields map[string]json.RawMessage) {
+		unknown = fields
+	})))
+
+	content := ""
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {
+		assert.NoError(t, err)
+		content += cr.Message.Content
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+	}
+
+	<-done
+	assert.Equal(t, "Hi", content)
+	assert.Equal(t, map[string]json.RawMessage{"gateway": json.RawMessage(`"eu-1"`)}, unknown)
+
+	strict := client.Clone(talkative.WithDecodeOptions(talkative.Strict()))
+
+	var streamErr error
+
+	done, _ = strict.Chat("", func(cr *talkative.ChatResponse, err error) {
+		streamErr = err
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+
+	<-done
+	assert.ErrorIs(t, streamErr, talkative.ErrDecoding)
+
+	var response talkative.ChatResponse
+
+	assert.NoError(t, client.Invoke(context.Background(), "/api/chat", nil, &response))
+	assert.ErrorIs(t, client.Invoke(context.Background(), "/api/chat", nil, &response, talkative.Strict()), talkative.ErrDecoding)
+}
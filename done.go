@@ -0,0 +1,105 @@
+package talkative
+
+import (
+	"context"
+	"errors"
+)
+
+// ChatDone is identical to ChatContext(), except that the returned channel delivers the error which ended
+// the stream, or nil when it completed successfully, instead of true. The channel is closed afterwards.
+//
+// The callback still receives every error, the channel allows telling success from failure after the
+// stream ended, i.e: a decoding failure in the middle of the stream:
+//
+//	done, err := client.ChatDone(ctx, model, cb, nil, msgs...)
+//	...
+//	if err := <-done; err != nil {
+//		...
+//	}
+func (c *Client) ChatDone(ctx context.Context, model string, cb ChatCallBack, params *ChatParams, msgs ...ChatMessage) (<-chan error, error) {
+	if cb == nil {
+		return nil, ErrCallback
+	}
+
+	wrapped, terminate := terminal(ctx, cb)
+	done, err := c.ChatContext(ctx, model, wrapped, params, msgs...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return terminate(done), nil
+}
+
+// CompletionDone is identical to CompletionContext(), except that the returned channel delivers the error
+// which ended the stream, or nil when it completed successfully, like ChatDone() does.
+func (c *Client) CompletionDone(ctx context.Context, model string, cb CompletionCallback, msg *CompletionMessage) (<-chan error, error) {
+	if cb == nil {
+		return nil, ErrCallback
+	}
+
+	wrapped, terminate := terminal(ctx, cb)
+	done, err := c.CompletionContext(ctx, model, wrapped, msg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return terminate(done), nil
+}
+
+// terminal wraps the callback to record the first error of the stream, and returns a function turning the
+// done channel of the stream into a channel delivering that error.
+//
+// Errors caused by the cancellation of the context are reported as its cause, see context.Cause. Other errors
+// occurring once the context is done are joined with the cause, so neither is lost.
+func terminal[T any](ctx context.Context, cb func(*T, error)) (func(*T, error), func(<-chan bool) <-chan error) {
+	var first error
+
+	wrapped := func(response *T, err error) {
+		if err != nil && ctx.Err() != nil {
+			err = withCause(ctx, err)
+		}
+
+		if err != nil && first == nil {
+			first = err
+		}
+
+		cb(response, err)
+	}
+
+	return wrapped, func(done <-chan bool) <-chan error {
+		errs := make(chan error, 1)
+
+		go func() {
+			<-done
+
+			errs <- first
+			close(errs)
+		}()
+
+		return errs
+	}
+}
+
+// withCause returns the cause of the done context in place of the error when the error results from the
+// cancellation, or both errors joined otherwise.
+func withCause(ctx context.Context, err error) error {
+	cause := context.Cause(ctx)
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return cause
+	}
+
+	if errors.Is(err, cause) {
+		return err
+	}
+
+	return errors.Join(err, cause)
+}
+
+// signalDone signals the end of a stream on its done channel, which must be buffered, and closes it.
+func signalDone(chDone chan bool) {
+	chDone <- true
+	close(chDone)
+}
@@ -0,0 +1,125 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChatDone tests receiving the outcome of a chat from its done channel.
+func TestChatDone(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "!"}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	content := ""
+
+	done, err := client.ChatDone(context.Background(), "", func(cr *talkative.ChatResponse, err error) {
+		if err == nil {
+			content += cr.Message.Content
+		}
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+		assert.NoError(t, <-done)
+		assert.Equal(t, "Hello!", content)
+
+		_, ok := <-done
+		assert.False(t, ok)
+	}
+
+	_, err = client.ChatDone(context.Background(), "", nil, nil)
+	assert.ErrorIs(t, err, talkative.ErrCallback)
+
+	_, err = client.ChatDone(context.Background(), "", func(cr *talkative.ChatResponse, err error) {}, nil)
+	assert.ErrorIs(t, err, talkative.ErrMessage)
+}
+
+// TestCompletionDone tests receiving the error of a stream failing midway from its done channel.
+func TestCompletionDone(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(talkative.CompletionResponse{Response: "Hello"})
+		w.Write([]byte("{invalid"))
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	errs := 0
+
+	done, err := client.CompletionDone(context.Background(), "", func(cr *talkative.CompletionResponse, err error) {
+		if err != nil {
+			errs++
+		}
+	}, &talkative.CompletionMessage{Prompt: "Hi"})
+	{
+		assert.NoError(t, err)
+		assert.ErrorIs(t, <-done, talkative.ErrDecoding)
+		assert.Equal(t, 1, errs)
+	}
+}
+
+// TestDoneClosed tests the done channel is closed after signalling the end of the stream.
+func TestDoneClosed(t *testing.T) {
+	server := streamServer(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}, Done: true})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+		assert.True(t, <-done)
+
+		_, open := <-done
+		assert.False(t, open)
+	}
+
+	// Nobody listening doesn't keep the stream from completing, the channel is readable afterwards.
+	done, _ = client.Completion("", func(cr *talkative.CompletionResponse, err error) {}, &talkative.CompletionMessage{Prompt: "Hi"})
+
+	assert.Eventually(t, func() bool {
+		return len(done) == 1
+	}, time.Second, time.Millisecond)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("done channel was not signalled")
+	}
+}
+
+// TestDoneCancelledError tests that errors other than the cancellation, occurring once the context is done,
+// are reported along with the cause of the cancellation.
+func TestDoneCancelledError(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{\"response\": \"Hello\"}\n{invalid\n"))
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	done, err := client.CompletionDone(ctx, "", func(cr *talkative.CompletionResponse, err error) {
+		if err == nil {
+			cancel(assert.AnError)
+		}
+	}, &talkative.CompletionMessage{Prompt: "Hi"})
+	{
+		assert.NoError(t, err)
+
+		err := <-done
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorIs(t, err, talkative.ErrDecoding)
+	}
+}
@@ -0,0 +1,79 @@
+package talkative
+
+import "context"
+
+// EmbeddingsParams represents the advanced parameters (Optional) to be supplied to the embeddings request.
+type EmbeddingsParams struct {
+	Truncate  *bool                  `json:"truncate,omitempty"`   // Whether to truncate inputs exceeding the context length, defaults to true on the server
+	Options   map[string]interface{} `json:"options,omitempty"`    // The additional model parameters listed in the Modelfile documentation
+	KeepAlive string                 `json:"keep_alive,omitempty"` // How long to keep the model will stay loaded into the memory. Default to 5m(inutes)
+}
+
+// EmbeddingsRequest represents the request body sent to the embed endpoint.
+type EmbeddingsRequest struct {
+	Model string   `json:"model"` // The model used to generate the embeddings.
+	Input []string `json:"input"` // The texts to embed.
+
+	*EmbeddingsParams `json:",omitempty"` // The additional parameters for the embeddings
+}
+
+// EmbeddingsResponse represents the response of the embed endpoint.
+type EmbeddingsResponse struct {
+	Model      string      `json:"model"`      // The model used to generate the embeddings.
+	Embeddings [][]float64 `json:"embeddings"` // The embeddings, one per input in the same order.
+
+	TotalDuration   int `json:"total_duration"`    // Total processing time in nanoseconds.
+	LoadDuration    int `json:"load_duration"`     // Time spent loading the model in nanoseconds.
+	PromptEvalCount int `json:"prompt_eval_count"` // Number of tokens of the inputs.
+}
+
+// Embeddings generates the embeddings of the inputs with the model.
+//
+// When model is empty, the default model of the client is used.
+func (c *Client) Embeddings(model string, input ...string) (*EmbeddingsResponse, error) {
+	return c.EmbeddingsContext(context.Background(), model, nil, input...)
+}
+
+// EmbeddingsContext is identical to Embeddings(), except that the request is bound to the context
+// and accepts additional parameters.
+func (c *Client) EmbeddingsContext(ctx context.Context, model string, params *EmbeddingsParams, input ...string) (*EmbeddingsResponse, error) {
+	if len(input) == 0 {
+		return nil, ErrMessage
+	}
+
+	if model == "" {
+		model = c.DefaultModel()
+	}
+
+	held := c.hold(model)
+	request := EmbeddingsRequest{
+		Model:            held,
+		Input:            input,
+		EmbeddingsParams: params,
+	}
+
+	l := c.begin("embeddings", request.Model, c.urls["embed"])
+	c.release(held)
+
+	res, err := c.generate(ctx, l, c.urls["embed"], request)
+
+	if err != nil {
+		err = l.wrap(err)
+		l.abort(err)
+
+		return nil, err
+	}
+
+	response, err := decode[EmbeddingsResponse](res)
+
+	if err != nil {
+		err = l.wrap(err)
+		l.abort(err)
+
+		return nil, err
+	}
+
+	l.complete()
+
+	return response, nil
+}
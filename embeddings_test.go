@@ -0,0 +1,45 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEmbeddings tests generating the embeddings of several inputs.
+func TestEmbeddings(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.EmbeddingsRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		assert.Equal(t, "/api/embed", r.URL.Path)
+		assert.Equal(t, "all-minilm", request.Model)
+		assert.Equal(t, []string{"Why is the sky blue?", "Why is the grass green?"}, request.Input)
+
+		json.NewEncoder(w).Encode(talkative.EmbeddingsResponse{
+			Model:           request.Model,
+			Embeddings:      [][]float64{{0.1, -0.2}, {0.3, 0.4}},
+			PromptEvalCount: 12,
+		})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	response, err := client.Embeddings("all-minilm", "Why is the sky blue?", "Why is the grass green?")
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{0.1, -0.2}, {0.3, 0.4}}, response.Embeddings)
+		assert.Equal(t, 12, response.PromptEvalCount)
+	}
+
+	_, err = client.Embeddings("all-minilm")
+	{
+		assert.ErrorIs(t, err, talkative.ErrMessage)
+	}
+}
@@ -0,0 +1,330 @@
+package talkative
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// ENCRYPTION_AES_GCM identifies message contents encrypted with AES-256-GCM.
+const ENCRYPTION_AES_GCM = "AES-256-GCM"
+
+// Pre-defined errors used by the conversation encryption.
+var (
+	ErrEncrypt = newError("unable to encrypt") // Error for failing to encrypt conversation content.
+	ErrDecrypt = newError("unable to decrypt") // Error for failing to decrypt conversation content.
+)
+
+// Encryption holds the envelope encryption details of a conversation.
+type Encryption struct {
+	Algorithm  string `json:"algorithm"`   // The algorithm used to encrypt the message contents.
+	KeyID      string `json:"key_id"`      // The ID of the key encryption key which wrapped the data key.
+	WrappedKey []byte `json:"wrapped_key"` // The data key, encrypted with the key encryption key.
+}
+
+// KeyManager wraps and unwraps data keys with a key encryption key, i.e: backed by a cloud KMS.
+type KeyManager interface {
+	// WrapKey encrypts the data key, returning the wrapped key and the ID of the key encryption key used.
+	WrapKey(dataKey []byte) (wrapped []byte, keyID string, err error)
+
+	// UnwrapKey decrypts a data key previously wrapped with the key encryption key identified by keyID.
+	UnwrapKey(wrapped []byte, keyID string) ([]byte, error)
+}
+
+// AESKeyManager is a KeyManager wrapping data keys locally with AES-GCM master keys.
+//
+// It supports key rotation: new data keys are wrapped with the current key while previously
+// wrapped keys can still be unwrapped with any of the retired keys. It is safe for concurrent use.
+type AESKeyManager struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewAESKeyManager creates a new AESKeyManager wrapping data keys with the given 16, 24 or 32 bytes master key.
+func NewAESKeyManager(keyID string, masterKey []byte) (*AESKeyManager, error) {
+	m := &AESKeyManager{keys: map[string]cipher.AEAD{}}
+
+	if err := m.Rotate(keyID, masterKey); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Rotate makes the given master key the current one, previous keys are kept for unwrapping only.
+func (m *AESKeyManager) Rotate(keyID string, masterKey []byte) error {
+	aead, err := newGCM(masterKey)
+
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.keys[keyID] = aead
+	m.current = keyID
+
+	return nil
+}
+
+// WrapKey encrypts the data key with the current master key.
+func (m *AESKeyManager) WrapKey(dataKey []byte) ([]byte, string, error) {
+	m.mu.RLock()
+	aead, current := m.keys[m.current], m.current
+	m.mu.RUnlock()
+
+	wrapped, err := seal(aead, dataKey, nil)
+
+	return wrapped, current, err
+}
+
+// UnwrapKey decrypts the data key with the master key identified by keyID.
+func (m *AESKeyManager) UnwrapKey(wrapped []byte, keyID string) ([]byte, error) {
+	m.mu.RLock()
+	aead, ok := m.keys[keyID]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrDecrypt, keyID)
+	}
+
+	return open(aead, wrapped, nil)
+}
+
+// EncryptedConversationStore wraps a ConversationStore and encrypts message contents and images at rest.
+//
+// Every saved conversation gets a fresh random data key which encrypts the message contents and images
+// with AES-256-GCM, the data key itself is stored wrapped by the KeyManager alongside the conversation
+// (envelope encryption). Conversations are decrypted transparently when loaded.
+//
+// Every content and image is bound to the ID of its conversation and its position in the conversation,
+// so ciphertexts moved between conversations or messages fail to decrypt.
+type EncryptedConversationStore struct {
+	store ConversationStore
+	keys  KeyManager
+}
+
+// NewEncryptedConversationStore creates a new store encrypting the conversations saved to the underlying store.
+func NewEncryptedConversationStore(store ConversationStore, keys KeyManager) *EncryptedConversationStore {
+	return &EncryptedConversationStore{store: store, keys: keys}
+}
+
+// Save encrypts the message contents and saves the conversation to the underlying store.
+func (s *EncryptedConversationStore) Save(conversation *Conversation) error {
+	encrypted, err := s.encrypt(conversation)
+
+	if err != nil {
+		return err
+	}
+
+	return s.store.Save(encrypted)
+}
+
+// Load loads the conversation from the underlying store and decrypts its message contents.
+func (s *EncryptedConversationStore) Load(id string) (*Conversation, error) {
+	conversation, err := s.store.Load(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.decrypt(conversation)
+}
+
+// List lists the conversations of the underlying store and decrypts their message contents.
+func (s *EncryptedConversationStore) List() ([]*Conversation, error) {
+	conversations, err := s.store.List()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, conversation := range conversations {
+		if conversations[i], err = s.decrypt(conversation); err != nil {
+			return nil, err
+		}
+	}
+
+	return conversations, nil
+}
+
+// Delete deletes the conversation from the underlying store.
+func (s *EncryptedConversationStore) Delete(id string) error {
+	return s.store.Delete(id)
+}
+
+// encrypt returns a copy of the conversation with encrypted message contents.
+func (s *EncryptedConversationStore) encrypt(conversation *Conversation) (*Conversation, error) {
+	dataKey := make([]byte, 32)
+
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEncrypt, err)
+	}
+
+	aead, err := newGCM(dataKey)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEncrypt, err)
+	}
+
+	wrapped, keyID, err := s.keys.WrapKey(dataKey)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEncrypt, err)
+	}
+
+	encrypted := conversation.clone()
+	encrypted.Encryption = &Encryption{
+		Algorithm:  ENCRYPTION_AES_GCM,
+		KeyID:      keyID,
+		WrappedKey: wrapped,
+	}
+
+	for i, msg := range encrypted.Messages {
+		if encrypted.Messages[i].Content, err = sealString(aead, msg.Content, binding(encrypted.ID, i)); err != nil {
+			return nil, err
+		}
+
+		// the images are shared with the caller's conversation, they are encrypted into a new slice
+		if len(msg.Images) == 0 {
+			continue
+		}
+
+		encrypted.Messages[i].Images = make([]string, len(msg.Images))
+
+		for j, image := range msg.Images {
+			if encrypted.Messages[i].Images[j], err = sealString(aead, image, binding(encrypted.ID, i, j)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return encrypted, nil
+}
+
+// decrypt decrypts the message contents of the conversation in place, plain conversations are returned as is.
+func (s *EncryptedConversationStore) decrypt(conversation *Conversation) (*Conversation, error) {
+	if conversation.Encryption == nil {
+		return conversation, nil
+	}
+
+	if conversation.Encryption.Algorithm != ENCRYPTION_AES_GCM {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrDecrypt, conversation.Encryption.Algorithm)
+	}
+
+	dataKey, err := s.keys.UnwrapKey(conversation.Encryption.WrappedKey, conversation.Encryption.KeyID)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+
+	aead, err := newGCM(dataKey)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+
+	for i, msg := range conversation.Messages {
+		if conversation.Messages[i].Content, err = openString(aead, msg.Content, binding(conversation.ID, i)); err != nil {
+			return nil, err
+		}
+
+		// the images may be shared with the underlying store, they are decrypted into a new slice
+		if len(msg.Images) == 0 {
+			continue
+		}
+
+		conversation.Messages[i].Images = make([]string, len(msg.Images))
+
+		for j, image := range msg.Images {
+			if conversation.Messages[i].Images[j], err = openString(aead, image, binding(conversation.ID, i, j)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	conversation.Encryption = nil
+
+	return conversation, nil
+}
+
+// binding returns the additional authenticated data binding a ciphertext to its conversation and position,
+// i.e: `"abc"/2` for the content of the third message of conversation "abc" and `"abc"/2/0` for its first image.
+// The ID is quoted so IDs containing slashes can't collide with positions.
+func binding(id string, position ...int) []byte {
+	data := []byte(strconv.Quote(id))
+
+	for _, index := range position {
+		data = strconv.AppendInt(append(data, '/'), int64(index), 10)
+	}
+
+	return data
+}
+
+// sealString encrypts the text with seal and encodes the ciphertext with base64.
+func sealString(aead cipher.AEAD, text string, additional []byte) (string, error) {
+	ciphertext, err := seal(aead, []byte(text), additional)
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrEncrypt, err)
+	}
+
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// openString decodes and decrypts a text encrypted with sealString.
+func openString(aead cipher.AEAD, text string, additional []byte) (string, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(text)
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+
+	plaintext, err := open(aead, ciphertext, additional)
+
+	return string(plaintext), err
+}
+
+// newGCM creates an AES-GCM cipher from the key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts and authenticates the plaintext along with the additional data, prefixing the ciphertext
+// with a random nonce.
+func seal(aead cipher.AEAD, plaintext, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+// open decrypts a ciphertext produced by seal with the same additional data.
+func open(aead cipher.AEAD, ciphertext, additional []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: ciphertext too short", ErrDecrypt)
+	}
+
+	plaintext, err := aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], additional)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+
+	return plaintext, nil
+}
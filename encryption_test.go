@@ -0,0 +1,155 @@
+package talkative_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEncryptedConversationStore tests envelope encryption of conversations, including key rotation.
+func TestEncryptedConversationStore(t *testing.T) {
+	keys, err := talkative.NewAESKeyManager("key-1", bytes.Repeat([]byte{1}, 32))
+	{
+		assert.NoError(t, err)
+	}
+
+	_, err = talkative.NewAESKeyManager("invalid", []byte("short"))
+	assert.Error(t, err)
+
+	plain := talkative.NewMemoryConversationStore()
+	store := talkative.NewEncryptedConversationStore(plain, keys)
+	conversation := &talkative.Conversation{
+		ID: "secret",
+		Messages: []talkative.ChatMessage{
+			{Role: talkative.USER, Content: "My card number is 4111 1111 1111 1111"},
+		},
+	}
+
+	assert.NoError(t, store.Save(conversation))
+
+	raw, _ := plain.Load("secret")
+
+	assert.NotNil(t, raw.Encryption)
+	assert.Equal(t, "key-1", raw.Encryption.KeyID)
+	assert.NotContains(t, raw.Messages[0].Content, "4111")
+
+	// the caller's conversation is left untouched
+	assert.Nil(t, conversation.Encryption)
+	assert.Contains(t, conversation.Messages[0].Content, "4111")
+
+	keys.Rotate("key-2", bytes.Repeat([]byte{2}, 32))
+
+	loaded, err := store.Load("secret")
+
+	assert.NoError(t, err)
+	assert.Nil(t, loaded.Encryption)
+	assert.Equal(t, conversation.Messages, loaded.Messages)
+
+	other, _ := talkative.NewAESKeyManager("key-1", bytes.Repeat([]byte{3}, 32))
+	_, err = talkative.NewEncryptedConversationStore(plain, other).Load("secret")
+
+	assert.ErrorIs(t, err, talkative.ErrDecrypt)
+}
+
+// TestEncryptedConversationStoreImages tests that the images of the messages are encrypted at rest.
+func TestEncryptedConversationStoreImages(t *testing.T) {
+	keys, _ := talkative.NewAESKeyManager("key-1", bytes.Repeat([]byte{1}, 32))
+	plain := talkative.NewMemoryConversationStore()
+	store := talkative.NewEncryptedConversationStore(plain, keys)
+	image := base64.StdEncoding.EncodeToString([]byte("\x89PNG scanned passport"))
+	conversation := &talkative.Conversation{
+		ID: "scan",
+		Messages: []talkative.ChatMessage{
+			{Role: talkative.USER, Content: "What is the passport number?", Images: []string{image}},
+			{Role: talkative.ASSISTANT, Content: "X1234567"},
+		},
+	}
+
+	assert.NoError(t, store.Save(conversation))
+
+	raw, _ := plain.Load("scan")
+	{
+		assert.Len(t, raw.Messages[0].Images, 1)
+		assert.NotEqual(t, image, raw.Messages[0].Images[0])
+		assert.NotContains(t, raw.Messages[0].Images[0], image)
+
+		ciphertext, _ := base64.StdEncoding.DecodeString(raw.Messages[0].Images[0])
+		assert.NotContains(t, string(ciphertext), "scanned passport")
+	}
+
+	// the caller's images are left untouched
+	assert.Equal(t, image, conversation.Messages[0].Images[0])
+
+	loaded, err := store.Load("scan")
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, conversation.Messages, loaded.Messages)
+	}
+
+	// decrypting doesn't alter the stored ciphertext
+	again, _ := plain.Load("scan")
+	assert.Equal(t, raw.Messages[0].Images, again.Messages[0].Images)
+}
+
+// TestEncryptedConversationStoreBinding tests that ciphertexts moved between conversations or messages fail to decrypt.
+func TestEncryptedConversationStoreBinding(t *testing.T) {
+	keys, _ := talkative.NewAESKeyManager("key-1", bytes.Repeat([]byte{1}, 32))
+	plain := talkative.NewMemoryConversationStore()
+	store := talkative.NewEncryptedConversationStore(plain, keys)
+
+	store.Save(&talkative.Conversation{
+		ID: "alice",
+		Messages: []talkative.ChatMessage{
+			{Role: talkative.USER, Content: "Transfer 10"},
+			{Role: talkative.USER, Content: "Transfer 1000"},
+		},
+	})
+
+	raw, _ := plain.Load("alice")
+
+	// swapped messages
+	swapped := *raw
+	swapped.Messages = []talkative.ChatMessage{raw.Messages[1], raw.Messages[0]}
+	plain.Save(&swapped)
+
+	_, err := store.Load("alice")
+	assert.ErrorIs(t, err, talkative.ErrDecrypt)
+
+	// copied to another conversation
+	moved := *raw
+	moved.ID = "bob"
+	plain.Save(&moved)
+
+	_, err = store.Load("bob")
+	assert.ErrorIs(t, err, talkative.ErrDecrypt)
+}
+
+// TestAESKeyManagerRotate tests rotating keys while data keys are wrapped and unwrapped.
+func TestAESKeyManagerRotate(t *testing.T) {
+	keys, _ := talkative.NewAESKeyManager("key-0", bytes.Repeat([]byte{1}, 32))
+	done := make(chan bool)
+
+	go func() {
+		defer close(done)
+
+		for i := 1; i <= 50; i++ {
+			keys.Rotate(fmt.Sprintf("key-%d", i), bytes.Repeat([]byte{byte(i)}, 32))
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		wrapped, keyID, err := keys.WrapKey([]byte("data key"))
+		assert.NoError(t, err)
+
+		unwrapped, err := keys.UnwrapKey(wrapped, keyID)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("data key"), unwrapped)
+	}
+
+	<-done
+}
@@ -0,0 +1,73 @@
+package talkative
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DEFAULT_HOST is the URL of a local Ollama server, used when OLLAMA_HOST is not set.
+const DEFAULT_HOST = "http://localhost:11434"
+
+// NewFromEnv creates a new Client for the server designated by the OLLAMA_HOST environment variable,
+// like the Ollama CLI does, falling back to DEFAULT_HOST.
+func NewFromEnv(opts ...Option) (*Client, error) {
+	return New(OllamaHost(), opts...)
+}
+
+// OllamaHost returns the URL of the server designated by the OLLAMA_HOST environment variable.
+//
+// It accepts the forms accepted by the Ollama CLI: a full URL, "host:port", a host or IP address alone,
+// or ":port". The scheme defaults to http and the port to 11434, or to the default port of an explicit scheme.
+// Invalid values fall back to DEFAULT_HOST.
+func OllamaHost() string {
+	value := strings.TrimSpace(os.Getenv("OLLAMA_HOST"))
+
+	if value == "" {
+		return DEFAULT_HOST
+	}
+
+	if strings.HasPrefix(value, "unix://") {
+		return value
+	}
+
+	port := "11434"
+	scheme, hostport, ok := strings.Cut(value, "://")
+
+	switch {
+	case !ok:
+		scheme, hostport = "http", value
+	case scheme == "http":
+		port = "80"
+	case scheme == "https":
+		port = "443"
+	default:
+		return DEFAULT_HOST
+	}
+
+	hostport, path, _ := strings.Cut(hostport, "/")
+	host, explicit, err := net.SplitHostPort(hostport)
+
+	if err != nil {
+		host = strings.Trim(hostport, "[]")
+	} else {
+		port = explicit
+	}
+
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return DEFAULT_HOST
+	}
+
+	if host == "" {
+		host = "localhost"
+	}
+
+	url := scheme + "://" + net.JoinHostPort(host, port)
+
+	if path = strings.Trim(path, "/"); path != "" {
+		url += "/" + path
+	}
+
+	return url
+}
@@ -0,0 +1,51 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOllamaHost tests resolving the forms of OLLAMA_HOST accepted by the Ollama CLI.
+func TestOllamaHost(t *testing.T) {
+	tests := map[string]string{
+		"":                            "http://localhost:11434",
+		"0.0.0.0":                     "http://0.0.0.0:11434",
+		":1234":                       "http://localhost:1234",
+		"example.com:8080":            "http://example.com:8080",
+		"[::1]":                       "http://[::1]:11434",
+		"https://example.com":         "https://example.com:443",
+		"http://example.com/ollama/":  "http://example.com:80/ollama",
+		"https://example.com:9000":    "https://example.com:9000",
+		"unix:///var/run/ollama.sock": "unix:///var/run/ollama.sock",
+		"example.com:port":            "http://localhost:11434",
+		"ftp://example.com":           "http://localhost:11434",
+	}
+
+	for value, expected := range tests {
+		t.Setenv("OLLAMA_HOST", value)
+
+		assert.Equal(t, expected, talkative.OllamaHost(), value)
+	}
+}
+
+// TestNewFromEnv tests creating a client for the server designated by OLLAMA_HOST.
+func TestNewFromEnv(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"models": []any{}})
+	})
+	defer server.Close()
+
+	t.Setenv("OLLAMA_HOST", strings.TrimPrefix(server.URL, "http://"))
+
+	client, err := talkative.NewFromEnv()
+	assert.NoError(t, err)
+
+	_, err = client.ListModels()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,109 @@
+package talkative
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error describes the failure of a request along with its context, so logs and error trackers can tell
+// which operation failed against which endpoint without parsing the error text:
+//
+//	var e *talkative.Error
+//
+//	if errors.As(err, &e) {
+//		log.Printf("op=%s endpoint=%s model=%s id=%s: %v", e.Op, e.Endpoint, e.Model, e.RequestID, e.Err)
+//	}
+//
+// The underlying error is still matched by errors.Is, i.e: errors.Is(err, ErrInvoke).
+type Error struct {
+	Op        string // The operation of the request, i.e: chat, completion or pull.
+	Endpoint  string // The URL of the endpoint the request was sent to.
+	Model     string // The model of the request, empty when the operation is not bound to a model.
+	RequestID string // The ID of the request, as reported by the lifecycle events. (Optional)
+	Err       error  // The underlying error.
+}
+
+// Error returns the description of the error prefixed with the operation, model and endpoint.
+func (e *Error) Error() string {
+	if e.Model == "" {
+		return fmt.Sprintf("%s %q: %v", e.Op, e.Endpoint, e.Err)
+	}
+
+	return fmt.Sprintf("%s %s %q: %v", e.Op, e.Model, e.Endpoint, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// wrapError wraps the error with the context of the request, errors already carrying one are returned as is.
+func wrapError(op, endpoint, model, id string, err error) error {
+	var e *Error
+
+	if err == nil || errors.As(err, &e) {
+		return err
+	}
+
+	return &Error{Op: op, Endpoint: endpoint, Model: model, RequestID: id, Err: err}
+}
+
+// wrap wraps the error with the context of the tracked request.
+func (l *lifecycle) wrap(err error) error {
+	return wrapError(l.op, l.endpoint, l.model, l.id, err)
+}
+
+// APIError describes a response of the server other than 200 OK, along with the message of the server.
+//
+// It matches the sentinel error of its status code with errors.Is: ErrBadRequest for 400 Bad Request, and
+// ErrInvoke for the others, along with ErrNotFound for 404 Not Found, ErrModelLoading for 503 Service
+// Unavailable and HintCheckServer for the remaining statuses.
+type APIError struct {
+	StatusCode int    // The status code of the response.
+	Message    string // The error reported by the server, i.e: "model 'llama2' not found", or its raw response.
+	Endpoint   string // The URL of the endpoint which answered.
+}
+
+// Error returns the description of the error, i.e: "bad request: status 400: model is required".
+func (e *APIError) Error() string {
+	errs := e.Unwrap()
+	hinted := errs[len(errs)-1] == HintCheckServer
+
+	if hinted {
+		errs = errs[:len(errs)-1]
+	}
+
+	var sb strings.Builder
+
+	for _, err := range errs {
+		fmt.Fprintf(&sb, "%v: ", err)
+	}
+
+	fmt.Fprintf(&sb, "status %d", e.StatusCode)
+
+	if e.Message != "" {
+		sb.WriteString(": " + e.Message)
+	}
+
+	if hinted {
+		fmt.Fprintf(&sb, ": %v", HintCheckServer)
+	}
+
+	return sb.String()
+}
+
+// Unwrap returns the sentinel errors of the status code.
+func (e *APIError) Unwrap() []error {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return []error{ErrBadRequest}
+	case http.StatusNotFound:
+		return []error{ErrInvoke, ErrNotFound}
+	case http.StatusServiceUnavailable:
+		return []error{ErrInvoke, ErrModelLoading}
+	default:
+		return []error{ErrInvoke, HintCheckServer}
+	}
+}
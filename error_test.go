@@ -0,0 +1,163 @@
+package talkative_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestErrorContext tests the errors of failed requests carry the context of the request.
+func TestErrorContext(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var ids []string
+
+	client, _ := talkative.New(server.URL)
+	client.Subscribe(func(event talkative.Event) {
+		ids = append(ids, event.ID)
+	})
+
+	_, err := client.Chat("llama2", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.ErrorIs(t, err, talkative.ErrInvoke)
+
+		var e *talkative.Error
+
+		if assert.ErrorAs(t, err, &e) {
+			assert.Equal(t, "chat", e.Op)
+			assert.Equal(t, "llama2", e.Model)
+			assert.Equal(t, server.URL+"/api/chat", e.Endpoint)
+			assert.NotEmpty(t, e.RequestID)
+			assert.Contains(t, ids, e.RequestID)
+		}
+	}
+
+	_, err = client.ListModels()
+	{
+		assert.ErrorIs(t, err, talkative.ErrInvoke)
+
+		var e *talkative.Error
+
+		if assert.ErrorAs(t, err, &e) {
+			assert.Equal(t, "list", e.Op)
+			assert.Empty(t, e.Model)
+			assert.Equal(t, server.URL+"/api/tags", e.Endpoint)
+		}
+	}
+
+	// Argument errors are returned as is.
+	_, err = client.ShowModel("")
+	{
+		assert.Equal(t, talkative.ErrModel, err)
+	}
+}
+
+// TestErrorStream tests the errors of a failing stream carry the context of the request.
+func TestErrorStream(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"model":"llama2","response":"Hi"}`)
+		fmt.Fprintln(w, `{not json`)
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	var streamErr error
+
+	done, err := client.Completion("llama2", func(cr *talkative.CompletionResponse, err error) {
+		if err != nil && streamErr == nil {
+			streamErr = err
+		}
+	}, &talkative.CompletionMessage{Prompt: "Hi"})
+
+	assert.NoError(t, err)
+	<-done
+
+	var e *talkative.Error
+
+	if assert.ErrorAs(t, streamErr, &e) {
+		assert.Equal(t, "completion", e.Op)
+		assert.Equal(t, server.URL+"/api/generate", e.Endpoint)
+	}
+}
+
+// TestErrorMessage tests the description of the errors.
+func TestErrorMessage(t *testing.T) {
+	err := &talkative.Error{Op: "chat", Endpoint: "http://localhost:11434/api/chat", Model: "llama2", Err: talkative.ErrInvoke}
+	{
+		assert.EqualError(t, err, `chat llama2 "http://localhost:11434/api/chat": unable to invoke ollama api`)
+		assert.True(t, errors.Is(err, talkative.ErrInvoke))
+	}
+
+	err.Model = ""
+	{
+		assert.EqualError(t, err, `chat "http://localhost:11434/api/chat": unable to invoke ollama api`)
+	}
+}
+
+// TestAPIError tests translating the responses of the server to API errors.
+func TestAPIError(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/chat":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"model is required"}`))
+		case "/api/show":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"model 'missing' not found"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("internal failure\n"))
+		}
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	_, err := client.Chat("llama2", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		var e *talkative.APIError
+
+		if assert.ErrorAs(t, err, &e) {
+			assert.Equal(t, http.StatusBadRequest, e.StatusCode)
+			assert.Equal(t, "model is required", e.Message)
+			assert.Equal(t, server.URL+"/api/chat", e.Endpoint)
+			assert.EqualError(t, e, "bad request: status 400: model is required")
+		}
+
+		assert.ErrorIs(t, err, talkative.ErrBadRequest)
+		assert.NotErrorIs(t, err, talkative.ErrInvoke)
+	}
+
+	_, err = client.ShowModel("missing")
+	{
+		var e *talkative.APIError
+
+		if assert.ErrorAs(t, err, &e) {
+			assert.EqualError(t, e, "unable to invoke ollama api: not found: status 404: model 'missing' not found")
+		}
+
+		assert.ErrorIs(t, err, talkative.ErrNotFound)
+		assert.ErrorIs(t, err, talkative.ErrInvoke)
+	}
+
+	_, err = client.ListModels()
+	{
+		var e *talkative.APIError
+
+		if assert.ErrorAs(t, err, &e) {
+			assert.Equal(t, "internal failure", e.Message)
+			assert.EqualError(t, e, "unable to invoke ollama api: status 500: internal failure: please make sure ollama server is running and url is correct")
+		}
+
+		assert.ErrorIs(t, err, talkative.HintCheckServer)
+	}
+}
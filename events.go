@@ -0,0 +1,233 @@
+package talkative
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Define an enum-like type to represent the lifecycle events of a request.
+type EventType string
+
+const (
+	// Emitted before the request is sent to the server.
+	EVENT_REQUEST_STARTED EventType = "request_started"
+
+	// Emitted when the first response chunk has been received.
+	EVENT_FIRST_TOKEN EventType = "first_token"
+
+	// Emitted when the response has been completely processed without errors.
+	EVENT_COMPLETED EventType = "completed"
+
+	// Emitted when the request or the processing of the response failed.
+	EVENT_FAILED EventType = "failed"
+
+	// Emitted when the creation time of a response is off the local clock by more than the tolerance,
+	// see WithClockSkewTolerance.
+	EVENT_CLOCK_SKEW EventType = "clock_skew"
+
+	// Emitted when an alias has been switched to another model, see Client.SwitchAlias.
+	EVENT_ALIAS_SWITCHED EventType = "alias_switched"
+
+	// Emitted when the in-flight requests of the previous model of a switched alias have finished.
+	EVENT_ALIAS_DRAINED EventType = "alias_drained"
+
+	// Emitted before a request hitting a loading model is retried, see WithLoadingRetry.
+	EVENT_MODEL_LOADING EventType = "model_loading"
+)
+
+// Event represents a lifecycle event of a request issued by the client.
+type Event struct {
+	Type     EventType     // The type of the event.
+	ID       string        // The identifier of the request, generated with the IDGenerator of the client.
+	Op       string        // The operation of the request, i.e: chat or completion.
+	Model    string        // The model used by the request.
+	Endpoint string        // The endpoint URL the request was sent to.
+	Time     time.Time     // Time the event occurred.
+	Elapsed  time.Duration // Time elapsed since the request started.
+	Err      error         // The error which caused the failure, only set for EVENT_FAILED and EVENT_MODEL_LOADING.
+	Skew     time.Duration // The offset of the server clock, only set for EVENT_CLOCK_SKEW with a valid creation time.
+	Alias    string        // The switched alias, only set for alias events.
+	Previous string        // The model the alias pointed to before the switch, only set for alias events.
+	Attempt  int           // The number of the retry, only set for EVENT_MODEL_LOADING.
+	Delay    time.Duration // The delay before the retry, only set for EVENT_MODEL_LOADING.
+
+	PromptTokens int // The number of prompt tokens of the final response, only set for EVENT_COMPLETED of chats and completions.
+	EvalTokens   int // The number of generated tokens of the final response, only set for EVENT_COMPLETED of chats and completions.
+}
+
+// EventHandler function type used for handling lifecycle events.
+type EventHandler func(Event)
+
+// eventBus dispatches lifecycle events to the subscribed handlers.
+type eventBus struct {
+	mu       sync.RWMutex
+	next     int
+	handlers map[int]subscription
+}
+
+// subscription is a handler subscribed to a set of event types, all types when empty.
+type subscription struct {
+	handler EventHandler
+	types   map[EventType]bool
+}
+
+// Subscribe registers a handler receiving the lifecycle events of all requests issued by this client.
+//
+// When types are given, the handler only receives events of these types. Handlers are invoked synchronously
+// on the goroutine processing the request, so they should return quickly. The returned function removes
+// the subscription, handlers may call it or subscribe other handlers.
+func (c *Client) Subscribe(handler EventHandler, types ...EventType) (unsubscribe func()) {
+	c.events.mu.Lock()
+	defer c.events.mu.Unlock()
+
+	if c.events.handlers == nil {
+		c.events.handlers = map[int]subscription{}
+	}
+
+	id := c.events.next
+	c.events.next++
+
+	sub := subscription{handler: handler}
+
+	if len(types) > 0 {
+		sub.types = map[EventType]bool{}
+
+		for _, typ := range types {
+			sub.types[typ] = true
+		}
+	}
+
+	c.events.handlers[id] = sub
+
+	return func() {
+		c.events.mu.Lock()
+		defer c.events.mu.Unlock()
+
+		delete(c.events.handlers, id)
+	}
+}
+
+// emit dispatches the event to the subscribed handlers.
+//
+// The handlers are invoked once the lock is released, so they can subscribe and unsubscribe.
+func (b *eventBus) emit(event Event) {
+	b.mu.RLock()
+
+	handlers := make([]EventHandler, 0, len(b.handlers))
+
+	for _, sub := range b.handlers {
+		if sub.types == nil || sub.types[event.Type] {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// lifecycle tracks a single request and emits its lifecycle events.
+type lifecycle struct {
+	client   *Client
+	id       string
+	op       string
+	model    string
+	endpoint string
+	start    time.Time
+	first    sync.Once
+	failed   atomic.Bool // Set by the first failure, from the stream or the pacing goroutine.
+
+	promptTokens int // The number of prompt tokens of the final response, reported on completion.
+	evalTokens   int // The number of generated tokens of the final response, reported on completion.
+}
+
+// begin starts tracking a request and emits EVENT_REQUEST_STARTED.
+func (c *Client) begin(op, model, endpoint string) *lifecycle {
+	c.acquire(model)
+
+	l := &lifecycle{
+		client:   c,
+		id:       c.NewID(),
+		op:       op,
+		model:    model,
+		endpoint: endpoint,
+		start:    time.Now(),
+	}
+
+	l.emit(EVENT_REQUEST_STARTED, nil)
+
+	return l
+}
+
+// fail emits EVENT_FAILED, only the first failure of a request is emitted.
+func (l *lifecycle) fail(err error) {
+	if l.failed.CompareAndSwap(false, true) {
+		l.emit(EVENT_FAILED, err)
+	}
+}
+
+// abort emits EVENT_FAILED for a request which could not be sent and stops tracking it.
+func (l *lifecycle) abort(err error) {
+	l.fail(err)
+	l.client.release(l.model)
+}
+
+// complete emits EVENT_COMPLETED unless the request failed and stops tracking it.
+func (l *lifecycle) complete() {
+	if !l.failed.Load() {
+		event := l.event(EVENT_COMPLETED, nil)
+		event.PromptTokens, event.EvalTokens = l.promptTokens, l.evalTokens
+
+		l.client.events.emit(event)
+	}
+
+	l.client.release(l.model)
+}
+
+// emit dispatches an event of the given type for this request.
+func (l *lifecycle) emit(typ EventType, err error) {
+	l.client.events.emit(l.event(typ, err))
+}
+
+// event returns an event of the given type for this request.
+func (l *lifecycle) event(typ EventType, err error) Event {
+	now := time.Now()
+
+	return Event{
+		Type:     typ,
+		ID:       l.id,
+		Op:       l.op,
+		Model:    l.model,
+		Endpoint: l.endpoint,
+		Time:     now,
+		Elapsed:  now.Sub(l.start),
+		Err:      err,
+	}
+}
+
+// track wraps the callback so the lifecycle events are emitted for the streamed responses, and the errors
+// of the stream are wrapped with the context of the request.
+func track[T any](l *lifecycle, cb func(T, error)) func(T, error) {
+	return func(response T, err error) {
+		if err != nil {
+			err = l.wrap(err)
+			l.fail(err)
+		} else {
+			l.first.Do(func() {
+				l.emit(EVENT_FIRST_TOKEN, nil)
+			})
+		}
+
+		cb(response, err)
+
+		// the final response carries the token counts, once estimated by the wrapped callbacks
+		if r, ok := any(response).(metered); ok && err == nil {
+			if _, done := r.chunk(); done {
+				l.promptTokens, l.evalTokens = r.tokens()
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package talkative_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEvents tests the lifecycle events emitted for successful and failing requests.
+func TestEvents(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: "Hello"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: "!"}, Done: true},
+	)
+	defer server.Close()
+
+	message := talkative.ChatMessage{Role: talkative.USER, Content: "Hi"}
+
+	t.Run("events-completed", func(t *testing.T) {
+		client, _ := talkative.New(server.URL)
+		events := []talkative.EventType{}
+
+		client.Subscribe(func(e talkative.Event) {
+			assert.Equal(t, "chat", e.Op)
+			assert.Equal(t, talkative.DEFAULT_MODEL, e.Model)
+
+			events = append(events, e.Type)
+		})
+
+		done, _ := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+		<-done
+
+		assert.Equal(t, []talkative.EventType{
+			talkative.EVENT_REQUEST_STARTED,
+			talkative.EVENT_FIRST_TOKEN,
+			talkative.EVENT_COMPLETED,
+		}, events)
+	})
+
+	t.Run("events-failed", func(t *testing.T) {
+		failing := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer failing.Close()
+
+		client, _ := talkative.New(failing.URL)
+		failures := []error{}
+
+		unsubscribe := client.Subscribe(func(e talkative.Event) {
+			failures = append(failures, e.Err)
+		}, talkative.EVENT_FAILED)
+
+		client.Completion("", func(cr *talkative.CompletionResponse, err error) {}, &talkative.CompletionMessage{Prompt: "Hi"})
+
+		assert.Len(t, failures, 1)
+		assert.ErrorIs(t, failures[0], talkative.ErrInvoke)
+
+		unsubscribe()
+		client.Completion("", func(cr *talkative.CompletionResponse, err error) {}, &talkative.CompletionMessage{Prompt: "Hi"})
+
+		assert.Len(t, failures, 1)
+	})
+
+	t.Run("events-subscribe-from-handler", func(t *testing.T) {
+		client, _ := talkative.New(server.URL)
+		first, later := []talkative.EventType{}, []talkative.EventType{}
+
+		var unsubscribe func()
+
+		unsubscribe = client.Subscribe(func(e talkative.Event) {
+			first = append(first, e.Type)
+
+			unsubscribe()
+			client.Subscribe(func(e talkative.Event) {
+				later = append(later, e.Type)
+			})
+		})
+
+		done, _ := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("handler deadlocked the event bus")
+		}
+
+		assert.Equal(t, []talkative.EventType{talkative.EVENT_REQUEST_STARTED}, first)
+		assert.Equal(t, []talkative.EventType{talkative.EVENT_FIRST_TOKEN, talkative.EVENT_COMPLETED}, later)
+	})
+}
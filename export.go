@@ -0,0 +1,151 @@
+package talkative
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// DEFAULT_EXPORT_PAGE_SIZE is the default number of conversations exported per page.
+const DEFAULT_EXPORT_PAGE_SIZE = 100
+
+// ConversationPager is implemented by conversation stores able to list their conversations page by page,
+// i.e: backed by a database. ExportConversations uses it when available instead of listing all conversations at once.
+type ConversationPager interface {
+	// ListPage returns at most limit conversations created after the conversation with the given ID,
+	// ordered by creation time. An empty ID returns the first page.
+	ListPage(after string, limit int) ([]*Conversation, error)
+}
+
+// ExportOptions configures which conversations ExportConversations exports and how.
+type ExportOptions struct {
+	Owner    string          // Only exports the conversations of the owner, i.e: for a data-export request. (Optional)
+	Since    time.Time       // Only exports the conversations created at or after this time. (Optional)
+	Until    time.Time       // Only exports the conversations created before this time. (Optional)
+	PageSize int             // Number of conversations exported per page, defaults to DEFAULT_EXPORT_PAGE_SIZE.
+	OnPage   func(count int) // Invoked after every page with the number of conversations exported so far. (Optional)
+}
+
+// ExportConversations writes the conversations of the store matching the options to w as JSON lines,
+// one conversation per line, returning the number of exported conversations.
+//
+// Conversations are exported page by page and every page is flushed before the next one is read,
+// so a slow writer slows the export down rather than buffering the whole store. The export stops
+// when the context is cancelled, between two pages.
+func ExportConversations(ctx context.Context, store ConversationStore, w io.Writer, opts ExportOptions) (int, error) {
+	size := opts.PageSize
+
+	if size <= 0 {
+		size = DEFAULT_EXPORT_PAGE_SIZE
+	}
+
+	next := pages(store, size)
+	writer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(writer)
+	count := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+
+		page, err := next()
+
+		if err != nil {
+			return count, err
+		}
+
+		if len(page) == 0 {
+			return count, nil
+		}
+
+		for _, conversation := range page {
+			if !opts.matches(conversation) {
+				continue
+			}
+
+			if err := encoder.Encode(conversation); err != nil {
+				return count, err
+			}
+
+			count++
+		}
+
+		if err := writer.Flush(); err != nil {
+			return count, err
+		}
+
+		if opts.OnPage != nil {
+			opts.OnPage(count)
+		}
+	}
+}
+
+// matches reports whether the conversation matches the filters of the options.
+func (opts *ExportOptions) matches(conversation *Conversation) bool {
+	if opts.Owner != "" && conversation.Owner != opts.Owner {
+		return false
+	}
+
+	if !opts.Since.IsZero() && conversation.CreatedAt.Before(opts.Since) {
+		return false
+	}
+
+	if !opts.Until.IsZero() && !conversation.CreatedAt.Before(opts.Until) {
+		return false
+	}
+
+	return true
+}
+
+// pages returns a function returning the successive pages of conversations of the store, then an empty page.
+func pages(store ConversationStore, size int) func() ([]*Conversation, error) {
+	if pager, ok := store.(ConversationPager); ok {
+		after, done := "", false
+
+		return func() ([]*Conversation, error) {
+			if done {
+				return nil, nil
+			}
+
+			page, err := pager.ListPage(after, size)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if len(page) < size {
+				done = true
+			}
+
+			if len(page) > 0 {
+				after = page[len(page)-1].ID
+			}
+
+			return page, nil
+		}
+	}
+
+	var all []*Conversation
+
+	listed := false
+
+	return func() ([]*Conversation, error) {
+		if !listed {
+			conversations, err := store.List()
+
+			if err != nil {
+				return nil, err
+			}
+
+			all, listed = conversations, true
+		}
+
+		page := all[:min(size, len(all))]
+		all = all[len(page):]
+
+		return page, nil
+	}
+}
@@ -0,0 +1,107 @@
+package talkative_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pagedStore is a conversation store recording the pages it is asked for.
+type pagedStore struct {
+	*talkative.MemoryConversationStore
+	pages []string
+}
+
+func (s *pagedStore) ListPage(after string, limit int) ([]*talkative.Conversation, error) {
+	s.pages = append(s.pages, after)
+	conversations, _ := s.List()
+
+	for i, conversation := range conversations {
+		if conversation.ID == after {
+			conversations = conversations[i+1:]
+
+			break
+		}
+	}
+
+	return conversations[:min(limit, len(conversations))], nil
+}
+
+// exportStore is a helper function that creates a store with five conversations, created a day apart and
+// owned by alice and bob alternately.
+func exportStore() *talkative.MemoryConversationStore {
+	store := talkative.NewMemoryConversationStore()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		store.Save(&talkative.Conversation{
+			ID:        fmt.Sprint(i),
+			Owner:     []string{"alice", "bob"}[i%2],
+			CreatedAt: start.AddDate(0, 0, i),
+		})
+	}
+
+	return store
+}
+
+// TestExportConversations tests exporting filtered conversations as JSON lines page by page.
+func TestExportConversations(t *testing.T) {
+	buf := &bytes.Buffer{}
+	pages := []int{}
+
+	count, err := talkative.ExportConversations(context.Background(), exportStore(), buf, talkative.ExportOptions{
+		Owner:    "alice",
+		Since:    time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		PageSize: 2,
+		OnPage:   func(count int) { pages = append(pages, count) },
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []int{0, 1, 2}, pages)
+
+	ids := []string{}
+	scanner := bufio.NewScanner(buf)
+
+	for scanner.Scan() {
+		conversation := talkative.Conversation{}
+
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &conversation))
+		ids = append(ids, conversation.ID)
+	}
+
+	assert.Equal(t, []string{"2", "4"}, ids)
+}
+
+// TestExportConversationsPager tests exporting with the pagination of the store and stopping on cancellation.
+func TestExportConversationsPager(t *testing.T) {
+	store := &pagedStore{MemoryConversationStore: exportStore()}
+	buf := &bytes.Buffer{}
+
+	count, err := talkative.ExportConversations(context.Background(), store, buf, talkative.ExportOptions{
+		Until:    time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		PageSize: 2,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+	assert.Equal(t, []string{"", "1", "3"}, store.pages)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	count, err = talkative.ExportConversations(ctx, store, buf, talkative.ExportOptions{
+		PageSize: 2,
+		OnPage:   func(int) { cancel() },
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,93 @@
+package talkative
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WithFallbackURLs makes the client try the given base URLs in order when the base URL given to New cannot be
+// connected to, i.e: the IPv4 address of a dual-stack server whose IPv6 address is unreachable:
+//
+//	client, err := talkative.New("http://[::1]:11434", talkative.WithFallbackURLs("http://127.0.0.1:11434"))
+//
+// Only failures to connect fail over, since the request never reached the server then. Requests are always
+// tried against the base URL first, use a Router with a HealthMonitor for load balancing. The fallback URLs
+// share the transport of the client, they can't designate Unix domain sockets.
+func WithFallbackURLs(urls ...string) Option {
+	return func(c *Client) {
+		for _, fallback := range urls {
+			if fallback = strings.TrimRight(strings.Trim(fallback, " "), "/"); fallback != "" {
+				c.fallbackURLs = append(c.fallbackURLs, fallback)
+			}
+		}
+	}
+}
+
+// failover sends the request to the base URL of the client, then to its fallback URLs in order for as long as
+// the connection fails, returning the error of the base URL when none could be connected to.
+func (c *Client) failover(req *http.Request) (*http.Response, error) {
+	res, err := c.attempt(req)
+
+	if err == nil || len(c.fallbackURLs) == 0 || !unreachable(req, err) {
+		return res, err
+	}
+
+	path, ok := strings.CutPrefix(req.URL.String(), c.base)
+
+	// Requests to other servers, i.e: absolute endpoint overrides, have no fallback.
+	if !ok || (path != "" && path[0] != '/' && path[0] != '?') {
+		return nil, err
+	}
+
+	for _, fallback := range c.fallbackURLs {
+		retry, rebaseErr := rebase(req, fallback+path)
+
+		if rebaseErr != nil {
+			return nil, err
+		}
+
+		res, retryErr := c.attempt(retry)
+
+		if retryErr == nil || !unreachable(retry, retryErr) {
+			return res, retryErr
+		}
+	}
+
+	return nil, err
+}
+
+// unreachable reports whether the request failed to connect to the server, which is safe to retry elsewhere
+// since nothing was sent. Requests whose body can't be sent again are never reported as unreachable.
+func unreachable(req *http.Request, err error) bool {
+	var opErr *net.OpError
+
+	if req.Context().Err() != nil || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return false
+	}
+
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// rebase returns a copy of the request sent to the given URL, with a fresh copy of its body.
+func rebase(req *http.Request, target string) (*http.Request, error) {
+	u, err := url.Parse(target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	retry.URL = u
+	retry.Host = ""
+
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+
+	return retry, nil
+}
@@ -0,0 +1,66 @@
+package talkative_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFallbackURLs tests sending requests to the fallback URLs when the base URL cannot be connected to.
+func TestFallbackURLs(t *testing.T) {
+	unreachable := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unreachable.Close()
+
+	server := streamServer(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}, Done: true})
+	defer server.Close()
+
+	client, _ := talkative.New(unreachable.URL, talkative.WithFallbackURLs(unreachable.URL+"/", server.URL))
+	content := ""
+
+	done, err := client.Chat("llama2", func(cr *talkative.ChatResponse, err error) {
+		assert.NoError(t, err)
+
+		content += cr.Message.Content
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+		<-done
+		assert.Equal(t, "Hello", content)
+	}
+
+	client, _ = talkative.New(unreachable.URL, talkative.WithFallbackURLs(unreachable.URL))
+
+	_, err = client.Chat("llama2", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		var e *talkative.Error
+
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, unreachable.URL+"/api/chat", e.Endpoint)
+	}
+}
+
+// TestFallbackURLsResponse tests not failing over when the base URL answered with an error.
+func TestFallbackURLsResponse(t *testing.T) {
+	failing := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "out of memory"}`, http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	calls := 0
+	fallback := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer fallback.Close()
+
+	client, _ := talkative.New(failing.URL, talkative.WithFallbackURLs(fallback.URL))
+
+	_, err := client.Chat("llama2", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.ErrorContains(t, err, "out of memory")
+		assert.Equal(t, 0, calls)
+	}
+}
@@ -0,0 +1,128 @@
+package talkative
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// FallbackData is the data the fallback template is rendered with.
+type FallbackData struct {
+	Model  string // The model the request was sent to.
+	Prompt string // The last user message of the chat, or the prompt of the completion.
+	Err    error  // The error which made the backend unavailable.
+}
+
+// WithFallback answers chats and completions with the rendered template, instead of failing,
+// when the backend is unavailable, so user-facing applications can fail soft.
+//
+// The template is either static text, i.e: "The assistant is unavailable, please retry later.",
+// or a text/template rendered with FallbackData. Fallback responses are delivered to the callback
+// as a single final response with the Degraded flag set. Only failures to reach the server and server errors
+// (5xx) are degraded, client errors (4xx) such as bad requests, unknown models or rejected credentials, and
+// cancelled requests still fail.
+func WithFallback(template string) Option {
+	return func(c *Client) {
+		c.fallback = &template
+	}
+}
+
+// degrade renders the fallback response of a request which failed with err.
+//
+// It reports false when no fallback is configured, the error doesn't mean the backend is unavailable
+// or the template cannot be rendered, in which case the request must fail with err.
+func (c *Client) degrade(ctx context.Context, model, prompt string, err error) (string, bool) {
+	if c.fallback == nil || !unavailable(err) || ctx.Err() != nil {
+		return "", false
+	}
+
+	text, renderErr := DefaultTemplates.Render(*c.fallback, FallbackData{Model: model, Prompt: prompt, Err: err})
+
+	return text, renderErr == nil
+}
+
+// unavailable reports whether the error means the backend is unavailable: the server couldn't be reached or
+// didn't answer in time, or it answered with a server error.
+func unavailable(err error) bool {
+	var (
+		e      *APIError
+		netErr net.Error
+	)
+
+	if errors.As(err, &e) {
+		return e.StatusCode >= 500
+	}
+
+	return errors.Is(err, ErrRequestTimeout) || errors.As(err, &netErr)
+}
+
+// degradedChat delivers the fallback response of the chat to the callback.
+func (c *Client) degradedChat(ctx context.Context, request *ChatRequest, cb ChatCallBack, err error) (<-chan bool, bool) {
+	prompt := ""
+
+	for _, msg := range request.Messages {
+		if msg.Role == USER {
+			prompt = msg.Content
+		}
+	}
+
+	text, ok := c.degrade(ctx, request.Model, prompt, err)
+
+	if !ok {
+		return nil, false
+	}
+
+	chDone := make(chan bool, 1)
+
+	go func() {
+		defer c.guard("chat", func(err error) {
+			notify(cb, err)
+
+			signalDone(chDone)
+		})()
+
+		cb(&ChatResponse{
+			Model:     request.Model,
+			Message:   ChatMessage{Role: ASSISTANT, Content: text},
+			CreatedAt: time.Now(),
+			Done:      true,
+			Degraded:  true,
+		}, nil)
+
+		signalDone(chDone)
+	}()
+
+	return chDone, true
+}
+
+// degradedCompletion delivers the fallback response of the completion to the callback.
+func (c *Client) degradedCompletion(ctx context.Context, request *CompletionRequest, cb CompletionCallback, err error) (<-chan bool, bool) {
+	text, ok := c.degrade(ctx, request.Model, request.Prompt, err)
+
+	if !ok {
+		return nil, false
+	}
+
+	chDone := make(chan bool, 1)
+
+	go func() {
+		defer c.guard("completion", func(err error) {
+			notify(cb, err)
+
+			signalDone(chDone)
+		})()
+
+		cb(&CompletionResponse{
+			Model:     request.Model,
+			Response:  text,
+			CreatedAt: time.Now().Format(time.RFC3339Nano),
+			Done:      true,
+			Degraded:  true,
+		}, nil)
+
+		signalDone(chDone)
+	}()
+
+	return chDone, true
+}
@@ -0,0 +1,109 @@
+package talkative_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFallback tests degraded responses when the backend is unavailable.
+func TestFallback(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithFallback("Sorry, {{.Model}} can't answer \"{{.Prompt}}\" right now."))
+
+	var response *talkative.ChatResponse
+
+	done, err := client.Chat("llama2", func(cr *talkative.ChatResponse, err error) {
+		assert.NoError(t, err)
+		response = cr
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+	}
+
+	<-done
+	assert.True(t, response.Degraded)
+	assert.True(t, response.Done)
+	assert.Equal(t, "Sorry, llama2 can't answer \"Hi\" right now.", response.Message.Content)
+
+	var completion *talkative.CompletionResponse
+
+	done, err = client.Completion("", func(cr *talkative.CompletionResponse, err error) {
+		completion = cr
+	}, &talkative.CompletionMessage{Prompt: "Hello"})
+	{
+		assert.NoError(t, err)
+	}
+
+	<-done
+	assert.True(t, completion.Degraded)
+	assert.Equal(t, "Sorry, llama2 can't answer \"Hello\" right now.", completion.Response)
+}
+
+// TestFallbackBadRequest tests bad requests still failing with a fallback configured.
+func TestFallbackBadRequest(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithFallback("Unavailable"))
+
+	_, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.ErrorIs(t, err, talkative.ErrBadRequest)
+	}
+}
+
+// TestFallbackClientError tests client errors still failing with a fallback configured.
+func TestFallbackClientError(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusUnauthorized} {
+		server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+
+		client, _ := talkative.New(server.URL, talkative.WithFallback("Unavailable"))
+
+		_, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+		{
+			var e *talkative.APIError
+
+			assert.ErrorAs(t, err, &e)
+			assert.Equal(t, status, e.StatusCode)
+		}
+
+		_, err = client.Completion("", func(cr *talkative.CompletionResponse, err error) {}, &talkative.CompletionMessage{Prompt: "Hello"})
+		{
+			assert.ErrorIs(t, err, talkative.ErrInvoke)
+		}
+
+		server.Close()
+	}
+}
+
+// TestFallbackUnreachable tests degraded responses when the server cannot be reached.
+func TestFallbackUnreachable(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithFallback("Unavailable"))
+
+	var response *talkative.ChatResponse
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {
+		response = cr
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+	}
+
+	<-done
+	assert.True(t, response.Degraded)
+}
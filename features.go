@@ -0,0 +1,67 @@
+package talkative
+
+import (
+	"context"
+	"sort"
+)
+
+// Define an enum-like type to represent the experimental behaviours which can be enabled on a client.
+type Feature string
+
+const (
+	// Pull the model of chats, completions and embeddings answered with ErrNotFound, then send them again.
+	FEATURE_AUTO_PULL Feature = "auto-pull"
+)
+
+// WithFeatures enables the given experimental behaviours on the client.
+//
+// Experimental behaviours are disabled by default so they can be adopted incrementally,
+// existing code paths are left untouched unless a feature is explicitly enabled.
+func WithFeatures(features ...Feature) Option {
+	return func(c *Client) {
+		if c.features == nil {
+			c.features = make(map[Feature]bool, len(features))
+		}
+
+		for _, feature := range features {
+			c.features[feature] = true
+		}
+	}
+}
+
+// Enabled reports whether the experimental behaviour is enabled on the client.
+func (c *Client) Enabled(feature Feature) bool {
+	return c.features[feature]
+}
+
+// Features returns the experimental behaviours enabled on the client, sorted by name.
+func (c *Client) Features() []Feature {
+	features := make([]Feature, 0, len(c.features))
+
+	for feature := range c.features {
+		features = append(features, feature)
+	}
+
+	sort.Slice(features, func(i, j int) bool { return features[i] < features[j] })
+
+	return features
+}
+
+// pull downloads the model and waits for the end of the download, see FEATURE_AUTO_PULL.
+func (c *Client) pull(ctx context.Context, model string) error {
+	var pullErr error
+
+	done, err := c.PullModelContext(ctx, model, func(p *Progress, err error) {
+		if err != nil {
+			pullErr = err
+		}
+	})
+
+	if err != nil {
+		return err
+	}
+
+	<-done
+
+	return pullErr
+}
@@ -0,0 +1,84 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFeatures tests enabling experimental behaviours on the client.
+func TestFeatures(t *testing.T) {
+	const (
+		alpha talkative.Feature = "alpha"
+		beta  talkative.Feature = "beta"
+		gamma talkative.Feature = "gamma"
+	)
+
+	client, _ := talkative.New("http://localhost:11434")
+
+	assert.False(t, client.Enabled(alpha))
+	assert.Empty(t, client.Features())
+
+	client, _ = talkative.New(
+		"http://localhost:11434",
+		talkative.WithFeatures(gamma),
+		talkative.WithFeatures(alpha),
+	)
+
+	assert.True(t, client.Enabled(alpha))
+	assert.False(t, client.Enabled(beta))
+	assert.Equal(t, []talkative.Feature{alpha, gamma}, client.Features())
+}
+
+// TestFeatureAutoPull tests pulling missing models only when FEATURE_AUTO_PULL is enabled.
+func TestFeatureAutoPull(t *testing.T) {
+	var pulled atomic.Int32
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/pull" {
+			pulled.Add(1)
+			json.NewEncoder(w).Encode(talkative.Progress{Status: "success"})
+
+			return
+		}
+
+		if pulled.Load() == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "model 'llama3' not found"}`))
+
+			return
+		}
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hi"}, Done: true})
+	}))
+	defer server.Close()
+
+	message := talkative.ChatMessage{Role: talkative.USER, Content: "Hi"}
+
+	client, _ := talkative.New(server.URL)
+
+	_, err := client.Chat("llama3", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+	assert.ErrorIs(t, err, talkative.ErrNotFound)
+	assert.EqualValues(t, 0, pulled.Load())
+
+	client, _ = talkative.New(server.URL, talkative.WithFeatures(talkative.FEATURE_AUTO_PULL))
+	content := ""
+
+	done, err := client.Chat("llama3", func(cr *talkative.ChatResponse, err error) {
+		if err == nil {
+			content += cr.Message.Content
+		}
+	}, nil, message)
+	{
+		assert.NoError(t, err)
+	}
+
+	<-done
+	assert.EqualValues(t, 1, pulled.Load())
+	assert.Equal(t, "Hi", content)
+}
@@ -0,0 +1,106 @@
+package talkative
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Define an enum-like type to represent the boundaries at which streamed output can be flushed,
+// ordered from the finest to the coarsest.
+type FlushHint int
+
+const (
+	// The output reached the end of a chunk.
+	FLUSH_CHUNK FlushHint = iota
+
+	// The output reached the end of a sentence or a line.
+	FLUSH_SENTENCE
+
+	// The output reached the end of a paragraph.
+	FLUSH_PARAGRAPH
+
+	// The output reached the end of the stream.
+	FLUSH_END
+)
+
+// FlushHinter is implemented by writers receiving flush hints from ChatTo and CompletionTo, i.e: bridges
+// to SSE or WebSocket connections, which are then responsible for flushing the output themselves.
+//
+// FlushHint is called after every chunk written, and once with FLUSH_END when the stream completed.
+// Returning an error aborts the request, like a failing write does.
+type FlushHinter interface {
+	FlushHint(hint FlushHint) error
+}
+
+// HintOf returns the flush hint of the output written so far, so producers consuming callbacks can flush
+// at the same boundaries as FlushHinter writers.
+//
+// A sentence ends with a terminal punctuation, possibly followed by closing quotes or brackets, or with a
+// line break. A paragraph ends with a blank line.
+func HintOf(written string) FlushHint {
+	trimmed := strings.TrimRight(written, " \t")
+
+	if strings.HasSuffix(trimmed, "\n\n") {
+		return FLUSH_PARAGRAPH
+	}
+
+	if strings.HasSuffix(trimmed, "\n") {
+		return FLUSH_SENTENCE
+	}
+
+	trimmed = strings.TrimRightFunc(trimmed, func(r rune) bool {
+		return strings.ContainsRune(`"')]}»”’`, r)
+	})
+
+	if r, _ := utf8.DecodeLastRuneInString(trimmed); strings.ContainsRune(".!?…。！？", r) {
+		return FLUSH_SENTENCE
+	}
+
+	return FLUSH_CHUNK
+}
+
+// FlushWriter is a FlushHinter flushing the underlying writer at the boundaries of its granularity, so
+// proxies and browsers render the output promptly without flushing every single chunk.
+type FlushWriter struct {
+	w     io.Writer
+	at    FlushHint
+	flush func() error
+}
+
+// NewFlushWriter creates a new FlushWriter flushing w on hints of at least the given granularity,
+// i.e: FLUSH_SENTENCE flushes the ends of sentences, paragraphs and the stream.
+//
+// The underlying writer is flushed when it implements http.Flusher, like an http.ResponseWriter does,
+// or has a Flush() error method, like a bufio.Writer does. Other writers are never flushed.
+func NewFlushWriter(w io.Writer, at FlushHint) *FlushWriter {
+	f := &FlushWriter{w: w, at: at}
+
+	switch flusher := w.(type) {
+	case http.Flusher:
+		f.flush = func() error {
+			flusher.Flush()
+
+			return nil
+		}
+	case interface{ Flush() error }:
+		f.flush = flusher.Flush
+	}
+
+	return f
+}
+
+// Write writes p to the underlying writer without flushing it.
+func (f *FlushWriter) Write(p []byte) (int, error) {
+	return f.w.Write(p)
+}
+
+// FlushHint flushes the underlying writer when the hint is at least of the granularity of the writer.
+func (f *FlushWriter) FlushHint(hint FlushHint) error {
+	if hint < f.at || f.flush == nil {
+		return nil
+	}
+
+	return f.flush()
+}
@@ -0,0 +1,107 @@
+package talkative_test
+
+import (
+	"bufio"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flushRecorder records the flushes of a response.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushed []string
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushed = append(r.flushed, r.Body.String())
+}
+
+// hintRecorder records the flush hints received.
+type hintRecorder struct {
+	strings.Builder
+	hints []talkative.FlushHint
+}
+
+func (r *hintRecorder) FlushHint(hint talkative.FlushHint) error {
+	r.hints = append(r.hints, hint)
+
+	return nil
+}
+
+// TestHintOf tests detecting the boundaries of the output written so far.
+func TestHintOf(t *testing.T) {
+	tests := map[string]talkative.FlushHint{
+		"":                   talkative.FLUSH_CHUNK,
+		"Hello":              talkative.FLUSH_CHUNK,
+		"Hello, world":       talkative.FLUSH_CHUNK,
+		"Hello world.":       talkative.FLUSH_SENTENCE,
+		"Really? ":           talkative.FLUSH_SENTENCE,
+		`He said "stop!"`:    talkative.FLUSH_SENTENCE,
+		"(see above.) ":      talkative.FLUSH_SENTENCE,
+		"你好。":                talkative.FLUSH_SENTENCE,
+		"- item\n":           talkative.FLUSH_SENTENCE,
+		"First.\n\n":         talkative.FLUSH_PARAGRAPH,
+		"First.\n\n  ":       talkative.FLUSH_PARAGRAPH,
+		"First.\n\nSecond":   talkative.FLUSH_CHUNK,
+		"Wait for it...":     talkative.FLUSH_SENTENCE,
+		"version 1.2 is out": talkative.FLUSH_CHUNK,
+	}
+
+	for written, hint := range tests {
+		assert.Equal(t, hint, talkative.HintOf(written), written)
+	}
+}
+
+// TestFlushWriter tests flushing a response at the ends of sentences only.
+func TestFlushWriter(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: " world."}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: " How"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: " are you?\n\n"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Bye"}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	message := talkative.ChatMessage{Role: talkative.USER, Content: "Hi"}
+
+	recorder := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	_, err := client.ChatTo(context.Background(), talkative.NewFlushWriter(recorder, talkative.FLUSH_SENTENCE), "", nil, message)
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Hello world.", "Hello world. How are you?\n\n", "Hello world. How are you?\n\nBye"}, recorder.flushed)
+	}
+
+	hints := &hintRecorder{}
+
+	_, err = client.ChatTo(context.Background(), hints, "", nil, message)
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, []talkative.FlushHint{
+			talkative.FLUSH_CHUNK,
+			talkative.FLUSH_SENTENCE,
+			talkative.FLUSH_CHUNK,
+			talkative.FLUSH_PARAGRAPH,
+			talkative.FLUSH_CHUNK,
+			talkative.FLUSH_END,
+		}, hints.hints)
+	}
+
+	var sb strings.Builder
+
+	buffered := bufio.NewWriter(&sb)
+
+	_, err = client.ChatTo(context.Background(), talkative.NewFlushWriter(buffered, talkative.FLUSH_PARAGRAPH), "", nil, message)
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello world. How are you?\n\nBye", sb.String())
+	}
+}
@@ -0,0 +1,63 @@
+package talkative
+
+import (
+	"context"
+	"net/http"
+)
+
+// headersKey is the context key of the headers of a request.
+type headersKey struct{}
+
+// WithHeaders attaches the headers to every request of the client, i.e: "Authorization" or "X-API-Key"
+// expected by an authenticating reverse proxy in front of Ollama.
+//
+// It can be used multiple times, the values of a header set again replace the previous ones.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = http.Header{}
+		}
+
+		for key, value := range headers {
+			c.headers.Set(key, value)
+		}
+	}
+}
+
+// WithRequestHeaders returns a context attaching the headers to the requests bound to it, i.e: with ChatContext().
+//
+// Request headers take precedence over the headers of the client, and the headers of a parent context.
+func WithRequestHeaders(ctx context.Context, headers map[string]string) context.Context {
+	merged := http.Header{}
+
+	if parent, ok := ctx.Value(headersKey{}).(http.Header); ok {
+		merged = parent.Clone()
+	}
+
+	for key, value := range headers {
+		merged.Set(key, value)
+	}
+
+	return context.WithValue(ctx, headersKey{}, merged)
+}
+
+// setHeaders sets the headers of the client, its credentials and the headers of the request context on the request.
+func (c *Client) setHeaders(req *http.Request) error {
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+
+	if c.auth != nil {
+		if err := c.auth(req); err != nil {
+			return err
+		}
+	}
+
+	if headers, ok := req.Context().Value(headersKey{}).(http.Header); ok {
+		for key, values := range headers {
+			req.Header[key] = values
+		}
+	}
+
+	return nil
+}
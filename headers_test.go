@@ -0,0 +1,54 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithHeaders tests attaching the headers of the client and of the request context to requests.
+func TestWithHeaders(t *testing.T) {
+	headers := make(chan http.Header, 2)
+
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Clone()
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Done: true})
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithHeaders(map[string]string{
+		"Authorization": "Bearer secret",
+		"X-API-Key":     "key",
+	}))
+
+	message := talkative.ChatMessage{Role: talkative.USER, Content: "Hi"}
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+	assert.NoError(t, err)
+	<-done
+
+	header := <-headers
+	assert.Equal(t, "Bearer secret", header.Get("Authorization"))
+	assert.Equal(t, "key", header.Get("X-API-Key"))
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+
+	ctx := talkative.WithRequestHeaders(context.Background(), map[string]string{"X-API-Key": "tenant", "X-Request-ID": "1"})
+	ctx = talkative.WithRequestHeaders(ctx, map[string]string{"X-Trace": "abc"})
+
+	done, err = client.ChatContext(ctx, "", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+	assert.NoError(t, err)
+	<-done
+
+	header = <-headers
+	assert.Equal(t, "Bearer secret", header.Get("Authorization"))
+	assert.Equal(t, "tenant", header.Get("X-API-Key"))
+	assert.Equal(t, "1", header.Get("X-Request-ID"))
+	assert.Equal(t, "abc", header.Get("X-Trace"))
+}
@@ -0,0 +1,305 @@
+package talkative
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Define an enum-like type to represent the health state of an endpoint.
+type HealthState int
+
+const (
+	// The endpoint has not been probed yet.
+	HEALTH_UNKNOWN HealthState = iota
+
+	// The endpoint is ready to serve requests.
+	HEALTH_READY
+
+	// The endpoint failed too many consecutive probes.
+	HEALTH_NOT_READY
+)
+
+// String returns the name of the state.
+func (s HealthState) String() string {
+	switch s {
+	case HEALTH_READY:
+		return "ready"
+	case HEALTH_NOT_READY:
+		return "not ready"
+	default:
+		return "unknown"
+	}
+}
+
+// HealthOptions configures a HealthMonitor.
+type HealthOptions struct {
+	Interval         time.Duration // Interval between two probes of an endpoint, defaults to 10 seconds.
+	Timeout          time.Duration // Timeout of a single probe, defaults to 2 seconds.
+	FailureThreshold int           // Consecutive failed probes before an endpoint becomes not ready, defaults to 1.
+	SuccessThreshold int           // Consecutive successful probes before an endpoint becomes ready again, defaults to 1.
+	HTTPClient       *http.Client  // The HTTP client used by the default probe, defaults to http.DefaultClient.
+
+	// Probe checks a single endpoint, defaults to a GET request to /api/version expecting 200 OK.
+	Probe func(ctx context.Context, endpoint string) error
+
+	// OnTransition is invoked whenever the state of an endpoint changes, in the order of the changes of the endpoint.
+	// It must not probe the endpoint itself, i.e: with Check. (Optional)
+	OnTransition func(endpoint string, from, to HealthState, err error)
+}
+
+// HealthMonitor periodically probes endpoints and tracks their readiness.
+//
+// Endpoints start in the HEALTH_UNKNOWN state. An endpoint becomes not ready after FailureThreshold
+// consecutive failed probes and ready again after SuccessThreshold consecutive successful ones, so a
+// failing endpoint is taken out of rotation until it proves to be healthy again.
+type HealthMonitor struct {
+	endpoints []string
+	opts      HealthOptions
+
+	mu     sync.RWMutex
+	states map[string]*endpointHealth
+}
+
+// endpointHealth holds the health of a single endpoint.
+type endpointHealth struct {
+	updating sync.Mutex // Serialises the updates of the state with their transition callback.
+
+	state     HealthState
+	failures  int
+	successes int
+	lastErr   error
+	checkedAt time.Time
+}
+
+// NewHealthMonitor creates a new HealthMonitor for the given base URLs.
+func NewHealthMonitor(endpoints []string, opts HealthOptions) *HealthMonitor {
+	if opts.Interval <= 0 {
+		opts.Interval = 10 * time.Second
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Second
+	}
+
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = 1
+	}
+
+	if opts.SuccessThreshold <= 0 {
+		opts.SuccessThreshold = 1
+	}
+
+	m := &HealthMonitor{
+		opts:   opts,
+		states: map[string]*endpointHealth{},
+	}
+
+	if m.opts.Probe == nil {
+		m.opts.Probe = m.probe
+	}
+
+	for _, endpoint := range endpoints {
+		endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/")
+
+		m.endpoints = append(m.endpoints, endpoint)
+		m.states[endpoint] = &endpointHealth{}
+	}
+
+	return m
+}
+
+// HealthMonitor creates a new HealthMonitor probing the endpoint of this client.
+//
+// Unless a probe or an HTTP client is given, the version endpoint is requested like the other requests of
+// the client, with its headers, credentials, endpoint overrides and timeouts, but without failing over to its
+// fallback URLs so the health of the base URL itself is reported.
+func (c *Client) HealthMonitor(opts HealthOptions) *HealthMonitor {
+	if opts.Probe == nil && opts.HTTPClient == nil {
+		opts.Probe = c.probe
+	}
+
+	return NewHealthMonitor([]string{c.base}, opts)
+}
+
+// probe requests the version of the server of this client, see Client.HealthMonitor.
+func (c *Client) probe(ctx context.Context, endpoint string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.urls["version"], nil)
+
+	if err != nil {
+		return err
+	}
+
+	res, err := c.attempt(req)
+
+	if err != nil {
+		return err
+	}
+
+	if res, err = check(res); err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+// Start probes every endpoint in the background each interval until the context is cancelled.
+func (m *HealthMonitor) Start(ctx context.Context) {
+	for _, endpoint := range m.endpoints {
+		go func(endpoint string) {
+			ticker := time.NewTicker(m.opts.Interval)
+			defer ticker.Stop()
+
+			for {
+				m.check(ctx, endpoint)
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(endpoint)
+	}
+}
+
+// Check probes all endpoints once and waits for the probes to finish.
+func (m *HealthMonitor) Check(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, endpoint := range m.endpoints {
+		wg.Add(1)
+
+		go func(endpoint string) {
+			defer wg.Done()
+
+			m.check(ctx, endpoint)
+		}(endpoint)
+	}
+
+	wg.Wait()
+}
+
+// State returns the state of the endpoint, HEALTH_UNKNOWN for endpoints not monitored.
+func (m *HealthMonitor) State(endpoint string) HealthState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if health, ok := m.states[strings.TrimRight(endpoint, "/")]; ok {
+		return health.state
+	}
+
+	return HEALTH_UNKNOWN
+}
+
+// Ready reports whether the endpoint is ready.
+func (m *HealthMonitor) Ready(endpoint string) bool {
+	return m.State(endpoint) == HEALTH_READY
+}
+
+// NotReady reports whether the endpoint failed too many consecutive probes.
+func (m *HealthMonitor) NotReady(endpoint string) bool {
+	return m.State(endpoint) == HEALTH_NOT_READY
+}
+
+// ReadyEndpoints returns the ready endpoints, in the order they were given.
+func (m *HealthMonitor) ReadyEndpoints() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ready := []string{}
+
+	for _, endpoint := range m.endpoints {
+		if m.states[endpoint].state == HEALTH_READY {
+			ready = append(ready, endpoint)
+		}
+	}
+
+	return ready
+}
+
+// LastError returns the error of the last failed probe of the endpoint, nil when the last probe succeeded.
+func (m *HealthMonitor) LastError(endpoint string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if health, ok := m.states[strings.TrimRight(endpoint, "/")]; ok {
+		return health.lastErr
+	}
+
+	return nil
+}
+
+// check probes a single endpoint and updates its state.
+func (m *HealthMonitor) check(ctx context.Context, endpoint string) {
+	ctx, cancel := context.WithTimeout(ctx, m.opts.Timeout)
+	err := m.opts.Probe(ctx, endpoint)
+	cancel()
+
+	// the states are never added nor removed once the monitor is created
+	health := m.states[endpoint]
+
+	health.updating.Lock()
+	defer health.updating.Unlock()
+
+	m.mu.Lock()
+
+	from := health.state
+	health.lastErr = err
+	health.checkedAt = time.Now()
+
+	if err != nil {
+		health.failures++
+		health.successes = 0
+
+		if health.failures >= m.opts.FailureThreshold {
+			health.state = HEALTH_NOT_READY
+		}
+	} else {
+		health.successes++
+		health.failures = 0
+
+		if health.successes >= m.opts.SuccessThreshold || from == HEALTH_UNKNOWN {
+			health.state = HEALTH_READY
+		}
+	}
+
+	to := health.state
+	m.mu.Unlock()
+
+	if from != to && m.opts.OnTransition != nil {
+		m.opts.OnTransition(endpoint, from, to, err)
+	}
+}
+
+// probe is the default probe, requesting the version of the server.
+func (m *HealthMonitor) probe(ctx context.Context, endpoint string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/api/version", nil)
+
+	if err != nil {
+		return err
+	}
+
+	client := m.opts.HTTPClient
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: health probe responded with %s", ErrInvoke, res.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package talkative_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHealthMonitor tests readiness transitions of a monitored endpoint.
+func TestHealthMonitor(t *testing.T) {
+	healthy := true
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/version", r.URL.Path)
+
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.Write([]byte(`{"version":"0.3.0"}`))
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	transitions := []talkative.HealthState{}
+
+	monitor := client.HealthMonitor(talkative.HealthOptions{
+		FailureThreshold: 2,
+		SuccessThreshold: 2,
+		OnTransition: func(endpoint string, from, to talkative.HealthState, err error) {
+			assert.Equal(t, server.URL, endpoint)
+
+			transitions = append(transitions, to)
+		},
+	})
+
+	assert.Equal(t, talkative.HEALTH_UNKNOWN, monitor.State(server.URL))
+
+	monitor.Check(context.Background())
+	assert.True(t, monitor.Ready(server.URL))
+	assert.Equal(t, []string{server.URL}, monitor.ReadyEndpoints())
+
+	healthy = false
+
+	monitor.Check(context.Background())
+	assert.True(t, monitor.Ready(server.URL))
+
+	monitor.Check(context.Background())
+	assert.True(t, monitor.NotReady(server.URL))
+	assert.ErrorIs(t, monitor.LastError(server.URL), talkative.ErrInvoke)
+	assert.Empty(t, monitor.ReadyEndpoints())
+
+	healthy = true
+
+	monitor.Check(context.Background())
+	assert.True(t, monitor.NotReady(server.URL))
+
+	monitor.Check(context.Background())
+	assert.True(t, monitor.Ready(server.URL))
+
+	assert.Equal(t, []talkative.HealthState{
+		talkative.HEALTH_READY,
+		talkative.HEALTH_NOT_READY,
+		talkative.HEALTH_READY,
+	}, transitions)
+}
+
+// TestHealthMonitorTransitionOrder tests that the transitions of an endpoint probed concurrently are delivered
+// in the order of its state changes.
+func TestHealthMonitorTransitionOrder(t *testing.T) {
+	var (
+		probes      atomic.Int32
+		mu          sync.Mutex
+		transitions [][2]talkative.HealthState
+	)
+
+	monitor := talkative.NewHealthMonitor([]string{"http://ollama"}, talkative.HealthOptions{
+		Probe: func(ctx context.Context, endpoint string) error {
+			if probes.Add(1)%2 == 0 {
+				return errors.New("unavailable")
+			}
+
+			return nil
+		},
+		OnTransition: func(endpoint string, from, to talkative.HealthState, err error) {
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			transitions = append(transitions, [2]talkative.HealthState{from, to})
+			mu.Unlock()
+		},
+	})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			monitor.Check(context.Background())
+		}()
+	}
+
+	wg.Wait()
+
+	previous := talkative.HEALTH_UNKNOWN
+
+	for _, transition := range transitions {
+		assert.Equal(t, previous, transition[0])
+
+		previous = transition[1]
+	}
+
+	assert.Equal(t, monitor.State("http://ollama"), previous)
+}
+
+// TestSetHealthMonitor tests attaching health monitors to routers and shards while they are in use,
+// which the race detector reports unless the monitor is guarded.
+func TestSetHealthMonitor(t *testing.T) {
+	client, _ := talkative.New("http://ollama")
+	router := talkative.NewRouter(talkative.Target{Client: client, Model: "llama3"})
+	shards := talkative.NewShards(client)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			monitor := talkative.NewHealthMonitor([]string{"http://ollama"}, talkative.HealthOptions{})
+
+			router.SetHealthMonitor(monitor)
+			shards.SetHealthMonitor(monitor)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_, err := router.Route(talkative.Needs{})
+
+			assert.NoError(t, err)
+			assert.Same(t, client, shards.Pick("session"))
+		}()
+	}
+
+	wg.Wait()
+}
+
+// TestHealthMonitorAuth tests probing an endpoint behind an authenticating proxy with the credentials of the client.
+func TestHealthMonitorAuth(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("X-Tenant") != "acme" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		assert.Equal(t, "/gateway/version", r.URL.Path)
+
+		w.Write([]byte(`{"version":"0.3.0"}`))
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL,
+		talkative.WithBearerToken("secret"),
+		talkative.WithHeaders(map[string]string{"X-Tenant": "acme"}),
+		talkative.WithEndpointOverride("version", "/gateway/version"),
+	)
+
+	monitor := client.HealthMonitor(talkative.HealthOptions{})
+	monitor.Check(context.Background())
+
+	assert.True(t, monitor.Ready(server.URL))
+	assert.NoError(t, monitor.LastError(server.URL))
+
+	anonymous, _ := talkative.New(server.URL, talkative.WithEndpointOverride("version", "/gateway/version"))
+
+	monitor = anonymous.HealthMonitor(talkative.HealthOptions{})
+	monitor.Check(context.Background())
+
+	assert.True(t, monitor.NotReady(server.URL))
+	assert.ErrorIs(t, monitor.LastError(server.URL), talkative.ErrInvoke)
+}
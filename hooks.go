@@ -0,0 +1,103 @@
+package talkative
+
+import "context"
+
+// ChatHook function type used for inspecting or modifying chat requests before they are sent.
+//
+// Returning an error aborts the request, the error is returned to the caller of Chat/PlainChat.
+type ChatHook func(*ChatRequest) error
+
+// CompletionHook function type used for inspecting or modifying completion requests before they are sent.
+//
+// Returning an error aborts the request, the error is returned to the caller of Completion/PlainCompletion.
+type CompletionHook func(*CompletionRequest) error
+
+// WithChatHook registers a hook invoked, in registration order, before every chat request is sent.
+func WithChatHook(hook ChatHook) Option {
+	return func(c *Client) {
+		c.chatHooks = append(c.chatHooks, hook)
+	}
+}
+
+// WithCompletionHook registers a hook invoked, in registration order, before every completion request is sent.
+func WithCompletionHook(hook CompletionHook) Option {
+	return func(c *Client) {
+		c.completionHooks = append(c.completionHooks, hook)
+	}
+}
+
+// WithDefaultSystemPrompt prepends a system message with the prompt to every chat which doesn't
+// supply one, centralizing policy prompts for an organization.
+//
+// Set ChatParams.SkipDefaultSystem to opt a single request out.
+func WithDefaultSystemPrompt(prompt string) Option {
+	return WithChatHook(func(request *ChatRequest) error {
+		if request.ChatParams != nil && request.SkipDefaultSystem {
+			return nil
+		}
+
+		for _, message := range request.Messages {
+			if message.Role == SYSTEM {
+				return nil
+			}
+		}
+
+		request.Messages = append([]ChatMessage{{Role: SYSTEM, Content: prompt}}, request.Messages...)
+
+		return nil
+	})
+}
+
+// prepareChat resolves the model alias and runs the chat hooks against the request.
+//
+// The prompt prefix, if any, is applied before the hooks and observed after them, the context is sized last.
+// The resolved model is held as in-flight, see hold, and returned so the caller releases it once the request
+// is tracked, it is released already when the preparation fails.
+func (c *Client) prepareChat(ctx context.Context, request *ChatRequest) (string, error) {
+	held := c.hold(request.Model)
+	request.Model = held
+
+	if c.prefix != nil {
+		c.prefix.apply(request)
+	}
+
+	for _, hook := range c.chatHooks {
+		if err := hook(request); err != nil {
+			c.release(held)
+
+			return "", err
+		}
+	}
+
+	if c.prefix != nil {
+		c.prefix.observe(request)
+	}
+
+	if c.sizing != nil {
+		c.sizing.sizeChat(ctx, c, request)
+	}
+
+	return held, nil
+}
+
+// prepareCompletion resolves the model alias and runs the completion hooks against the request.
+//
+// The context is sized once the hooks ran. The resolved model is held like in prepareChat.
+func (c *Client) prepareCompletion(ctx context.Context, request *CompletionRequest) (string, error) {
+	held := c.hold(request.Model)
+	request.Model = held
+
+	for _, hook := range c.completionHooks {
+		if err := hook(request); err != nil {
+			c.release(held)
+
+			return "", err
+		}
+	}
+
+	if c.sizing != nil {
+		c.sizing.sizeCompletion(ctx, c, request)
+	}
+
+	return held, nil
+}
@@ -0,0 +1,49 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDefaultSystemPrompt tests prepending the default system prompt unless supplied or skipped.
+func TestDefaultSystemPrompt(t *testing.T) {
+	requests := make(chan talkative.ChatRequest, 1)
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		requests <- request
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithDefaultSystemPrompt("Be polite."))
+	cb := func(cr *talkative.ChatResponse, err error) {}
+	user := talkative.ChatMessage{Role: talkative.USER, Content: "Hi"}
+
+	done, _ := client.Chat("", cb, nil, user)
+	<-done
+
+	request := <-requests
+	assert.Equal(t, []talkative.ChatMessage{{Role: talkative.SYSTEM, Content: "Be polite."}, user}, request.Messages)
+
+	system := talkative.ChatMessage{Role: talkative.SYSTEM, Content: "Be brief."}
+	done, _ = client.Chat("", cb, nil, system, user)
+	<-done
+
+	request = <-requests
+	assert.Equal(t, []talkative.ChatMessage{system, user}, request.Messages)
+
+	done, _ = client.Chat("", cb, &talkative.ChatParams{SkipDefaultSystem: true}, user)
+	<-done
+
+	request = <-requests
+	assert.Equal(t, []talkative.ChatMessage{user}, request.Messages)
+}
@@ -0,0 +1,218 @@
+package talkative
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DEFAULT_LOCALE is the tag of the locale used when no registered locale matches.
+const DEFAULT_LOCALE = "en"
+
+// Locale formats dates and numbers and words instructions for a language, so the same assistant can be
+// shipped in several languages from the same prompt templates:
+//
+//	{{.Locale.Instruction}} Today is {{.Locale.Date .Data.Now}}, the total is {{.Locale.Number .Data.Total 2}}.
+//
+// See LocalizedTemplate.
+type Locale struct {
+	Tag         string // The BCP 47 tag of the locale, i.e: "fr" or "en-GB".
+	Language    string // The name of the language in the language itself, i.e: "français".
+	Instruction string // The instruction asking the model to answer in the language, i.e: "Réponds en français."
+
+	// DateLayout is the layout of dates, see time.Layout. The names of months and days must be spelled out,
+	// i.e: "January" and "Monday", to be translated with Months and Days.
+	DateLayout string
+
+	Months [12]string // The names of the months, from January. (Optional)
+	Days   [7]string  // The names of the days of the week, from Sunday. (Optional)
+
+	Decimal string // The decimal separator, i.e: ",".
+	Group   string // The separator of the groups of thousands, i.e: ",". (Optional)
+}
+
+// Date formats the time with the date layout of the locale.
+func (l *Locale) Date(t time.Time) string {
+	date := t.Format(l.DateLayout)
+
+	if l.Months[0] != "" {
+		date = strings.ReplaceAll(date, t.Month().String(), l.Months[t.Month()-1])
+	}
+
+	if l.Days[0] != "" {
+		date = strings.ReplaceAll(date, t.Weekday().String(), l.Days[t.Weekday()])
+	}
+
+	return date
+}
+
+// Number formats the number with the given number of decimals and the separators of the locale.
+func (l *Locale) Number(v float64, decimals int) string {
+	formatted := strconv.FormatFloat(v, 'f', decimals, 64)
+	sign := ""
+
+	if strings.HasPrefix(formatted, "-") {
+		sign, formatted = "-", formatted[1:]
+	}
+
+	integer, fraction, _ := strings.Cut(formatted, ".")
+
+	var sb strings.Builder
+
+	sb.WriteString(sign)
+
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteString(l.Group)
+		}
+
+		sb.WriteRune(digit)
+	}
+
+	if fraction != "" {
+		sb.WriteString(l.Decimal)
+		sb.WriteString(fraction)
+	}
+
+	return sb.String()
+}
+
+var (
+	localesMu sync.RWMutex
+
+	// locales holds the registered locales by lower-cased tag.
+	locales = map[string]*Locale{
+		"en": {
+			Tag:         "en",
+			Language:    "English",
+			Instruction: "Answer in English.",
+			DateLayout:  "Monday, January 2, 2006",
+			Decimal:     ".",
+			Group:       ",",
+		},
+		"en-gb": {
+			Tag:         "en-GB",
+			Language:    "English",
+			Instruction: "Answer in British English.",
+			DateLayout:  "Monday 2 January 2006",
+			Decimal:     ".",
+			Group:       ",",
+		},
+		"fr": {
+			Tag:         "fr",
+			Language:    "français",
+			Instruction: "Réponds en français.",
+			DateLayout:  "Monday 2 January 2006",
+			Months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+			Days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+			Decimal:     ",",
+			Group:       "\u202f",
+		},
+		"de": {
+			Tag:         "de",
+			Language:    "Deutsch",
+			Instruction: "Antworte auf Deutsch.",
+			DateLayout:  "Monday, 2. January 2006",
+			Months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+			Days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+			Decimal:     ",",
+			Group:       ".",
+		},
+		"es": {
+			Tag:         "es",
+			Language:    "español",
+			Instruction: "Responde en español.",
+			DateLayout:  "Monday, 2 de January de 2006",
+			Months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+			Days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+			Decimal:     ",",
+			Group:       ".",
+		},
+	}
+)
+
+// RegisterLocale registers the locale, replacing the locale registered with the same tag if any.
+func RegisterLocale(locale Locale) {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+
+	locales[strings.ToLower(locale.Tag)] = &locale
+}
+
+// LookupLocale returns the locale of the tag, falling back to its parent tags, i.e: "fr-CA" falls back to "fr",
+// and to DEFAULT_LOCALE when none is registered.
+func LookupLocale(tag string) *Locale {
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+
+	for _, candidate := range candidates(tag) {
+		if locale, ok := locales[candidate]; ok {
+			return locale
+		}
+	}
+
+	return locales[DEFAULT_LOCALE]
+}
+
+// candidates returns the lower-cased tag followed by its parent tags, i.e: "fr-ca" and "fr" for "fr_CA".
+func candidates(tag string) []string {
+	var tags []string
+
+	for tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-")); tag != ""; {
+		tags = append(tags, tag)
+
+		i := strings.LastIndex(tag, "-")
+
+		if i < 0 {
+			break
+		}
+
+		tag = tag[:i]
+	}
+
+	return tags
+}
+
+// LocalizedTemplate holds the sources of a prompt template by language tag, the source of the empty tag
+// being used for the languages without a source of their own, i.e:
+//
+//	greeting := talkative.LocalizedTemplate{
+//		"":   "{{.Locale.Instruction}} Greet {{.Data.Name}}.",
+//		"fr": "Salue {{.Data.Name}} chaleureusement.",
+//	}
+type LocalizedTemplate map[string]string
+
+// LocaleData is the data the sources of a LocalizedTemplate are rendered with.
+type LocaleData struct {
+	Locale *Locale // The locale of the rendering.
+	Data   any     // The data passed to Render.
+}
+
+// Source returns the source of the template for the tag, falling back to its parent tags and to the
+// source of the empty tag, reporting false when none matches.
+func (lt LocalizedTemplate) Source(tag string) (string, bool) {
+	for _, candidate := range candidates(tag) {
+		for key, source := range lt {
+			if strings.EqualFold(key, candidate) {
+				return source, true
+			}
+		}
+	}
+
+	source, ok := lt[""]
+
+	return source, ok
+}
+
+// Render renders the source of the template for the locale with DefaultTemplates, the data being available
+// as .Data and the locale as .Locale. It returns ErrMessage when the template has no source for the locale.
+func (lt LocalizedTemplate) Render(locale *Locale, data any) (string, error) {
+	source, ok := lt.Source(locale.Tag)
+
+	if !ok {
+		return "", ErrMessage
+	}
+
+	return DefaultTemplates.Render(source, LocaleData{Locale: locale, Data: data})
+}
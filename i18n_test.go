@@ -0,0 +1,74 @@
+package talkative_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLocale tests formatting dates and numbers for a locale.
+func TestLocale(t *testing.T) {
+	date := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+
+	en := talkative.LookupLocale("en-US")
+	{
+		assert.Equal(t, "en", en.Tag)
+		assert.Equal(t, "Tuesday, March 5, 2024", en.Date(date))
+		assert.Equal(t, "-1,234,567.89", en.Number(-1234567.891, 2))
+		assert.Equal(t, "12", en.Number(12, 0))
+	}
+
+	fr := talkative.LookupLocale("fr_CA")
+	{
+		assert.Equal(t, "fr", fr.Tag)
+		assert.Equal(t, "mardi 5 mars 2024", fr.Date(date))
+		assert.Equal(t, "1\u202f234,50", fr.Number(1234.5, 2))
+	}
+
+	de := talkative.LookupLocale("DE")
+	{
+		assert.Equal(t, "Dienstag, 5. März 2024", de.Date(date))
+		assert.Equal(t, "1.234,5", de.Number(1234.5, 1))
+	}
+
+	assert.Equal(t, "en", talkative.LookupLocale("xx").Tag)
+	assert.Equal(t, "en", talkative.LookupLocale("").Tag)
+
+	talkative.RegisterLocale(talkative.Locale{Tag: "it", Language: "italiano", Instruction: "Rispondi in italiano.", DateLayout: "2/1/2006", Decimal: ","})
+	{
+		it := talkative.LookupLocale("it-IT")
+
+		assert.Equal(t, "5/3/2024", it.Date(date))
+		assert.Equal(t, "1234,5", it.Number(1234.5, 1))
+	}
+}
+
+// TestLocalizedTemplate tests rendering the source of a template for a locale.
+func TestLocalizedTemplate(t *testing.T) {
+	greeting := talkative.LocalizedTemplate{
+		"":   "{{.Locale.Instruction}} Greet {{.Data.Name}}, the total is {{.Locale.Number .Data.Total 2}}.",
+		"fr": "Salue {{.Data.Name}}, le total est de {{.Locale.Number .Data.Total 2}}.",
+	}
+
+	data := map[string]any{"Name": "Ada", "Total": 1500.0}
+
+	text, err := greeting.Render(talkative.LookupLocale("fr-BE"), data)
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "Salue Ada, le total est de 1\u202f500,00.", text)
+	}
+
+	text, err = greeting.Render(talkative.LookupLocale("es"), data)
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "Responde en español. Greet Ada, the total is 1.500,00.", text)
+	}
+
+	_, err = talkative.LocalizedTemplate{"fr": "Bonjour"}.Render(talkative.LookupLocale("de"), nil)
+	{
+		assert.ErrorIs(t, err, talkative.ErrMessage)
+	}
+}
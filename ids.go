@@ -0,0 +1,119 @@
+package talkative
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// IDGenerator generates the identifiers of requests, jobs, outbox entries and conversations.
+//
+// Generators must be safe for concurrent use.
+type IDGenerator func() string
+
+// WithIDGenerator generates the identifiers issued by the client with the generator, i.e: UUIDv7, ULID or
+// snowflake IDs following the conventions of the host application. Defaults to RandomID.
+func WithIDGenerator(generator IDGenerator) Option {
+	return func(c *Client) {
+		c.ids = generator
+	}
+}
+
+// NewID returns a new identifier from the generator of the client.
+func (c *Client) NewID() string {
+	if c.ids == nil {
+		return RandomID()
+	}
+
+	return c.ids()
+}
+
+// NewConversation returns a new, empty conversation identified by the generator of the client.
+func (c *Client) NewConversation(model string) *Conversation {
+	now := time.Now()
+
+	return &Conversation{ID: c.NewID(), Model: model, CreatedAt: now, UpdatedAt: now}
+}
+
+// RandomID returns 16 random bytes, hex encoded. It is the default IDGenerator.
+func RandomID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+// UUIDv7 returns a new version 7 UUID, whose IDs sort by creation time.
+//
+// IDs generated within the same millisecond are kept increasing by a counter, as allowed by RFC 9562.
+func UUIDv7() string {
+	var id [16]byte
+
+	millis, counter := monotonic()
+
+	binary.BigEndian.PutUint64(id[:8], millis<<16)
+	rand.Read(id[8:])
+
+	id[6] = 0x70 | byte(counter>>8)&0x0f // Version 7 and the high bits of the counter.
+	id[7] = byte(counter)
+	id[8] = 0x80 | id[8]&0x3f // RFC 9562 variant.
+
+	encoded := hex.EncodeToString(id[:])
+
+	return encoded[:8] + "-" + encoded[8:12] + "-" + encoded[12:16] + "-" + encoded[16:20] + "-" + encoded[20:]
+}
+
+// ULID returns a new ULID, 26 characters in Crockford's base32 whose IDs sort by creation time.
+func ULID() string {
+	const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	var id [16]byte
+
+	millis, counter := monotonic()
+
+	binary.BigEndian.PutUint64(id[:8], millis<<16|uint64(counter>>4))
+	rand.Read(id[8:])
+
+	id[8] = byte(counter<<4) | id[8]&0x0f // The low bits of the counter lead the random part.
+
+	// 128 bits are encoded as 26 characters of 5 bits, the first one holding the 3 highest bits.
+	hi, lo := binary.BigEndian.Uint64(id[:8]), binary.BigEndian.Uint64(id[8:])
+	encoded := make([]byte, 26)
+
+	for i := 25; i >= 0; i-- {
+		encoded[i] = alphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(encoded)
+}
+
+// clock holds the state keeping time ordered IDs of the same millisecond increasing.
+var clock struct {
+	sync.Mutex
+	millis  uint64
+	counter uint16
+}
+
+// monotonic returns the current Unix time in milliseconds and a 12 bits counter of the IDs generated within it.
+func monotonic() (uint64, uint16) {
+	clock.Lock()
+	defer clock.Unlock()
+
+	millis := uint64(time.Now().UnixMilli())
+
+	switch {
+	case millis > clock.millis:
+		clock.millis, clock.counter = millis, 0
+	case clock.counter < 0x0fff:
+		clock.counter++
+	default:
+		// The counter overflowed, borrow the next millisecond.
+		clock.millis, clock.counter = clock.millis+1, 0
+	}
+
+	return clock.millis, clock.counter
+}
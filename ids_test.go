@@ -0,0 +1,57 @@
+package talkative_test
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTimeOrderedIDs tests UUIDv7 and ULID identifiers are well formed and sort by creation.
+func TestTimeOrderedIDs(t *testing.T) {
+	formats := map[string]*regexp.Regexp{
+		"uuidv7": regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		"ulid":   regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`),
+	}
+
+	for name, generator := range map[string]talkative.IDGenerator{"uuidv7": talkative.UUIDv7, "ulid": talkative.ULID} {
+		ids := make([]string, 5000)
+
+		for i := range ids {
+			ids[i] = generator()
+			assert.Regexp(t, formats[name], ids[i])
+		}
+
+		assert.True(t, sort.StringsAreSorted(ids), name)
+	}
+
+	assert.Len(t, talkative.RandomID(), 32)
+	assert.NotEqual(t, talkative.RandomID(), talkative.RandomID())
+}
+
+// TestWithIDGenerator tests identifying requests and conversations with a custom generator.
+func TestWithIDGenerator(t *testing.T) {
+	server := streamServer(talkative.ChatResponse{Done: true})
+	defer server.Close()
+
+	next := 0
+	client, _ := talkative.New(server.URL, talkative.WithIDGenerator(func() string {
+		next++
+
+		return fmt.Sprintf("id-%d", next)
+	}))
+
+	ids := []string{}
+	client.Subscribe(func(e talkative.Event) { ids = append(ids, e.ID) })
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	assert.NoError(t, err)
+	<-done
+
+	assert.Equal(t, []string{"id-1", "id-1", "id-1"}, ids)
+	assert.Equal(t, "id-2", client.NewConversation("llama2").ID)
+}
@@ -0,0 +1,156 @@
+package talkative
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// INJECTION_PLACEHOLDER replaces the instruction-like content neutralized by an InjectionScanner.
+const INJECTION_PLACEHOLDER = "[removed instruction]"
+
+// Define an enum-like type to represent what happens to retrieved documents containing instruction-like content.
+type InjectionAction int
+
+const (
+	// The documents are kept unchanged, their findings are reported to the OnDetect handler and their
+	// metadata is annotated with the "injection" key listing the matched rules.
+	INJECTION_FLAG InjectionAction = iota
+
+	// The matches are replaced with INJECTION_PLACEHOLDER, in addition to flagging the documents.
+	INJECTION_NEUTRALIZE
+
+	// The documents are dropped from the prompt.
+	INJECTION_DROP
+)
+
+// InjectionFinding represents a retrieved document containing instruction-like content.
+type InjectionFinding struct {
+	Part    PromptPart // The document part, as it was before being neutralized.
+	Matches []PIIMatch // The instruction-like content found, typed after the rule which matched.
+}
+
+// DefaultInjectionDetectors returns heuristic detectors of content trying to override the instructions
+// of the model, i.e: "ignore previous instructions", role markers or requests to reveal the system prompt.
+//
+// Heuristics catch the common, naive attempts only. They complement, and do not replace, keeping retrieved
+// content clearly separated from the instructions.
+func DefaultInjectionDetectors() []Detector {
+	return []Detector{
+		&RegexDetector{
+			Type:    "ignore_instructions",
+			Pattern: regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget|override)\b[^.\n]{0,40}?\b(?:previous|prior|above|earlier|all|any|your|the)\b[^.\n]{0,20}?\b(?:instructions?|prompts?|rules|directions|guidelines)\b`),
+		},
+		&RegexDetector{
+			Type:    "role_override",
+			Pattern: regexp.MustCompile(`(?i)\byou are now\b|\bfrom now on,? you\b|\bact as\b[^.\n]{0,30}?\b(?:unrestricted|unfiltered|jailbroken|DAN)\b`),
+		},
+		&RegexDetector{
+			Type:    "prompt_leak",
+			Pattern: regexp.MustCompile(`(?i)\b(?:reveal|print|show|repeat|output|disclose)\b[^.\n]{0,30}?\b(?:system prompt|hidden instructions|initial instructions)\b`),
+		},
+		&RegexDetector{
+			Type:    "role_marker",
+			Pattern: regexp.MustCompile(`(?im)^\s*(?:system|assistant)\s*:|<\|(?:im_start|im_end|system|endoftext)\|>|\[/?INST\]|<</?SYS>>`),
+		},
+		&RegexDetector{
+			Type:    "new_instructions",
+			Pattern: regexp.MustCompile(`(?i)\bnew instructions?\s*:`),
+		},
+	}
+}
+
+// InjectionScanner scans the retrieved documents of a prompt for instruction-like content before the prompt
+// is assembled, flagging, neutralizing or dropping them.
+type InjectionScanner struct {
+	Detectors []Detector                        // The detectors to run, defaults to DefaultInjectionDetectors().
+	Action    InjectionAction                   // What to do with documents containing matches.
+	OnDetect  func(findings []InjectionFinding) // Invoked with the findings of every prompt containing some. (Optional)
+}
+
+// Scan returns the instruction-like content of the text, ordered by position.
+//
+// Overlapping matches are resolved in favour of the earliest detector.
+func (s *InjectionScanner) Scan(text string) []PIIMatch {
+	detectors := s.Detectors
+
+	if detectors == nil {
+		detectors = DefaultInjectionDetectors()
+	}
+
+	guard := &PIIGuard{Detectors: detectors}
+
+	return guard.Scan(text)
+}
+
+// Neutralize returns the text with the instruction-like content replaced with INJECTION_PLACEHOLDER.
+func (s *InjectionScanner) Neutralize(text string) string {
+	matches := s.Scan(text)
+
+	for i := len(matches) - 1; i >= 0; i-- {
+		text = text[:matches[i].Start] + INJECTION_PLACEHOLDER + text[matches[i].End:]
+	}
+
+	return text
+}
+
+// Apply returns a new prompt with the scanner applied to its document parts, along with the findings.
+//
+// Other parts, including the user input, are left untouched: they are not retrieved content.
+func (s *InjectionScanner) Apply(prompt *Prompt) (*Prompt, []InjectionFinding) {
+	findings := []InjectionFinding{}
+
+	scanned := prompt.Map(func(part PromptPart) (PromptPart, bool) {
+		if part.Kind != PART_DOCUMENT {
+			return part, true
+		}
+
+		matches := s.Scan(part.Content)
+
+		if len(matches) == 0 {
+			return part, true
+		}
+
+		findings = append(findings, InjectionFinding{Part: part, Matches: matches})
+
+		if s.Action == INJECTION_DROP {
+			return part, false
+		}
+
+		if s.Action == INJECTION_NEUTRALIZE {
+			part.Content = s.Neutralize(part.Content)
+		}
+
+		part.Metadata = flagInjection(part.Metadata, matches)
+
+		return part, true
+	})
+
+	if len(findings) > 0 && s.OnDetect != nil {
+		s.OnDetect(findings)
+	}
+
+	return scanned, findings
+}
+
+// flagInjection returns a copy of the metadata with the "injection" key listing the types of the matches.
+func flagInjection(metadata map[string]string, matches []PIIMatch) map[string]string {
+	flagged := make(map[string]string, len(metadata)+1)
+
+	for key, value := range metadata {
+		flagged[key] = value
+	}
+
+	types := []string{}
+
+	for _, match := range matches {
+		if !contains(types, match.Type) {
+			types = append(types, match.Type)
+		}
+	}
+
+	sort.Strings(types)
+	flagged["injection"] = strings.Join(types, ",")
+
+	return flagged
+}
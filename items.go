@@ -0,0 +1,197 @@
+package talkative
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// ErrIncompleteArray is reported when a stream ends before the JSON array it contains is closed.
+var ErrIncompleteArray = newError("stream ended before the json array was complete")
+
+// ItemStream incrementally parses the elements of a JSON array out of streamed text and delivers
+// every element as a typed value as soon as it is complete.
+//
+// It is meant for structured list generation (i.e: with format "json" and a prompt asking for an array),
+// so applications can render items progressively instead of waiting for the whole response. Any text
+// before the opening bracket, such as a markdown code fence, is ignored, as is any text after the
+// closing bracket.
+//
+// Items are sent on an unbuffered channel unless a buffer is given, a slow consumer therefore slows
+// down the processing of the stream.
+type ItemStream[T any] struct {
+	items chan T
+	once  sync.Once
+	mu    sync.Mutex
+	err   error
+
+	started  bool // Whether the opening bracket has been seen.
+	finished bool // Whether the closing bracket has been seen.
+	depth    int  // Nesting depth, 1 inside the top level array.
+	inString bool // Whether the parser is inside a string literal.
+	escaped  bool // Whether the previous character was a backslash inside a string.
+	element  bytes.Buffer
+}
+
+// NewItemStream creates a new ItemStream, the optional buffer sets the capacity of the items channel.
+func NewItemStream[T any](buffer ...int) *ItemStream[T] {
+	size := 0
+
+	if len(buffer) > 0 {
+		size = buffer[0]
+	}
+
+	return &ItemStream[T]{items: make(chan T, size)}
+}
+
+// Items returns the channel delivering the parsed elements, it is closed once the array is complete,
+// the stream ended or failed.
+func (s *ItemStream[T]) Items() <-chan T {
+	return s.items
+}
+
+// Err returns the error which ended the stream, if any. It should be checked once Items is closed.
+func (s *ItemStream[T]) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// Write parses the chunk of streamed text, sending the elements completed by it.
+func (s *ItemStream[T]) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if s.finished {
+			break
+		}
+
+		if err := s.parse(b); err != nil {
+			s.fail(err)
+
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close ends the stream, reporting ErrIncompleteArray when the array has not been closed.
+func (s *ItemStream[T]) Close() error {
+	if !s.finished {
+		s.fail(ErrIncompleteArray)
+	}
+
+	s.once.Do(func() { close(s.items) })
+
+	return s.Err()
+}
+
+// ChatCallback returns a ChatCallBack feeding the content of chat responses to the stream.
+func (s *ItemStream[T]) ChatCallback() ChatCallBack {
+	return func(cr *ChatResponse, err error) {
+		s.feed(cr != nil && cr.Done, err, func() string { return cr.Message.Content })
+	}
+}
+
+// CompletionCallback returns a CompletionCallback feeding the content of completion responses to the stream.
+func (s *ItemStream[T]) CompletionCallback() CompletionCallback {
+	return func(cr *CompletionResponse, err error) {
+		s.feed(cr != nil && cr.Done, err, func() string { return cr.Response })
+	}
+}
+
+// feed writes the content of a response and closes the stream on errors and on the final response.
+func (s *ItemStream[T]) feed(done bool, err error, content func() string) {
+	if err != nil {
+		s.fail(err)
+		s.Close()
+
+		return
+	}
+
+	s.Write([]byte(content()))
+
+	if done || s.finished {
+		s.Close()
+	}
+}
+
+// parse processes a single byte of the streamed text.
+func (s *ItemStream[T]) parse(b byte) error {
+	if !s.started {
+		if b == '[' {
+			s.started = true
+			s.depth = 1
+		}
+
+		return nil
+	}
+
+	if s.inString {
+		s.element.WriteByte(b)
+
+		if s.escaped {
+			s.escaped = false
+		} else if b == '\\' {
+			s.escaped = true
+		} else if b == '"' {
+			s.inString = false
+		}
+
+		return nil
+	}
+
+	switch b {
+	case '"':
+		s.inString = true
+	case '[', '{':
+		s.depth++
+	case ']', '}':
+		s.depth--
+	}
+
+	if s.depth == 0 {
+		s.finished = true
+
+		return s.emit()
+	}
+
+	if s.depth == 1 && b == ',' {
+		return s.emit()
+	}
+
+	s.element.WriteByte(b)
+
+	return nil
+}
+
+// emit decodes the buffered element and sends it.
+func (s *ItemStream[T]) emit() error {
+	raw := bytes.TrimSpace(s.element.Bytes())
+	s.element.Reset()
+
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var item T
+
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return fmt.Errorf("%w: %w", ErrDecoding, err)
+	}
+
+	s.items <- item
+
+	return nil
+}
+
+// fail records the first error of the stream.
+func (s *ItemStream[T]) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err == nil {
+		s.err = err
+	}
+}
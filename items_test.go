@@ -0,0 +1,62 @@
+package talkative_test
+
+import (
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestItemStream tests progressive parsing of a JSON array split over several chunks.
+func TestItemStream(t *testing.T) {
+	type city struct {
+		Name    string   `json:"name"`
+		Tags    []string `json:"tags"`
+		Country string   `json:"country"`
+	}
+
+	chunks := []string{
+		"```json\n[\n  {\"name\": \"Par",
+		"is\", \"tags\": [\"capital\", \"[europe]\"], \"country\": \"Fr",
+		"ance\"},\n  {\"name\": \"Lyon, \\\"the\\\" city\", \"tags\": []}",
+		"\n]\n```",
+	}
+
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: chunks[0]}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: chunks[1]}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: chunks[2]}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: chunks[3]}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	stream := talkative.NewItemStream[city]()
+
+	_, err := client.Chat("", stream.ChatCallback(), nil, talkative.ChatMessage{Role: talkative.USER, Content: "List cities"})
+	{
+		assert.NoError(t, err)
+	}
+
+	cities := []city{}
+
+	for item := range stream.Items() {
+		cities = append(cities, item)
+	}
+
+	assert.NoError(t, stream.Err())
+	assert.Equal(t, []city{
+		{Name: "Paris", Tags: []string{"capital", "[europe]"}, Country: "France"},
+		{Name: `Lyon, "the" city`, Tags: []string{}},
+	}, cities)
+
+	t.Run("item-stream-incomplete", func(t *testing.T) {
+		stream := talkative.NewItemStream[int](10)
+
+		stream.Write([]byte("[1, 2, 3"))
+
+		assert.ErrorIs(t, stream.Close(), talkative.ErrIncompleteArray)
+		assert.Len(t, stream.Items(), 2)
+	})
+}
@@ -0,0 +1,172 @@
+package jobs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DEFAULT_CHECKPOINT_EVERY is the number of chunks received between two checkpoints when not configured.
+const DEFAULT_CHECKPOINT_EVERY = 20
+
+// Checkpoint is the persisted state of a job, allowing it to be resumed by another queue after a crash.
+type Checkpoint struct {
+	ID        string    `json:"id"`              // The job ID.
+	Request   Request   `json:"request"`         // The request run by the job.
+	Status    Status    `json:"status"`          // The status of the job when the checkpoint was taken.
+	Output    string    `json:"output"`          // The output accumulated when the checkpoint was taken.
+	Chunks    int       `json:"chunks"`          // Number of chunks received when the checkpoint was taken.
+	Context   []int     `json:"context"`         // The completion context returned on completion, for completion jobs.
+	Error     string    `json:"error,omitempty"` // The error of a failed job.
+	CreatedAt time.Time `json:"created_at"`      // Time the job was submitted.
+	UpdatedAt time.Time `json:"updated_at"`      // Time the checkpoint was taken.
+}
+
+// CheckpointStore persists checkpoints. Implementations must be safe for concurrent use.
+type CheckpointStore interface {
+	// Save adds the checkpoint, or replaces the checkpoint of the same job.
+	Save(checkpoint Checkpoint) error
+
+	// List returns the checkpoints of all jobs.
+	List() ([]Checkpoint, error)
+
+	// Delete removes the checkpoint of the job with the given ID.
+	Delete(id string) error
+}
+
+// MemoryCheckpointStore is a CheckpointStore keeping the checkpoints in memory.
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemoryCheckpointStore creates a new, empty MemoryCheckpointStore.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: map[string]Checkpoint{}}
+}
+
+// Save adds or replaces the checkpoint.
+func (s *MemoryCheckpointStore) Save(checkpoint Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoints[checkpoint.ID] = checkpoint
+
+	return nil
+}
+
+// List returns all checkpoints.
+func (s *MemoryCheckpointStore) List() ([]Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints := make([]Checkpoint, 0, len(s.checkpoints))
+
+	for _, checkpoint := range s.checkpoints {
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints, nil
+}
+
+// Delete removes the checkpoint of the job.
+func (s *MemoryCheckpointStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.checkpoints, id)
+
+	return nil
+}
+
+// FileCheckpointStore is a durable CheckpointStore keeping every checkpoint as a JSON file in a directory.
+type FileCheckpointStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileCheckpointStore creates a new FileCheckpointStore in dir, creating the directory when missing.
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+// Save atomically writes the checkpoint to its file.
+func (s *FileCheckpointStore) Save(checkpoint Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(checkpoint)
+
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path(checkpoint.ID) + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path(checkpoint.ID))
+}
+
+// List reads all checkpoints.
+func (s *FileCheckpointStore) List() ([]Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make([]Checkpoint, 0, len(paths))
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var checkpoint Checkpoint
+
+		if err := json.Unmarshal(data, &checkpoint); err != nil {
+			return nil, err
+		}
+
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	return checkpoints, nil
+}
+
+// Delete removes the file of the checkpoint.
+func (s *FileCheckpointStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// path returns the file path of the checkpoint of the job with the given ID, named after the hash of the ID so
+// distinct IDs never share a file.
+func (s *FileCheckpointStore) path(id string) string {
+	sum := sha256.Sum256([]byte(id))
+
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
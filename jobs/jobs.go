@@ -0,0 +1,549 @@
+// Package jobs provides a lightweight job queue front-end for long generations.
+//
+// Submitting a request returns a job ID immediately while the generation runs in the background.
+// The accumulated output can be retrieved or streamed later using the ID, which suits HTTP front-ends
+// that cannot hold a connection open for the whole duration of a generation.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rifaideen/talkative"
+)
+
+// Pre-defined errors used by the job queue.
+var (
+	ErrNotFound = errors.New("job not found")                                         // Error for unknown job IDs.
+	ErrRequest  = errors.New("request must contain either chat messages or a prompt") // Error for invalid requests.
+)
+
+// Define an enum-like type to represent the status of a job.
+type Status string
+
+const (
+	// The job is waiting for a free worker.
+	PENDING Status = "pending"
+
+	// The generation is in progress.
+	RUNNING Status = "running"
+
+	// The generation completed successfully.
+	COMPLETED Status = "completed"
+
+	// The generation failed, see Job.Err. Cancelled jobs fail with an error matching context.Canceled.
+	FAILED Status = "failed"
+)
+
+// Request describes the generation to be run by a job, either a chat or a completion.
+type Request struct {
+	Model      string                       // The model to use, defaults to the client's default.
+	Messages   []talkative.ChatMessage      // The chat messages, for chat jobs.
+	ChatParams *talkative.ChatParams        // The additional chat parameters. (Optional)
+	Completion *talkative.CompletionMessage // The completion message, for completion jobs.
+}
+
+// Job is a snapshot of the state of a job.
+type Job struct {
+	ID        string    // The job ID.
+	Status    Status    // The current status.
+	Output    string    // The output accumulated so far.
+	Chunks    int       // Number of chunks received so far.
+	Context   []int     // The completion context returned on completion, for completion jobs.
+	Err       error     // The error of a failed job.
+	CreatedAt time.Time // Time the job was submitted.
+	UpdatedAt time.Time // Time the job was last updated.
+}
+
+// Progress is reported for every status change and every received chunk of a job.
+type Progress struct {
+	ID     string // The job ID.
+	Status Status // The status of the job.
+	Delta  string // The content of the received chunk, empty for status changes.
+	Chunks int    // Number of chunks received so far.
+}
+
+// Options configures the job queue.
+type Options struct {
+	Workers    int            // Maximum number of concurrently running generations, defaults to 1.
+	OnProgress func(Progress) // Invoked on progress of any job. (Optional)
+
+	Checkpoints     CheckpointStore // Persists the state of jobs so they can be resumed after a crash. (Optional)
+	CheckpointEvery int             // Number of chunks between two checkpoints, defaults to DEFAULT_CHECKPOINT_EVERY.
+}
+
+// Queue runs generations in the background and keeps their results in memory.
+type Queue struct {
+	client  *talkative.Client
+	opts    Options
+	workers chan struct{}
+
+	mu   sync.Mutex
+	jobs map[string]*job
+}
+
+// job holds the state of a job, guarded by the queue's mutex.
+type job struct {
+	Job
+
+	request Request
+	output  strings.Builder    // Accumulates the output, Job.Output being a snapshot of it.
+	ctx     context.Context    // Bound to the generation of the job, see Cancel.
+	cancel  context.CancelFunc // Cancels the context of the job.
+	changed chan struct{}      // Closed and replaced on every change to wake up waiters.
+	removed bool               // Whether the job has been removed, its checkpoint must not be saved again.
+	saving  sync.Mutex         // Serialises the checkpoints of the job with the deletion of its checkpoint.
+}
+
+// newJob creates the state of a job running the request.
+func newJob(snapshot Job, request Request) *job {
+	j := &job{
+		Job:     snapshot,
+		request: request,
+		changed: make(chan struct{}),
+	}
+
+	j.output.WriteString(snapshot.Output)
+	j.ctx, j.cancel = context.WithCancel(context.Background())
+
+	return j
+}
+
+// New creates a new job queue running generations through the client.
+func New(client *talkative.Client, opts Options) *Queue {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+
+	if opts.CheckpointEvery <= 0 {
+		opts.CheckpointEvery = DEFAULT_CHECKPOINT_EVERY
+	}
+
+	return &Queue{
+		client:  client,
+		opts:    opts,
+		workers: make(chan struct{}, opts.Workers),
+		jobs:    map[string]*job{},
+	}
+}
+
+// Submit enqueues the request and returns the ID of the job running it.
+func (q *Queue) Submit(request Request) (string, error) {
+	if (len(request.Messages) == 0) == (request.Completion == nil) {
+		return "", ErrRequest
+	}
+
+	now := time.Now()
+	j := newJob(Job{
+		ID:        q.client.NewID(),
+		Status:    PENDING,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, request)
+
+	q.mu.Lock()
+	q.jobs[j.ID] = j
+	checkpoint := q.checkpoint(j)
+	q.mu.Unlock()
+
+	q.save(j, checkpoint)
+	q.progress(Progress{ID: j.ID, Status: PENDING})
+
+	go q.run(j)
+
+	return j.ID, nil
+}
+
+// Resume restores the jobs from the configured checkpoint store, i.e: after a crash or restart.
+//
+// Finished jobs are restored as is so their results can be retrieved. Unfinished chat jobs continue
+// from their checkpointed output, which is sent back as a partial assistant message for the model to
+// complete. Unfinished completion jobs continue from their checkpointed output when the request is raw,
+// otherwise they are restarted from scratch as the prompt template cannot be continued.
+//
+// It returns the IDs of the jobs which have been resumed.
+func (q *Queue) Resume() ([]string, error) {
+	if q.opts.Checkpoints == nil {
+		return nil, nil
+	}
+
+	checkpoints, err := q.opts.Checkpoints.List()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resumed := []string{}
+
+	for _, checkpoint := range checkpoints {
+		q.mu.Lock()
+
+		if _, ok := q.jobs[checkpoint.ID]; ok {
+			q.mu.Unlock()
+
+			continue
+		}
+
+		j := newJob(Job{
+			ID:        checkpoint.ID,
+			Status:    checkpoint.Status,
+			Output:    checkpoint.Output,
+			Chunks:    checkpoint.Chunks,
+			Context:   checkpoint.Context,
+			CreatedAt: checkpoint.CreatedAt,
+			UpdatedAt: checkpoint.UpdatedAt,
+		}, checkpoint.Request)
+
+		if checkpoint.Error != "" {
+			j.Err = errors.New(checkpoint.Error)
+		}
+
+		unfinished := j.Status != COMPLETED && j.Status != FAILED
+
+		if unfinished {
+			j.Status = PENDING
+		}
+
+		q.jobs[j.ID] = j
+		q.mu.Unlock()
+
+		if unfinished {
+			resumed = append(resumed, j.ID)
+
+			go q.run(j)
+		}
+	}
+
+	return resumed, nil
+}
+
+// Get returns a snapshot of the job with the given ID.
+func (q *Queue) Get(id string) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, ok := q.jobs[id]
+
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+
+	return j.Job, nil
+}
+
+// Result waits until the job with the given ID finished and returns its final snapshot.
+func (q *Queue) Result(ctx context.Context, id string) (Job, error) {
+	for {
+		q.mu.Lock()
+		j, ok := q.jobs[id]
+
+		if !ok {
+			q.mu.Unlock()
+
+			return Job{}, ErrNotFound
+		}
+
+		snapshot, changed := j.Job, j.changed
+		q.mu.Unlock()
+
+		if snapshot.Status == COMPLETED || snapshot.Status == FAILED {
+			return snapshot, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return snapshot, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Stream streams the output of the job with the given ID, starting from the beginning.
+//
+// The output accumulated so far is delivered first, followed by new output as it arrives.
+// The channel is closed when the job finished, was removed or the context is cancelled, use Get to inspect
+// the outcome.
+func (q *Queue) Stream(ctx context.Context, id string) (<-chan string, error) {
+	if _, err := q.Get(id); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan string)
+
+	go func() {
+		defer close(ch)
+
+		offset := 0
+
+		for {
+			q.mu.Lock()
+			j, ok := q.jobs[id]
+
+			if !ok {
+				q.mu.Unlock()
+
+				return
+			}
+
+			output, status, changed := j.Output, j.Status, j.changed
+			q.mu.Unlock()
+
+			if len(output) > offset {
+				select {
+				case <-ctx.Done():
+					return
+				case ch <- output[offset:]:
+				}
+
+				offset = len(output)
+			}
+
+			if status == COMPLETED || status == FAILED {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// Cancel aborts the generation of the job with the given ID, which then fails with an error matching
+// context.Canceled. Cancelling a finished job is a no-op.
+func (q *Queue) Cancel(id string) error {
+	q.mu.Lock()
+	j, ok := q.jobs[id]
+	q.mu.Unlock()
+
+	if !ok {
+		return ErrNotFound
+	}
+
+	j.cancel()
+
+	return nil
+}
+
+// Remove forgets the job with the given ID, i.e: once its result has been delivered.
+//
+// The checkpoint of the job is removed as well, and the streams of the job are closed. A running job
+// is cancelled and no longer checkpointed.
+func (q *Queue) Remove(id string) error {
+	q.mu.Lock()
+
+	j, ok := q.jobs[id]
+
+	if ok {
+		delete(q.jobs, id)
+		j.removed = true
+
+		// wake up the waiters so they notice the removal
+		close(j.changed)
+		j.changed = make(chan struct{})
+	}
+
+	q.mu.Unlock()
+
+	if ok {
+		j.cancel()
+	}
+
+	if q.opts.Checkpoints == nil {
+		return nil
+	}
+
+	// wait for a checkpoint being saved, so it doesn't outlive the deletion
+	if ok {
+		j.saving.Lock()
+		defer j.saving.Unlock()
+	}
+
+	return q.opts.Checkpoints.Delete(id)
+}
+
+// run waits for a free worker and runs the generation of the job, unless it is cancelled first.
+func (q *Queue) run(j *job) {
+	defer j.cancel()
+
+	select {
+	case q.workers <- struct{}{}:
+		defer func() { <-q.workers }()
+	case <-j.ctx.Done():
+		q.update(j, func(j *job) {
+			j.Err = j.ctx.Err()
+			j.Status = FAILED
+		})
+
+		return
+	}
+
+	var (
+		messages   = j.request.Messages
+		completion = j.request.Completion
+	)
+
+	q.update(j, func(j *job) {
+		j.Status = RUNNING
+
+		if j.Output == "" {
+			return
+		}
+
+		// continue from the checkpointed output of a resumed job
+		if len(messages) > 0 {
+			messages = append(append([]talkative.ChatMessage{}, messages...), talkative.ChatMessage{
+				Role:    talkative.ASSISTANT,
+				Content: j.Output,
+			})
+		} else if completion.CompletionParams != nil && completion.Raw {
+			resumed := *completion
+			resumed.Prompt += j.Output
+			completion = &resumed
+		} else {
+			j.output.Reset()
+			j.Output = ""
+			j.Chunks = 0
+		}
+	})
+
+	var (
+		done <-chan bool
+		err  error
+	)
+
+	if len(messages) > 0 {
+		done, err = q.client.ChatContext(j.ctx, j.request.Model, func(cr *talkative.ChatResponse, err error) {
+			if err != nil {
+				q.fail(j, err)
+
+				return
+			}
+
+			q.chunk(j, cr.Message.Content)
+		}, j.request.ChatParams, messages...)
+	} else {
+		done, err = q.client.CompletionContext(j.ctx, j.request.Model, func(cr *talkative.CompletionResponse, err error) {
+			if err != nil {
+				q.fail(j, err)
+
+				return
+			}
+
+			if cr.Done {
+				q.update(j, func(j *job) { j.Context = cr.Context })
+			}
+
+			q.chunk(j, cr.Response)
+		}, completion)
+	}
+
+	if err == nil {
+		<-done
+	}
+
+	q.update(j, func(j *job) {
+		if err != nil {
+			j.Err = err
+		}
+
+		if j.Err != nil {
+			j.Status = FAILED
+		} else {
+			j.Status = COMPLETED
+		}
+	})
+}
+
+// chunk records a streamed chunk of the job.
+func (q *Queue) chunk(j *job, content string) {
+	q.update(j, func(j *job) {
+		j.output.WriteString(content)
+		j.Output = j.output.String()
+		j.Chunks++
+	}, content)
+}
+
+// fail records the streaming error of the job.
+func (q *Queue) fail(j *job, err error) {
+	q.update(j, func(j *job) {
+		j.Err = err
+	})
+}
+
+// update applies the change to the job, wakes up its waiters, checkpoints it when due and reports the progress.
+func (q *Queue) update(j *job, change func(*job), delta ...string) {
+	q.mu.Lock()
+	status, chunks := j.Status, j.Chunks
+	change(j)
+	j.UpdatedAt = time.Now()
+	close(j.changed)
+	j.changed = make(chan struct{})
+	progress := Progress{ID: j.ID, Status: j.Status, Chunks: j.Chunks}
+	due := status != j.Status || (chunks != j.Chunks && j.Chunks%q.opts.CheckpointEvery == 0)
+	checkpoint := q.checkpoint(j)
+	q.mu.Unlock()
+
+	if due {
+		q.save(j, checkpoint)
+	}
+
+	if len(delta) > 0 {
+		progress.Delta = delta[0]
+	}
+
+	q.progress(progress)
+}
+
+// checkpoint returns the checkpoint of the job, the caller must hold the queue's mutex.
+func (q *Queue) checkpoint(j *job) Checkpoint {
+	checkpoint := Checkpoint{
+		ID:        j.ID,
+		Request:   j.request,
+		Status:    j.Status,
+		Output:    j.Output,
+		Chunks:    j.Chunks,
+		Context:   j.Context,
+		CreatedAt: j.CreatedAt,
+		UpdatedAt: j.UpdatedAt,
+	}
+
+	if j.Err != nil {
+		checkpoint.Error = j.Err.Error()
+	}
+
+	return checkpoint
+}
+
+// save persists the checkpoint of the job in the configured store, unless the job has been removed.
+// Failures are not fatal to the job.
+func (q *Queue) save(j *job, checkpoint Checkpoint) {
+	if q.opts.Checkpoints == nil {
+		return
+	}
+
+	j.saving.Lock()
+	defer j.saving.Unlock()
+
+	q.mu.Lock()
+	removed := j.removed
+	q.mu.Unlock()
+
+	if !removed {
+		q.opts.Checkpoints.Save(checkpoint)
+	}
+}
+
+// progress reports the progress to the configured handler.
+func (q *Queue) progress(p Progress) {
+	if q.opts.OnProgress != nil {
+		q.opts.OnProgress(p)
+	}
+}
@@ -0,0 +1,312 @@
+package jobs_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+	"github.com/rifaideen/talkative/jobs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestQueue tests submitting jobs, streaming their output and retrieving their results.
+func TestQueue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/api/generate") {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		encoder := json.NewEncoder(w)
+
+		for i, content := range []string{"Hello", ", ", "world"} {
+			encoder.Encode(talkative.ChatResponse{
+				Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: content},
+				Done:    i == 2,
+			})
+			w.(http.Flusher).Flush()
+			time.Sleep(10 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	var (
+		mu       sync.Mutex
+		statuses []jobs.Status
+	)
+
+	queue := jobs.New(client, jobs.Options{
+		Workers: 2,
+		OnProgress: func(p jobs.Progress) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if len(statuses) == 0 || statuses[len(statuses)-1] != p.Status {
+				statuses = append(statuses, p.Status)
+			}
+		},
+	})
+
+	_, err := queue.Submit(jobs.Request{})
+	assert.ErrorIs(t, err, jobs.ErrRequest)
+
+	_, err = queue.Get("missing")
+	assert.ErrorIs(t, err, jobs.ErrNotFound)
+
+	id, err := queue.Submit(jobs.Request{
+		Messages: []talkative.ChatMessage{{Role: talkative.USER, Content: "Hi"}},
+	})
+	assert.NoError(t, err)
+
+	stream, err := queue.Stream(context.Background(), id)
+	assert.NoError(t, err)
+
+	streamed := ""
+
+	for chunk := range stream {
+		streamed += chunk
+	}
+
+	job, err := queue.Result(context.Background(), id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, jobs.COMPLETED, job.Status)
+	assert.Equal(t, "Hello, world", job.Output)
+	assert.Equal(t, "Hello, world", streamed)
+	assert.Equal(t, 3, job.Chunks)
+
+	mu.Lock()
+	assert.Equal(t, []jobs.Status{jobs.PENDING, jobs.RUNNING, jobs.COMPLETED}, statuses)
+	mu.Unlock()
+
+	// completion jobs fail as the mock server rejects them
+	id, _ = queue.Submit(jobs.Request{Completion: &talkative.CompletionMessage{Prompt: "Hi"}})
+	job, _ = queue.Result(context.Background(), id)
+
+	assert.Equal(t, jobs.FAILED, job.Status)
+	assert.ErrorIs(t, job.Err, talkative.ErrInvoke)
+}
+
+// TestResume tests that an interrupted chat job is resumed from its checkpoint by another queue.
+func TestResume(t *testing.T) {
+	var received []talkative.ChatMessage
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		received = request.Messages
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{
+			Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: " world"},
+			Done:    true,
+		})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	store, err := jobs.NewFileCheckpointStore(t.TempDir())
+	{
+		assert.NoError(t, err)
+	}
+
+	// simulate a worker which crashed in the middle of a generation
+	store.Save(jobs.Checkpoint{
+		ID: "interrupted",
+		Request: jobs.Request{
+			Messages: []talkative.ChatMessage{{Role: talkative.USER, Content: "Say hello world"}},
+		},
+		Status: jobs.RUNNING,
+		Output: "Hello",
+		Chunks: 1,
+	})
+	store.Save(jobs.Checkpoint{ID: "finished", Status: jobs.COMPLETED, Output: "Done"})
+
+	queue := jobs.New(client, jobs.Options{Checkpoints: store, CheckpointEvery: 1})
+	resumed, err := queue.Resume()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"interrupted"}, resumed)
+
+	job, _ := queue.Result(context.Background(), "interrupted")
+
+	assert.Equal(t, jobs.COMPLETED, job.Status)
+	assert.Equal(t, "Hello world", job.Output)
+	assert.Len(t, received, 2)
+	assert.Equal(t, talkative.ChatMessage{Role: talkative.ASSISTANT, Content: "Hello"}, received[1])
+
+	job, _ = queue.Get("finished")
+	assert.Equal(t, "Done", job.Output)
+
+	checkpoints, _ := store.List()
+	assert.Len(t, checkpoints, 2)
+
+	assert.NoError(t, queue.Remove("interrupted"))
+
+	checkpoints, _ = store.List()
+	assert.Len(t, checkpoints, 1)
+}
+
+// TestStreamRemove tests that removing a job while its output is streamed closes the stream.
+func TestStreamRemove(t *testing.T) {
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+
+		encoder.Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: "Hello"}})
+		w.(http.Flusher).Flush()
+
+		<-release
+
+		encoder.Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: " world"}, Done: true})
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, _ := talkative.New(server.URL)
+	queue := jobs.New(client, jobs.Options{})
+
+	id, _ := queue.Submit(jobs.Request{
+		Messages: []talkative.ChatMessage{{Role: talkative.USER, Content: "Hi"}},
+	})
+
+	stream, err := queue.Stream(context.Background(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", <-stream)
+
+	go queue.Remove(id)
+
+	select {
+	case _, ok := <-stream:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("stream not closed after removing the job")
+	}
+
+	_, err = queue.Get(id)
+	assert.ErrorIs(t, err, jobs.ErrNotFound)
+}
+
+// TestRemoveRunning tests that a job removed while running is cancelled and not checkpointed again once
+// finished, so it is not resumed afterwards.
+func TestRemoveRunning(t *testing.T) {
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+
+		encoder.Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: "Hello"}})
+		w.(http.Flusher).Flush()
+
+		<-release
+
+		encoder.Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: " world"}, Done: true})
+	}))
+	defer server.Close()
+
+	var finished sync.WaitGroup
+
+	finished.Add(1)
+
+	client, _ := talkative.New(server.URL)
+	store := jobs.NewMemoryCheckpointStore()
+	queue := jobs.New(client, jobs.Options{
+		Checkpoints:     store,
+		CheckpointEvery: 1,
+		OnProgress: func(p jobs.Progress) {
+			if p.Status == jobs.FAILED {
+				finished.Done()
+			}
+		},
+	})
+
+	id, _ := queue.Submit(jobs.Request{
+		Messages: []talkative.ChatMessage{{Role: talkative.USER, Content: "Hi"}},
+	})
+
+	stream, _ := queue.Stream(context.Background(), id)
+	assert.Equal(t, "Hello", <-stream)
+	assert.NoError(t, queue.Remove(id))
+
+	close(release)
+	finished.Wait()
+
+	checkpoints, _ := store.List()
+	assert.Empty(t, checkpoints)
+
+	resumed, err := jobs.New(client, jobs.Options{Checkpoints: store}).Resume()
+	assert.NoError(t, err)
+	assert.Empty(t, resumed)
+}
+
+// TestCancel tests cancelling running and pending jobs.
+func TestCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: "Hello"}})
+		w.(http.Flusher).Flush()
+
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	queue := jobs.New(client, jobs.Options{Workers: 1})
+	request := jobs.Request{
+		Messages: []talkative.ChatMessage{{Role: talkative.USER, Content: "Hi"}},
+	}
+
+	running, _ := queue.Submit(request)
+	stream, _ := queue.Stream(context.Background(), running)
+	assert.Equal(t, "Hello", <-stream)
+
+	// the only worker is busy with the running job
+	pending, _ := queue.Submit(request)
+
+	assert.NoError(t, queue.Cancel(pending))
+	assert.NoError(t, queue.Cancel(running))
+	assert.ErrorIs(t, queue.Cancel("unknown"), jobs.ErrNotFound)
+
+	for _, id := range []string{running, pending} {
+		job, err := queue.Result(context.Background(), id)
+
+		assert.NoError(t, err)
+		assert.Equal(t, jobs.FAILED, job.Status)
+		assert.ErrorIs(t, job.Err, context.Canceled)
+	}
+
+	job, _ := queue.Get(pending)
+	assert.Empty(t, job.Output)
+	assert.NoError(t, queue.Cancel(running))
+}
+
+// TestFileCheckpointStoreCollision tests that IDs differing only by path separators are stored separately.
+func TestFileCheckpointStoreCollision(t *testing.T) {
+	store, err := jobs.NewFileCheckpointStore(t.TempDir())
+	{
+		assert.NoError(t, err)
+	}
+
+	ids := []string{"a/b", "a\\b", "a_b"}
+
+	for _, id := range ids {
+		assert.NoError(t, store.Save(jobs.Checkpoint{ID: id, Output: id}))
+	}
+
+	checkpoints, _ := store.List()
+	assert.Len(t, checkpoints, 3)
+
+	for _, checkpoint := range checkpoints {
+		assert.Equal(t, checkpoint.ID, checkpoint.Output)
+	}
+}
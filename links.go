@@ -0,0 +1,264 @@
+package talkative
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_LINK_TIMEOUT     = 5 * time.Second // Default timeout of a single link validation.
+	DEFAULT_LINK_CONCURRENCY = 4               // Default number of links validated concurrently.
+	DEFAULT_MAX_LINKS        = 20              // Default maximum number of distinct links validated per response.
+	DEFAULT_LINK_ANNOTATION  = " [dead link]"  // Default annotation appended to dead links.
+)
+
+// LinkAction represents what is done with the dead links of a response.
+type LinkAction int
+
+const (
+	// Dead links are kept and followed by the annotation of the validator.
+	LINK_ANNOTATE LinkAction = iota
+
+	// Dead links are removed, markdown links are replaced by their text.
+	LINK_STRIP
+)
+
+// Link is a URL found in a response.
+type Link struct {
+	URL   string // The URL of the link.
+	Text  string // The text of the markdown link, empty for bare URLs.
+	Start int    // The offset of the link in the response, including its markdown syntax.
+	End   int    // The offset right after the link in the response.
+
+	Checked bool  // Whether the link was validated.
+	Status  int   // The status code the link responded with, 0 when it couldn't be reached.
+	Err     error // The error which occurred while validating the link, if any.
+}
+
+// Dead reports whether the link was validated and found unreachable or responding with an error status.
+func (l *Link) Dead() bool {
+	return l.Checked && (l.Err != nil || l.Status >= 400)
+}
+
+var (
+	markdownURL = regexp.MustCompile(`\[([^\]]*)\]\((https?://[^\s()]+(?:\([^\s()]*\)[^\s()]*)*)\)`)
+	bareURL     = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+)
+
+// ExtractLinks returns the http and https links of the text in order, both markdown links and bare URLs.
+//
+// Trailing punctuation and unbalanced closing parentheses are not considered part of bare URLs.
+func ExtractLinks(text string) []Link {
+	var links []Link
+
+	covered := func(start int) bool {
+		for _, link := range links {
+			if start >= link.Start && start < link.End {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, match := range markdownURL.FindAllStringSubmatchIndex(text, -1) {
+		links = append(links, Link{
+			URL:   text[match[4]:match[5]],
+			Text:  text[match[2]:match[3]],
+			Start: match[0],
+			End:   match[1],
+		})
+	}
+
+	for _, match := range bareURL.FindAllStringIndex(text, -1) {
+		if covered(match[0]) {
+			continue
+		}
+
+		url := trimURL(text[match[0]:match[1]])
+
+		links = append(links, Link{URL: url, Start: match[0], End: match[0] + len(url)})
+	}
+
+	sort.Slice(links, func(i, j int) bool { return links[i].Start < links[j].Start })
+
+	return links
+}
+
+// trimURL removes the trailing punctuation and unbalanced closing parentheses of a bare URL.
+func trimURL(url string) string {
+	for len(url) > 0 {
+		last := url[len(url)-1]
+
+		switch {
+		case strings.IndexByte(".,;:!?*_", last) >= 0:
+			url = url[:len(url)-1]
+		case last == ')' && strings.Count(url, "(") < strings.Count(url, ")"):
+			url = url[:len(url)-1]
+		default:
+			return url
+		}
+	}
+
+	return url
+}
+
+// LinkValidator validates the links of responses with HEAD requests, to catch dead or hallucinated links
+// before they are displayed.
+type LinkValidator struct {
+	Client      *http.Client  // The HTTP client used to validate the links, defaults to http.DefaultClient.
+	Timeout     time.Duration // Timeout of a single validation, defaults to DEFAULT_LINK_TIMEOUT.
+	Concurrency int           // Number of links validated concurrently, defaults to DEFAULT_LINK_CONCURRENCY.
+	MaxLinks    int           // Maximum number of distinct links validated, the others are left unchecked. Defaults to DEFAULT_MAX_LINKS.
+	Annotation  string        // Appended to dead links with LINK_ANNOTATE, defaults to DEFAULT_LINK_ANNOTATION.
+}
+
+// Validate validates the links, returning them with their result. Links sharing a URL are validated once.
+//
+// A link is validated with a HEAD request, followed by a GET request when the server doesn't allow HEAD.
+func (v *LinkValidator) Validate(ctx context.Context, links []Link) []Link {
+	client := v.Client
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	timeout := v.Timeout
+
+	if timeout <= 0 {
+		timeout = DEFAULT_LINK_TIMEOUT
+	}
+
+	concurrency := v.Concurrency
+
+	if concurrency <= 0 {
+		concurrency = DEFAULT_LINK_CONCURRENCY
+	}
+
+	limit := v.MaxLinks
+
+	if limit <= 0 {
+		limit = DEFAULT_MAX_LINKS
+	}
+
+	type result struct {
+		status int
+		err    error
+	}
+
+	var urls []string
+
+	results := map[string]*result{}
+
+	for _, link := range links {
+		if _, ok := results[link.URL]; !ok && len(urls) < limit {
+			results[link.URL] = &result{}
+			urls = append(urls, link.URL)
+		}
+	}
+
+	var wg sync.WaitGroup
+
+	semaphore := make(chan struct{}, concurrency)
+
+	for _, url := range urls {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(url string, r *result) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			r.status, r.err = checkLink(ctx, client, timeout, url)
+		}(url, results[url])
+	}
+
+	wg.Wait()
+
+	validated := make([]Link, len(links))
+
+	for i, link := range links {
+		if r, ok := results[link.URL]; ok {
+			link.Checked, link.Status, link.Err = true, r.status, r.err
+		}
+
+		validated[i] = link
+	}
+
+	return validated
+}
+
+// Process extracts and validates the links of the text, then annotates or strips the dead ones.
+//
+// It returns the processed text along with the validated links.
+func (v *LinkValidator) Process(ctx context.Context, text string, action LinkAction) (string, []Link) {
+	links := v.Validate(ctx, ExtractLinks(text))
+
+	annotation := v.Annotation
+
+	if annotation == "" {
+		annotation = DEFAULT_LINK_ANNOTATION
+	}
+
+	sb := strings.Builder{}
+	offset := 0
+
+	for _, link := range links {
+		if !link.Dead() {
+			continue
+		}
+
+		sb.WriteString(text[offset:link.Start])
+
+		switch action {
+		case LINK_STRIP:
+			sb.WriteString(link.Text)
+		default:
+			sb.WriteString(text[link.Start:link.End] + annotation)
+		}
+
+		offset = link.End
+	}
+
+	sb.WriteString(text[offset:])
+
+	return sb.String(), links
+}
+
+// checkLink requests the URL, returning the status code it responded with.
+func checkLink(ctx context.Context, client *http.Client, timeout time.Duration, url string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	status := 0
+
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+
+		if err != nil {
+			return 0, err
+		}
+
+		res, err := client.Do(req)
+
+		if err != nil {
+			return 0, err
+		}
+
+		res.Body.Close()
+		status = res.StatusCode
+
+		if status != http.StatusMethodNotAllowed && status != http.StatusNotImplemented {
+			break
+		}
+	}
+
+	return status, nil
+}
@@ -0,0 +1,75 @@
+package talkative_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExtractLinks tests extracting markdown links and bare URLs from a response.
+func TestExtractLinks(t *testing.T) {
+	text := "See https://go.dev/doc. Also [the spec](https://go.dev/ref/spec) (or https://en.wikipedia.org/wiki/Go_(programming_language))."
+	links := talkative.ExtractLinks(text)
+
+	assert.Len(t, links, 3)
+	assert.Equal(t, "https://go.dev/doc", links[0].URL)
+	assert.Equal(t, "https://go.dev/ref/spec", links[1].URL)
+	assert.Equal(t, "the spec", links[1].Text)
+	assert.Equal(t, "[the spec](https://go.dev/ref/spec)", text[links[1].Start:links[1].End])
+	assert.Equal(t, "https://en.wikipedia.org/wiki/Go_(programming_language)", links[2].URL)
+
+	assert.Empty(t, talkative.ExtractLinks("no links here, only ftp://example.com"))
+}
+
+// TestLinkValidator tests annotating and stripping dead links.
+func TestLinkValidator(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/alive":
+			w.WriteHeader(http.StatusOK)
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	text := "Read [the docs](" + server.URL + "/alive), " + server.URL + "/get-only and [the paper](" + server.URL + "/made-up)."
+	validator := &talkative.LinkValidator{}
+
+	annotated, links := validator.Process(context.Background(), text, talkative.LINK_ANNOTATE)
+
+	assert.Len(t, links, 3)
+	assert.False(t, links[0].Dead())
+	assert.False(t, links[1].Dead())
+	assert.True(t, links[2].Dead())
+	assert.Equal(t, http.StatusNotFound, links[2].Status)
+	assert.Equal(t, "Read [the docs]("+server.URL+"/alive), "+server.URL+"/get-only and [the paper]("+server.URL+"/made-up) [dead link].", annotated)
+
+	stripped, _ := validator.Process(context.Background(), text, talkative.LINK_STRIP)
+	assert.Equal(t, "Read [the docs]("+server.URL+"/alive), "+server.URL+"/get-only and the paper.", stripped)
+}
+
+// TestLinkValidatorLimits tests links beyond the limit are left unchecked and unreachable links are dead.
+func TestLinkValidatorLimits(t *testing.T) {
+	validator := &talkative.LinkValidator{MaxLinks: 1}
+
+	links := validator.Validate(context.Background(), talkative.ExtractLinks("http://127.0.0.1:1/a http://127.0.0.1:1/b http://127.0.0.1:1/a"))
+
+	assert.True(t, links[0].Dead())
+	assert.Error(t, links[0].Err)
+	assert.False(t, links[1].Checked)
+	assert.False(t, links[1].Dead())
+	assert.True(t, links[2].Dead())
+}
@@ -0,0 +1,93 @@
+package talkative
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	// DEFAULT_LOADING_RETRIES is the default number of retries of requests hitting a loading model.
+	DEFAULT_LOADING_RETRIES = 10
+
+	// DEFAULT_LOADING_BACKOFF is the default delay before the first retry of a request hitting a loading model.
+	DEFAULT_LOADING_BACKOFF = 500 * time.Millisecond
+
+	// DEFAULT_LOADING_MAX_BACKOFF is the default maximum delay between two retries of a request hitting a loading model.
+	DEFAULT_LOADING_MAX_BACKOFF = 5 * time.Second
+)
+
+// ErrModelLoading is returned when the server answers with 503 Service Unavailable, which Ollama and the
+// proxies in front of it do while a model is loading or the server is busy. It also matches ErrInvoke.
+var ErrModelLoading = newError("model is loading or the server is busy")
+
+// LoadingRetry configures WithLoadingRetry.
+type LoadingRetry struct {
+	Retries    int           // The number of retries, defaults to DEFAULT_LOADING_RETRIES.
+	Backoff    time.Duration // The delay before the first retry, doubled for every retry, defaults to DEFAULT_LOADING_BACKOFF.
+	MaxBackoff time.Duration // The maximum delay between two retries, defaults to DEFAULT_LOADING_MAX_BACKOFF.
+}
+
+// WithLoadingRetry retries chats, completions and embeddings failing with ErrModelLoading with an exponential
+// backoff, instead of surfacing the transient unavailability of the model as a failure to interactive users.
+//
+// EVENT_MODEL_LOADING is emitted before every retry, i.e: to display "model loading..." to the user.
+// Requests still unavailable after the last retry, or whose context is done, fail with the last error.
+func WithLoadingRetry(retry LoadingRetry) Option {
+	return func(c *Client) {
+		if retry.Retries <= 0 {
+			retry.Retries = DEFAULT_LOADING_RETRIES
+		}
+
+		if retry.Backoff <= 0 {
+			retry.Backoff = DEFAULT_LOADING_BACKOFF
+		}
+
+		if retry.MaxBackoff <= 0 {
+			retry.MaxBackoff = DEFAULT_LOADING_MAX_BACKOFF
+		}
+
+		c.loadingRetry = &retry
+	}
+}
+
+// generate posts the request of the tracked generation to the endpoint, pulling its missing model when
+// FEATURE_AUTO_PULL is enabled and retrying it while the model is loading when the client has a loading retry,
+// see WithLoadingRetry.
+func (c *Client) generate(ctx context.Context, l *lifecycle, endpoint string, request any) (*http.Response, error) {
+	res, err := c.post(ctx, endpoint, request)
+
+	if errors.Is(err, ErrNotFound) && c.Enabled(FEATURE_AUTO_PULL) && c.pull(ctx, l.model) == nil {
+		res, err = c.post(ctx, endpoint, request)
+	}
+
+	if c.loadingRetry == nil {
+		return res, err
+	}
+
+	delay := c.loadingRetry.Backoff
+
+	for attempt := 1; attempt <= c.loadingRetry.Retries && errors.Is(err, ErrModelLoading); attempt++ {
+		event := l.event(EVENT_MODEL_LOADING, err)
+		event.Attempt = attempt
+		event.Delay = delay
+
+		c.events.emit(event)
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, err
+		case <-timer.C:
+		}
+
+		delay = min(delay*2, c.loadingRetry.MaxBackoff)
+		res, err = c.post(ctx, endpoint, request)
+	}
+
+	return res, err
+}
@@ -0,0 +1,91 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithLoadingRetry tests retrying requests while the model is loading.
+func TestWithLoadingRetry(t *testing.T) {
+	var requests atomic.Int32
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":"model is loading"}`))
+
+			return
+		}
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}, Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithLoadingRetry(talkative.LoadingRetry{Backoff: 10 * time.Millisecond, MaxBackoff: 15 * time.Millisecond}))
+
+	var loading []talkative.Event
+
+	client.Subscribe(func(event talkative.Event) {
+		loading = append(loading, event)
+	}, talkative.EVENT_MODEL_LOADING)
+
+	content := ""
+
+	done, err := client.Chat("llama2", func(cr *talkative.ChatResponse, err error) {
+		assert.NoError(t, err)
+
+		content += cr.Message.Content
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+		<-done
+		assert.Equal(t, "Hello", content)
+		assert.Equal(t, int32(3), requests.Load())
+	}
+
+	if assert.Len(t, loading, 2) {
+		assert.Equal(t, 1, loading[0].Attempt)
+		assert.Equal(t, 10*time.Millisecond, loading[0].Delay)
+		assert.Equal(t, 2, loading[1].Attempt)
+		assert.Equal(t, 15*time.Millisecond, loading[1].Delay)
+		assert.Equal(t, "llama2", loading[1].Model)
+		assert.ErrorIs(t, loading[1].Err, talkative.ErrModelLoading)
+	}
+}
+
+// TestWithLoadingRetryExhausted tests failing requests whose model is still loading after the last retry.
+func TestWithLoadingRetryExhausted(t *testing.T) {
+	var requests atomic.Int32
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithLoadingRetry(talkative.LoadingRetry{Retries: 2, Backoff: time.Millisecond}))
+
+	_, err := client.Completion("llama2", func(cr *talkative.CompletionResponse, err error) {}, &talkative.CompletionMessage{Prompt: "Hi"})
+	{
+		assert.ErrorIs(t, err, talkative.ErrModelLoading)
+		assert.ErrorIs(t, err, talkative.ErrInvoke)
+		assert.Equal(t, int32(3), requests.Load())
+	}
+
+	// Without a loading retry, the request fails right away.
+	requests.Store(0)
+	client, _ = talkative.New(server.URL)
+
+	_, err = client.Completion("llama2", func(cr *talkative.CompletionResponse, err error) {}, &talkative.CompletionMessage{Prompt: "Hi"})
+	{
+		assert.ErrorIs(t, err, talkative.ErrModelLoading)
+		assert.Equal(t, int32(1), requests.Load())
+	}
+}
@@ -0,0 +1,322 @@
+// Package loadtest provides a conversation simulator for load testing Ollama compatible endpoints.
+//
+// It is built entirely on the public talkative client API, spinning up a configurable number of
+// simulated conversations and reporting latency percentiles and throughput once they complete.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rifaideen/talkative"
+)
+
+// Pre-defined errors used by the load test.
+var (
+	ErrConversations = errors.New("number of conversations must be greater than zero") // Error for invalid number of conversations.
+	ErrTurns         = errors.New("number of turns must be greater than zero")         // Error for invalid number of turns.
+	ErrMessages      = errors.New("messages cannot be empty")                          // Error for missing message distribution.
+	ErrWeights       = errors.New("weights must be non-negative and not all zero")     // Error for invalid message weights.
+)
+
+// Config describes the simulated workload.
+type Config struct {
+	URL           string                // The base URL of the Ollama API.
+	Options       []talkative.Option    // The options of the client, i.e: credentials or TLS configuration. (Optional)
+	Model         string                // The model to use, defaults to the default model of the client.
+	Params        *talkative.ChatParams // The additional parameters sent with every chat. (Optional)
+	Conversations int                   // Number of conversations to run in parallel.
+	Turns         int                   // Number of user turns per conversation.
+	Messages      []string              // The pool of user messages, picked uniformly unless Weights is set.
+	Weights       []float64             // Relative weights of Messages. (Optional)
+	MinThinkTime  time.Duration         // Minimum pause between turns of a single conversation.
+	MaxThinkTime  time.Duration         // Maximum pause between turns of a single conversation.
+	Seed          int64                 // Seed of the random generator, for reproducible runs.
+}
+
+// Report summarises the outcome of a load test run.
+type Report struct {
+	Requests        int           // Total number of chat requests issued.
+	Errors          int           // Number of requests which failed.
+	Duration        time.Duration // Wall clock duration of the whole run.
+	Throughput      float64       // Successful requests per second.
+	TokensPerSecond float64       // Generated tokens (eval_count) per second across all conversations.
+
+	FirstToken Latency // Latency until the first chunk was received.
+	Total      Latency // Latency until the response completed.
+}
+
+// Latency holds latency percentiles of a run.
+type Latency struct {
+	Min time.Duration
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// sample is the measurement of a single chat request.
+type sample struct {
+	firstToken time.Duration
+	total      time.Duration
+	tokens     int
+	err        error
+}
+
+// Run executes the load test described by cfg and returns its report.
+//
+// Every conversation keeps its own history, so later turns carry the assistant replies of the previous ones.
+// Cancelling ctx stops conversations from starting new turns, in-flight requests are allowed to finish.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := talkative.New(cfg.URL, cfg.Options...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return run(ctx, client, cfg), nil
+}
+
+// validate reports the first invalid setting of the configuration.
+func (cfg Config) validate() error {
+	if cfg.Conversations <= 0 {
+		return ErrConversations
+	}
+
+	if cfg.Turns <= 0 {
+		return ErrTurns
+	}
+
+	if len(cfg.Messages) == 0 {
+		return ErrMessages
+	}
+
+	if len(cfg.Weights) == 0 {
+		return nil
+	}
+
+	total := 0.0
+
+	for _, weight := range cfg.Weights {
+		if weight < 0 {
+			return ErrWeights
+		}
+
+		total += weight
+	}
+
+	if total == 0 {
+		return ErrWeights
+	}
+
+	return nil
+}
+
+// run executes the load test described by the validated cfg through the client.
+func run(ctx context.Context, client *talkative.Client, cfg Config) *Report {
+	var (
+		mu      sync.Mutex
+		samples []sample
+		wg      sync.WaitGroup
+	)
+
+	start := time.Now()
+
+	for i := 0; i < cfg.Conversations; i++ {
+		wg.Add(1)
+
+		go func(seed int64) {
+			defer wg.Done()
+
+			random := rand.New(rand.NewSource(seed))
+			history := []talkative.ChatMessage{}
+
+			for turn := 0; turn < cfg.Turns; turn++ {
+				if turn > 0 && !sleep(ctx, cfg.thinkTime(random)) {
+					return
+				}
+
+				if ctx.Err() != nil {
+					return
+				}
+
+				history = append(history, talkative.ChatMessage{
+					Role:    talkative.USER,
+					Content: cfg.message(random),
+				})
+
+				reply, s := converse(client, cfg, history)
+
+				mu.Lock()
+				samples = append(samples, s)
+				mu.Unlock()
+
+				if s.err != nil {
+					return
+				}
+
+				history = append(history, reply)
+			}
+		}(cfg.Seed + int64(i))
+	}
+
+	wg.Wait()
+
+	return report(samples, time.Since(start))
+}
+
+// converse sends the history as a single chat request and measures it.
+func converse(client *talkative.Client, cfg Config, history []talkative.ChatMessage) (talkative.ChatMessage, sample) {
+	var (
+		s     sample
+		reply = talkative.ChatMessage{Role: talkative.ASSISTANT}
+	)
+
+	start := time.Now()
+
+	done, err := client.Chat(cfg.Model, func(cr *talkative.ChatResponse, err error) {
+		if err != nil {
+			s.err = err
+
+			return
+		}
+
+		if s.firstToken == 0 {
+			s.firstToken = time.Since(start)
+		}
+
+		reply.Content += cr.Message.Content
+
+		if cr.Done {
+			s.tokens = cr.EvalCount
+		}
+	}, cfg.Params, history...)
+
+	if err != nil {
+		s.err = err
+
+		return reply, s
+	}
+
+	<-done
+
+	s.total = time.Since(start)
+
+	return reply, s
+}
+
+// thinkTime returns a random pause between MinThinkTime and MaxThinkTime.
+func (cfg Config) thinkTime(random *rand.Rand) time.Duration {
+	if cfg.MaxThinkTime <= cfg.MinThinkTime {
+		return cfg.MinThinkTime
+	}
+
+	return cfg.MinThinkTime + time.Duration(random.Int63n(int64(cfg.MaxThinkTime-cfg.MinThinkTime)))
+}
+
+// message picks a message according to the configured distribution.
+func (cfg Config) message(random *rand.Rand) string {
+	if len(cfg.Weights) != len(cfg.Messages) {
+		return cfg.Messages[random.Intn(len(cfg.Messages))]
+	}
+
+	total := 0.0
+
+	for _, weight := range cfg.Weights {
+		total += weight
+	}
+
+	pick := random.Float64() * total
+
+	for i, weight := range cfg.Weights {
+		if pick < weight {
+			return cfg.Messages[i]
+		}
+
+		pick -= weight
+	}
+
+	return cfg.Messages[len(cfg.Messages)-1]
+}
+
+// sleep pauses for d, returning false when ctx is cancelled in the meantime.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// report aggregates the samples of a run.
+func report(samples []sample, duration time.Duration) *Report {
+	r := &Report{
+		Requests: len(samples),
+		Duration: duration,
+	}
+
+	var (
+		firstTokens []time.Duration
+		totals      []time.Duration
+		tokens      int
+	)
+
+	for _, s := range samples {
+		if s.err != nil {
+			r.Errors++
+
+			continue
+		}
+
+		firstTokens = append(firstTokens, s.firstToken)
+		totals = append(totals, s.total)
+		tokens += s.tokens
+	}
+
+	if seconds := duration.Seconds(); seconds > 0 {
+		r.Throughput = float64(len(totals)) / seconds
+		r.TokensPerSecond = float64(tokens) / seconds
+	}
+
+	r.FirstToken = percentiles(firstTokens)
+	r.Total = percentiles(totals)
+
+	return r
+}
+
+// percentiles computes the latency percentiles of the given durations.
+func percentiles(durations []time.Duration) Latency {
+	if len(durations) == 0 {
+		return Latency{}
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	at := func(p float64) time.Duration {
+		return durations[int(p*float64(len(durations)-1))]
+	}
+
+	return Latency{
+		Min: durations[0],
+		P50: at(0.50),
+		P90: at(0.90),
+		P99: at(0.99),
+		Max: durations[len(durations)-1],
+	}
+}
@@ -0,0 +1,144 @@
+package loadtest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+	"github.com/rifaideen/talkative/loadtest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRun tests the load test against a mock server answering every chat with a single chunk.
+func TestRun(t *testing.T) {
+	var requests atomic.Int32
+
+	var authorized atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if r.Header.Get("Authorization") == "Bearer secret" {
+			authorized.Add(1)
+		}
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{
+			Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: "Paris"},
+			Done:    true,
+			ChatMetrics: talkative.ChatMetrics{
+				EvalCount: 1,
+			},
+		})
+	}))
+	defer server.Close()
+
+	t.Run("loadtest-validation-error", func(t *testing.T) {
+		_, err := loadtest.Run(context.Background(), loadtest.Config{URL: server.URL})
+
+		assert.ErrorIs(t, err, loadtest.ErrConversations)
+
+		_, err = loadtest.Run(context.Background(), loadtest.Config{URL: server.URL, Conversations: 1, Turns: 1})
+
+		assert.ErrorIs(t, err, loadtest.ErrMessages)
+
+		for _, weights := range [][]float64{{0, 0}, {3, -1}} {
+			_, err = loadtest.Run(context.Background(), loadtest.Config{
+				URL:           server.URL,
+				Conversations: 1,
+				Turns:         1,
+				Messages:      []string{"What is the capital of France?", "Hi"},
+				Weights:       weights,
+			})
+
+			assert.ErrorIs(t, err, loadtest.ErrWeights)
+		}
+	})
+
+	t.Run("loadtest-success", func(t *testing.T) {
+		report, err := loadtest.Run(context.Background(), loadtest.Config{
+			URL:           server.URL,
+			Conversations: 4,
+			Turns:         3,
+			Messages:      []string{"What is the capital of France?", "Hi"},
+			Weights:       []float64{3, 1},
+			Options:       []talkative.Option{talkative.WithBearerToken("secret")},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 12, report.Requests)
+		assert.Equal(t, 0, report.Errors)
+		assert.EqualValues(t, 12, requests.Load())
+		assert.EqualValues(t, 12, authorized.Load())
+		assert.Greater(t, report.Throughput, 0.0)
+		assert.LessOrEqual(t, report.Total.P50, report.Total.Max)
+	})
+}
+
+// TestSoak tests that a short soak test against a mock server does not report leaks.
+func TestSoak(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{
+			Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: "Paris"},
+			Done:    true,
+		})
+	}))
+	defer server.Close()
+
+	report, err := loadtest.Soak(context.Background(), loadtest.SoakConfig{
+		Config: loadtest.Config{
+			URL:           server.URL,
+			Conversations: 2,
+			Turns:         2,
+			Messages:      []string{"Hi"},
+			Options:       []talkative.Option{talkative.WithBearerToken("secret")},
+		},
+		Duration:           200 * time.Millisecond,
+		Settle:             100 * time.Millisecond,
+		MaxGoroutineGrowth: 10,
+		MaxFDGrowth:        10,
+	})
+
+	assert.NoError(t, err)
+	assert.Greater(t, report.Rounds, 0)
+	assert.Len(t, report.Samples, report.Rounds)
+	assert.Equal(t, 0, report.Errors)
+}
+
+// TestSoakDefaultLimits tests that a clean soak test passes with the default limits, pooled connections
+// are not reported as leaks.
+func TestSoakDefaultLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(talkative.ChatResponse{
+			Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: "Paris"},
+			Done:    true,
+		})
+	}))
+	defer server.Close()
+
+	report, err := loadtest.Soak(context.Background(), loadtest.SoakConfig{
+		Config: loadtest.Config{
+			URL:           server.URL,
+			Conversations: 4,
+			Turns:         2,
+			Messages:      []string{"Hi"},
+		},
+		Duration: 200 * time.Millisecond,
+		Settle:   100 * time.Millisecond,
+	})
+
+	assert.NoError(t, err)
+	assert.Greater(t, report.Rounds, 0)
+	assert.Equal(t, 0, report.Errors)
+}
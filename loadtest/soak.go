@@ -0,0 +1,145 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/rifaideen/talkative"
+)
+
+// ErrLeak is returned by Soak when goroutine or file descriptor counts grew beyond the allowed limits.
+var ErrLeak = errors.New("resource leak detected")
+
+// SoakConfig describes a long running soak test.
+//
+// The embedded Config describes a single round of conversations, rounds are repeated until Duration elapses.
+type SoakConfig struct {
+	Config
+
+	Duration           time.Duration // Total duration of the soak test.
+	Settle             time.Duration // Pause before taking the baseline and the final measurements, defaults to 1 second.
+	MaxGoroutineGrowth int           // Allowed growth of the goroutine count between baseline and final measurement.
+	MaxFDGrowth        int           // Allowed growth of the open file descriptor count between baseline and final measurement.
+}
+
+// SoakSample is a resource measurement taken after every round.
+type SoakSample struct {
+	At         time.Duration // Time elapsed since the start of the soak test.
+	Goroutines int           // Number of goroutines.
+	FDs        int           // Number of open file descriptors, -1 when unsupported on this platform.
+}
+
+// SoakReport summarises the outcome of a soak test.
+type SoakReport struct {
+	Rounds   int          // Number of completed rounds.
+	Requests int          // Total number of chat requests issued.
+	Errors   int          // Number of requests which failed.
+	Baseline SoakSample   // Measurement taken after the warm-up round, before the first round.
+	Final    SoakSample   // Measurement taken after the settle period.
+	Samples  []SoakSample // Measurements taken after every round.
+}
+
+// GoroutineGrowth returns the difference of goroutines between the final and the baseline measurement.
+func (r *SoakReport) GoroutineGrowth() int {
+	return r.Final.Goroutines - r.Baseline.Goroutines
+}
+
+// FDGrowth returns the difference of open file descriptors between the final and the baseline measurement.
+func (r *SoakReport) FDGrowth() int {
+	if r.Final.FDs < 0 || r.Baseline.FDs < 0 {
+		return 0
+	}
+
+	return r.Final.FDs - r.Baseline.FDs
+}
+
+// Soak repeatedly runs the configured conversations for the configured duration while watching
+// goroutine and file descriptor counts of the process.
+//
+// Every round shares a single client, created with its own HTTP client before the options of the configuration are
+// applied, so they can configure or replace it. A warm-up round, which is not part of the report, runs before the
+// baseline is measured, and idle connections are closed before every measurement, so pooled connections
+// are not mistaken for leaks.
+//
+// The report is always returned, the error wraps ErrLeak when the growth of either count
+// exceeds the configured limits, which usually indicates response bodies or streaming goroutines
+// that are never released.
+func Soak(ctx context.Context, cfg SoakConfig) (*SoakReport, error) {
+	if cfg.Settle <= 0 {
+		cfg.Settle = time.Second
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+	opts := append([]talkative.Option{talkative.WithHTTPClient(httpClient)}, cfg.Options...)
+	client, err := talkative.New(cfg.URL, opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// measure the baseline once the round has been warmed up and the connections have settled
+	settle := func(start time.Time) SoakSample {
+		client.CloseIdleConnections()
+		sleep(ctx, cfg.Settle)
+		runtime.GC()
+
+		return measure(time.Since(start))
+	}
+
+	run(ctx, client, cfg.Config)
+
+	start := time.Now()
+	report := &SoakReport{
+		Baseline: settle(start),
+	}
+
+	for time.Since(start) < cfg.Duration && ctx.Err() == nil {
+		round := run(ctx, client, cfg.Config)
+
+		report.Rounds++
+		report.Requests += round.Requests
+		report.Errors += round.Errors
+		report.Samples = append(report.Samples, measure(time.Since(start)))
+	}
+
+	report.Final = settle(start)
+
+	if growth := report.GoroutineGrowth(); growth > cfg.MaxGoroutineGrowth {
+		return report, fmt.Errorf("%w: goroutines grew by %d", ErrLeak, growth)
+	}
+
+	if growth := report.FDGrowth(); growth > cfg.MaxFDGrowth {
+		return report, fmt.Errorf("%w: file descriptors grew by %d", ErrLeak, growth)
+	}
+
+	return report, nil
+}
+
+// measure takes a resource measurement.
+func measure(at time.Duration) SoakSample {
+	return SoakSample{
+		At:         at,
+		Goroutines: runtime.NumGoroutine(),
+		FDs:        openFDs(),
+	}
+}
+
+// openFDs returns the number of open file descriptors of the process, or -1 when it cannot be determined.
+func openFDs() int {
+	entries, err := os.ReadDir("/proc/self/fd")
+
+	if err != nil {
+		return -1
+	}
+
+	return len(entries)
+}
@@ -0,0 +1,167 @@
+package talkative
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DEFAULT_MAP_CONCURRENCY is the number of chunks mapped concurrently when none is configured.
+const DEFAULT_MAP_CONCURRENCY = 4
+
+// ErrMapReduce is returned when a chunk cannot be mapped or the results cannot be reduced.
+var ErrMapReduce = newError("map-reduce failed")
+
+// MapReduceOptions configures MapReduce.
+type MapReduceOptions struct {
+	Model  string      // The model used for both phases, defaults to the default model of the client.
+	Params *ChatParams // The chat parameters used for both phases. (Optional)
+
+	// MapPrompt is a text/template rendered for every chunk with MapData, i.e: "Summarize: {{.Chunk}}".
+	MapPrompt string
+
+	// ReducePrompt is a text/template rendered once with ReduceData, i.e: "Combine these summaries: {{.Joined}}".
+	// When empty, the mapped results are joined with blank lines without asking the model.
+	ReducePrompt string
+
+	Concurrency int // Maximum number of chunks mapped concurrently, defaults to DEFAULT_MAP_CONCURRENCY.
+	Retries     int // Number of additional attempts made for a failing chunk or reduction.
+
+	// OnProgress is called after every chunk is mapped, successfully or not. (Optional)
+	OnProgress func(progress MapProgress)
+}
+
+// MapData is the data the map prompt is rendered with.
+type MapData struct {
+	Chunk string // The content of the chunk.
+	Index int    // The index of the chunk, starting from 0.
+	Total int    // The total number of chunks.
+}
+
+// ReduceData is the data the reduce prompt is rendered with.
+type ReduceData struct {
+	Results []string // The mapped results in the order of the chunks.
+	Joined  string   // The mapped results joined with blank lines.
+}
+
+// MapProgress reports the progress of the map phase.
+type MapProgress struct {
+	Index     int   // The index of the chunk which was just mapped.
+	Completed int   // The number of chunks mapped so far.
+	Total     int   // The total number of chunks.
+	Err       error // The error of the chunk, if it failed after all retries.
+}
+
+// MapReduceResult is the result of MapReduce.
+type MapReduceResult struct {
+	Output string   // The reduced output.
+	Mapped []string // The mapped results in the order of the chunks.
+}
+
+// MapReduce maps a prompt over the chunks concurrently and reduces the results with a combining prompt.
+//
+// The mapped results are always ordered like the chunks, regardless of the order they complete in.
+// Failing requests are retried up to opts.Retries times, the first chunk failing after all retries
+// fails the whole operation once the other chunks are done.
+func (c *Client) MapReduce(chunks []string, opts MapReduceOptions) (*MapReduceResult, error) {
+	if _, err := DefaultTemplates.Compile(opts.MapPrompt); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMapReduce, err)
+	}
+
+	if _, err := DefaultTemplates.Compile(opts.ReducePrompt); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMapReduce, err)
+	}
+
+	concurrency := opts.Concurrency
+
+	if concurrency <= 0 {
+		concurrency = DEFAULT_MAP_CONCURRENCY
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		completed int
+	)
+
+	mapped := make([]string, len(chunks))
+	errs := make([]error, len(chunks))
+	slots := make(chan struct{}, concurrency)
+
+	for i, chunk := range chunks {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func(i int, chunk string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			mapped[i], errs[i] = c.mapReduceStep(opts.MapPrompt, MapData{Chunk: chunk, Index: i, Total: len(chunks)}, opts)
+
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("%w: chunk %d: %w", ErrMapReduce, i, errs[i])
+			}
+
+			if opts.OnProgress != nil {
+				mu.Lock()
+				completed++
+				opts.OnProgress(MapProgress{Index: i, Completed: completed, Total: len(chunks), Err: errs[i]})
+				mu.Unlock()
+			}
+		}(i, chunk)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return &MapReduceResult{Mapped: mapped}, err
+		}
+	}
+
+	result := &MapReduceResult{Mapped: mapped}
+	data := ReduceData{Results: mapped, Joined: strings.Join(mapped, "\n\n")}
+
+	if opts.ReducePrompt == "" {
+		result.Output = data.Joined
+
+		return result, nil
+	}
+
+	output, err := c.mapReduceStep(opts.ReducePrompt, data, opts)
+
+	if err != nil {
+		return result, fmt.Errorf("%w: reduce: %w", ErrMapReduce, err)
+	}
+
+	result.Output = output
+
+	return result, nil
+}
+
+// mapReduceStep renders the template with the data and sends it, retrying failed requests.
+func (c *Client) mapReduceStep(source string, data any, opts MapReduceOptions) (string, error) {
+	prompt, err := DefaultTemplates.Render(source, data)
+
+	if err != nil {
+		return "", err
+	}
+
+	model := opts.Model
+
+	if model == "" {
+		model = c.DefaultModel()
+	}
+
+	var response string
+
+	for attempt := 0; attempt <= opts.Retries; attempt++ {
+		response, err = c.chatText(model, opts.Params, ChatMessage{Role: USER, Content: prompt})
+
+		if err == nil {
+			return response, nil
+		}
+	}
+
+	return "", err
+}
@@ -0,0 +1,68 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMapReduce tests mapping chunks concurrently, retrying failures and reducing in order.
+func TestMapReduce(t *testing.T) {
+	var failures atomic.Int32
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		prompt := request.Messages[0].Content
+
+		if prompt == "sum c" && failures.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: strings.ToUpper(prompt)}, Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	progress := []talkative.MapProgress{}
+
+	result, err := client.MapReduce([]string{"a", "b", "c"}, talkative.MapReduceOptions{
+		MapPrompt:    "sum {{.Chunk}}",
+		ReducePrompt: "combine {{len .Results}}: {{.Joined}}",
+		Concurrency:  2,
+		Retries:      1,
+		OnProgress:   func(p talkative.MapProgress) { progress = append(progress, p) },
+	})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"SUM A", "SUM B", "SUM C"}, result.Mapped)
+		assert.Equal(t, "COMBINE 3: SUM A\n\nSUM B\n\nSUM C", result.Output)
+		assert.Len(t, progress, 3)
+		assert.Equal(t, 3, progress[2].Completed)
+	}
+}
+
+// TestMapReduceFailure tests the error returned when a chunk keeps failing.
+func TestMapReduceFailure(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	_, err := client.MapReduce([]string{"a"}, talkative.MapReduceOptions{MapPrompt: "{{.Chunk}}"})
+	{
+		assert.ErrorIs(t, err, talkative.ErrMapReduce)
+		assert.ErrorIs(t, err, talkative.ErrInvoke)
+	}
+}
@@ -0,0 +1,61 @@
+package talkative
+
+import (
+	"maps"
+	"sync/atomic"
+)
+
+// Messages is a catalog of the human-readable texts of the errors of the package, keyed by sentinel error,
+// i.e: to localize the errors shown to end users:
+//
+//	talkative.SetMessages(talkative.Messages{
+//		talkative.ErrInvoke:       "impossible d'appeler l'api ollama",
+//		talkative.HintCheckServer: "vérifiez que le serveur ollama est démarré et que l'url est correcte",
+//	})
+type Messages map[error]string
+
+// HintCheckServer is the hint appended to ErrInvoke when the server answers with an unexpected status.
+// It is matched by errors.Is and its text can be overridden like the text of the errors.
+var HintCheckServer = newError("please make sure ollama server is running and url is correct")
+
+// messages holds the catalog set by SetMessages.
+var messages atomic.Pointer[Messages]
+
+// SetMessages overrides the text of the errors of the catalog, errors missing from the catalog keep their
+// default text. A nil catalog restores the default texts.
+//
+// The catalog only changes the text of the errors, errors.Is keeps matching the sentinel errors. Errors
+// wrapping a sentinel keep the text it had when they were returned, so the catalog is meant to be set once
+// at startup.
+func SetMessages(catalog Messages) {
+	if catalog == nil {
+		messages.Store(nil)
+
+		return
+	}
+
+	clone := maps.Clone(catalog)
+
+	messages.Store(&clone)
+}
+
+// sentinel is an error whose text can be overridden with SetMessages.
+type sentinel struct {
+	text string
+}
+
+// newError returns a sentinel error with the default text.
+func newError(text string) error {
+	return &sentinel{text: text}
+}
+
+// Error returns the text of the error from the catalog, or its default text.
+func (s *sentinel) Error() string {
+	if catalog := messages.Load(); catalog != nil {
+		if text, ok := (*catalog)[s]; ok {
+			return text
+		}
+	}
+
+	return s.text
+}
@@ -0,0 +1,65 @@
+package talkative_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetMessages tests overriding the text of the errors.
+func TestSetMessages(t *testing.T) {
+	defer talkative.SetMessages(nil)
+
+	err := fmt.Errorf("%w: %w", talkative.ErrInvoke, talkative.HintCheckServer)
+	{
+		assert.EqualError(t, err, "unable to invoke ollama api: please make sure ollama server is running and url is correct")
+	}
+
+	catalog := talkative.Messages{
+		talkative.ErrInvoke:       "impossible d'appeler l'api ollama",
+		talkative.HintCheckServer: "vérifiez que le serveur ollama est démarré",
+	}
+
+	talkative.SetMessages(catalog)
+
+	// The catalog is copied, later changes are ignored.
+	catalog[talkative.ErrModel] = "modèle manquant"
+
+	err = fmt.Errorf("%w: %w", talkative.ErrInvoke, talkative.HintCheckServer)
+	{
+		assert.EqualError(t, err, "impossible d'appeler l'api ollama: vérifiez que le serveur ollama est démarré")
+		assert.True(t, errors.Is(err, talkative.ErrInvoke))
+		assert.EqualError(t, talkative.ErrModel, "model cannot be empty")
+	}
+
+	talkative.SetMessages(nil)
+	{
+		assert.EqualError(t, talkative.ErrInvoke, "unable to invoke ollama api")
+	}
+}
+
+// TestSetMessagesRequest tests the overridden text reaches the errors of requests.
+func TestSetMessagesRequest(t *testing.T) {
+	defer talkative.SetMessages(nil)
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	talkative.SetMessages(talkative.Messages{talkative.HintCheckServer: "le serveur ne répond pas"})
+
+	_, err := client.ListModels()
+	{
+		assert.ErrorIs(t, err, talkative.ErrInvoke)
+		assert.ErrorIs(t, err, talkative.HintCheckServer)
+		assert.ErrorContains(t, err, "unable to invoke ollama api: status 500: le serveur ne répond pas")
+	}
+}
@@ -0,0 +1,295 @@
+package talkative
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrPushJob is returned by MetricsPusher.Push when the job is empty, which the Pushgateway rejects.
+var ErrPushJob = newError("pushgateway job cannot be empty")
+
+// Metrics aggregates client metrics from the lifecycle events of requests.
+//
+// Attach it to a client with Observe. Metrics are rendered in the Prometheus text exposition format,
+// either pulled through its http.Handler implementation or pushed with a MetricsPusher.
+type Metrics struct {
+	mu         sync.Mutex
+	series     map[[2]string]*series
+	collectors []io.WriterTo
+}
+
+// series holds the aggregated metrics of an operation and model.
+type series struct {
+	started    int
+	completed  int
+	failed     int
+	firstToken time.Duration
+	firstCount int
+	duration   time.Duration
+	skewed     int
+	prompt     int
+	eval       int
+}
+
+// NewMetrics creates a new, empty Metrics aggregator.
+func NewMetrics() *Metrics {
+	return &Metrics{series: map[[2]string]*series{}}
+}
+
+// Observe subscribes the aggregator to the lifecycle events of the client.
+//
+// The returned function stops observing the client.
+func (m *Metrics) Observe(client *Client) (unsubscribe func()) {
+	return client.Subscribe(m.record)
+}
+
+// Register adds a collector whose output is appended to the metrics, i.e: an Analyzer.
+//
+// Collectors must write their metrics in the Prometheus text exposition format.
+func (m *Metrics) Register(collector io.WriterTo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.collectors = append(m.collectors, collector)
+}
+
+// record aggregates a single event.
+func (m *Metrics) record(event Event) {
+	switch event.Type {
+	case EVENT_REQUEST_STARTED, EVENT_FIRST_TOKEN, EVENT_COMPLETED, EVENT_FAILED, EVENT_CLOCK_SKEW:
+	default:
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := [2]string{event.Op, event.Model}
+	s, ok := m.series[key]
+
+	if !ok {
+		s = &series{}
+		m.series[key] = s
+	}
+
+	switch event.Type {
+	case EVENT_REQUEST_STARTED:
+		s.started++
+	case EVENT_FIRST_TOKEN:
+		s.firstToken += event.Elapsed
+		s.firstCount++
+	case EVENT_COMPLETED:
+		s.completed++
+		s.duration += event.Elapsed
+		s.prompt += event.PromptTokens
+		s.eval += event.EvalTokens
+	case EVENT_FAILED:
+		s.failed++
+	case EVENT_CLOCK_SKEW:
+		s.skewed++
+	}
+}
+
+// WriteTo writes the aggregated metrics in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+
+	keys := make([][2]string, 0, len(m.series))
+
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i][0] < keys[j][0] || (keys[i][0] == keys[j][0] && keys[i][1] < keys[j][1])
+	})
+
+	type sample struct {
+		suffix string
+		value  func(*series) float64
+	}
+
+	type metric struct {
+		name, help, kind string
+		samples          []sample
+	}
+
+	metrics := []metric{
+		{"talkative_requests_total", "Total number of requests started.", "counter", []sample{{"", func(s *series) float64 { return float64(s.started) }}}},
+		{"talkative_requests_completed_total", "Total number of requests completed successfully.", "counter", []sample{{"", func(s *series) float64 { return float64(s.completed) }}}},
+		{"talkative_requests_failed_total", "Total number of requests failed.", "counter", []sample{{"", func(s *series) float64 { return float64(s.failed) }}}},
+		{"talkative_first_token_seconds", "Latencies until the first chunk.", "summary", []sample{
+			{"_sum", func(s *series) float64 { return s.firstToken.Seconds() }},
+			{"_count", func(s *series) float64 { return float64(s.firstCount) }},
+		}},
+		{"talkative_request_duration_seconds", "Durations of completed requests.", "summary", []sample{
+			{"_sum", func(s *series) float64 { return s.duration.Seconds() }},
+			{"_count", func(s *series) float64 { return float64(s.completed) }},
+		}},
+		{"talkative_clock_skew_total", "Total number of requests whose responses were off the local clock.", "counter", []sample{{"", func(s *series) float64 { return float64(s.skewed) }}}},
+		{"talkative_prompt_tokens_total", "Total number of prompt tokens of completed requests.", "counter", []sample{{"", func(s *series) float64 { return float64(s.prompt) }}}},
+		{"talkative_eval_tokens_total", "Total number of tokens generated by completed requests.", "counter", []sample{{"", func(s *series) float64 { return float64(s.eval) }}}},
+	}
+
+	buf := &bytes.Buffer{}
+
+	for _, metric := range metrics {
+		fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+
+		for _, key := range keys {
+			for _, sample := range metric.samples {
+				fmt.Fprintf(buf, "%s%s{op=%s,model=%s} %g\n", metric.name, sample.suffix, labelValue(key[0]), labelValue(key[1]), sample.value(m.series[key]))
+			}
+		}
+	}
+
+	collectors := m.collectors
+
+	m.mu.Unlock()
+
+	for _, collector := range collectors {
+		if _, err := collector.WriteTo(buf); err != nil {
+			return 0, err
+		}
+	}
+
+	return buf.WriteTo(w)
+}
+
+// labelValue returns the quoted label value, escaped as the Prometheus text exposition format requires.
+func labelValue(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+// ServeHTTP exposes the aggregated metrics for pull based scraping.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteTo(w)
+}
+
+// MetricsPusher periodically pushes aggregated metrics to a Prometheus Pushgateway.
+//
+// This suits short-lived batch jobs which do not live long enough to be scraped.
+type MetricsPusher struct {
+	Metrics  *Metrics          // The metrics to push.
+	URL      string            // The base URL of the Pushgateway.
+	Job      string            // The job label of the pushed metrics, it cannot be empty.
+	Grouping map[string]string // Additional grouping labels, i.e: instance. (Optional)
+	Interval time.Duration     // Interval between two pushes, defaults to 15 seconds.
+	Client   *http.Client      // The HTTP client used to push, defaults to http.DefaultClient.
+	OnError  func(error)       // Invoked when a push fails. (Optional)
+}
+
+// Push pushes the current metrics once, replacing the metrics previously pushed for the same grouping.
+//
+// It returns ErrPushJob when the job is empty.
+func (p *MetricsPusher) Push(ctx context.Context) error {
+	if p.Job == "" {
+		return ErrPushJob
+	}
+
+	body := &bytes.Buffer{}
+
+	if _, err := p.Metrics.WriteTo(body); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, p.endpoint(), body)
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	client := p.Client
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("%w: pushgateway responded with %s", ErrInvoke, res.Status)
+	}
+
+	return nil
+}
+
+// Run pushes the metrics every interval until the context is cancelled, pushing a last time before returning.
+func (p *MetricsPusher) Run(ctx context.Context) {
+	interval := p.Interval
+
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.report(p.Push(context.Background()))
+
+			return
+		case <-ticker.C:
+			p.report(p.Push(ctx))
+		}
+	}
+}
+
+// endpoint returns the Pushgateway URL for the job and grouping labels.
+func (p *MetricsPusher) endpoint() string {
+	path := strings.TrimRight(p.URL, "/") + "/metrics" + pushLabel("job", p.Job)
+	labels := make([]string, 0, len(p.Grouping))
+
+	for label := range p.Grouping {
+		labels = append(labels, label)
+	}
+
+	sort.Strings(labels)
+
+	for _, label := range labels {
+		path += pushLabel(label, p.Grouping[label])
+	}
+
+	return path
+}
+
+// pushLabel returns the path segments of the label in a Pushgateway URL. Values which are empty or contain
+// a slash are base64 encoded, as the Pushgateway requires, i.e: "/instance@base64/aG9zdC8x".
+func pushLabel(label, value string) string {
+	if value == "" {
+		return "/" + url.PathEscape(label) + "@base64/="
+	}
+
+	if strings.Contains(value, "/") {
+		return "/" + url.PathEscape(label) + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+
+	return "/" + url.PathEscape(label) + "/" + url.PathEscape(value)
+}
+
+// report hands the push error to the configured handler.
+func (p *MetricsPusher) report(err error) {
+	if err != nil && p.OnError != nil {
+		p.OnError(err)
+	}
+}
@@ -0,0 +1,101 @@
+package talkative_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMetrics tests aggregation of client metrics, pull based exposure and pushing to a Pushgateway.
+func TestMetrics(t *testing.T) {
+	server := streamServer(talkative.ChatResponse{
+		Message:     talkative.ChatMessage{Content: "Hi"},
+		Done:        true,
+		ChatMetrics: talkative.ChatMetrics{PromptEvalCount: 5, EvalCount: 3},
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	metrics := talkative.NewMetrics()
+	metrics.Observe(client)
+
+	done, _ := client.Chat("llama3", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	<-done
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Contains(t, recorder.Body.String(), `talkative_requests_total{op="chat",model="llama3"} 1`)
+	assert.Contains(t, recorder.Body.String(), `talkative_requests_completed_total{op="chat",model="llama3"} 1`)
+	assert.Contains(t, recorder.Body.String(), "# TYPE talkative_request_duration_seconds summary\n")
+	assert.Contains(t, recorder.Body.String(), `talkative_request_duration_seconds_count{op="chat",model="llama3"} 1`)
+	assert.NotContains(t, recorder.Body.String(), "# TYPE talkative_request_duration_seconds_sum")
+	assert.Contains(t, recorder.Body.String(), `talkative_prompt_tokens_total{op="chat",model="llama3"} 5`)
+	assert.Contains(t, recorder.Body.String(), `talkative_eval_tokens_total{op="chat",model="llama3"} 3`)
+
+	var (
+		method, path, pushed string
+	)
+
+	gateway := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		method, path, pushed = r.Method, r.URL.Path, string(body)
+	}))
+	defer gateway.Close()
+
+	pusher := &talkative.MetricsPusher{
+		Metrics:  metrics,
+		URL:      gateway.URL,
+		Job:      "batch",
+		Grouping: map[string]string{"instance": "worker-1"},
+	}
+
+	assert.NoError(t, pusher.Push(context.Background()))
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/metrics/job/batch/instance/worker-1", path)
+	assert.Equal(t, recorder.Body.String(), pushed)
+}
+
+// TestMetricsPusherGrouping tests validating the job and encoding the grouping labels in the Pushgateway URL.
+func TestMetricsPusherGrouping(t *testing.T) {
+	paths := make(chan string, 1)
+
+	gateway := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.EscapedPath()
+	}))
+	defer gateway.Close()
+
+	pusher := &talkative.MetricsPusher{Metrics: talkative.NewMetrics(), URL: gateway.URL}
+	assert.ErrorIs(t, pusher.Push(context.Background()), talkative.ErrPushJob)
+
+	pusher.Job = "batch/nightly"
+	pusher.Grouping = map[string]string{"instance": "", "zone": "eu west"}
+
+	assert.NoError(t, pusher.Push(context.Background()))
+	assert.Equal(t, "/metrics/job@base64/YmF0Y2gvbmlnaHRseQ/instance@base64/=/zone/eu%20west", <-paths)
+}
+
+// TestMetricsLabels tests the escaping of label values in the Prometheus text exposition format.
+func TestMetricsLabels(t *testing.T) {
+	server := streamServer(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hi"}, Done: true})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	metrics := talkative.NewMetrics()
+	metrics.Observe(client)
+
+	done, _ := client.Chat("modèle \"v2\"\\\n", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	<-done
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Contains(t, recorder.Body.String(), `talkative_requests_total{op="chat",model="modèle \"v2\"\\\n"} 1`)
+}
@@ -0,0 +1,323 @@
+package talkative
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Model describes a model available on the server.
+type Model struct {
+	Name       string       `json:"name"`        // The name of the model, i.e: "llama2:latest".
+	Model      string       `json:"model"`       // The identifier of the model.
+	ModifiedAt time.Time    `json:"modified_at"` // The time the model was last modified.
+	Size       int64        `json:"size"`        // The size of the model in bytes.
+	Digest     string       `json:"digest"`      // The digest of the model.
+	Details    ModelDetails `json:"details"`     // The details of the model.
+}
+
+// ModelDetails describes the format and architecture of a model.
+type ModelDetails struct {
+	ParentModel       string   `json:"parent_model"`       // The model this model was created from, if any.
+	Format            string   `json:"format"`             // The format of the model file, i.e: "gguf".
+	Family            string   `json:"family"`             // The family of the model, i.e: "llama".
+	Families          []string `json:"families"`           // The families of the model.
+	ParameterSize     string   `json:"parameter_size"`     // The number of parameters, i.e: "7B".
+	QuantizationLevel string   `json:"quantization_level"` // The quantization level, i.e: "Q4_0".
+}
+
+// ListModels returns the models available locally on the server.
+func (c *Client) ListModels() ([]Model, error) {
+	return c.ListModelsContext(context.Background())
+}
+
+// ListModelsContext is identical to ListModels(), except that the request is bound to the context.
+func (c *Client) ListModelsContext(ctx context.Context) ([]Model, error) {
+	res, err := c.get(ctx, c.urls["tags"])
+
+	if err != nil {
+		return nil, wrapError("list", c.urls["tags"], "", "", err)
+	}
+
+	list, err := decode[struct {
+		Models []Model `json:"models"`
+	}](res)
+
+	if err != nil {
+		return nil, wrapError("list", c.urls["tags"], "", "", err)
+	}
+
+	return list.Models, nil
+}
+
+// RunningModel describes a model currently loaded in memory.
+type RunningModel struct {
+	Name      string       `json:"name"`       // The name of the model, i.e: "llama2:latest".
+	Model     string       `json:"model"`      // The identifier of the model.
+	Size      int64        `json:"size"`       // The memory used by the model in bytes.
+	SizeVRAM  int64        `json:"size_vram"`  // The video memory used by the model in bytes.
+	Digest    string       `json:"digest"`     // The digest of the model.
+	ExpiresAt time.Time    `json:"expires_at"` // The time the model will be unloaded unless used.
+	Details   ModelDetails `json:"details"`    // The details of the model.
+}
+
+// RunningModels returns the models currently loaded in memory.
+func (c *Client) RunningModels() ([]RunningModel, error) {
+	return c.RunningModelsContext(context.Background())
+}
+
+// RunningModelsContext is identical to RunningModels(), except that the request is bound to the context.
+func (c *Client) RunningModelsContext(ctx context.Context) ([]RunningModel, error) {
+	res, err := c.get(ctx, c.urls["ps"])
+
+	if err != nil {
+		return nil, wrapError("ps", c.urls["ps"], "", "", err)
+	}
+
+	list, err := decode[struct {
+		Models []RunningModel `json:"models"`
+	}](res)
+
+	if err != nil {
+		return nil, wrapError("ps", c.urls["ps"], "", "", err)
+	}
+
+	return list.Models, nil
+}
+
+// ShowResponse describes a model in details.
+type ShowResponse struct {
+	License    string         `json:"license"`    // The license of the model.
+	Modelfile  string         `json:"modelfile"`  // The Modelfile of the model.
+	Parameters string         `json:"parameters"` // The parameters of the Modelfile, one per line.
+	Template   string         `json:"template"`   // The prompt template of the model.
+	System     string         `json:"system"`     // The system message of the model.
+	Details    ModelDetails   `json:"details"`    // The details of the model.
+	ModelInfo  map[string]any `json:"model_info"` // The metadata of the model file, i.e: "llama.context_length".
+}
+
+// ContextLength returns the context length of the model read from its metadata, or 0 when unknown.
+func (s *ShowResponse) ContextLength() int {
+	for key, value := range s.ModelInfo {
+		if !strings.HasSuffix(key, ".context_length") {
+			continue
+		}
+
+		if length, ok := value.(float64); ok {
+			return int(length)
+		}
+	}
+
+	return 0
+}
+
+// ShowModel returns the details of the model, i.e: its template and parameters.
+func (c *Client) ShowModel(name string) (*ShowResponse, error) {
+	return c.ShowModelContext(context.Background(), name)
+}
+
+// ShowModelContext is identical to ShowModel(), except that the request is bound to the context.
+func (c *Client) ShowModelContext(ctx context.Context, name string) (*ShowResponse, error) {
+	if name == "" {
+		return nil, ErrModel
+	}
+
+	res, err := c.do(ctx, http.MethodPost, c.urls["show"], map[string]string{"name": name})
+
+	if err != nil {
+		return nil, wrapError("show", c.urls["show"], name, "", err)
+	}
+
+	show, err := decode[ShowResponse](res)
+
+	if err != nil {
+		return nil, wrapError("show", c.urls["show"], name, "", err)
+	}
+
+	return show, nil
+}
+
+// DeleteModel deletes the model and its data from the server.
+func (c *Client) DeleteModel(name string) error {
+	return c.DeleteModelContext(context.Background(), name)
+}
+
+// DeleteModelContext is identical to DeleteModel(), except that the request is bound to the context.
+func (c *Client) DeleteModelContext(ctx context.Context, name string) error {
+	if name == "" {
+		return ErrModel
+	}
+
+	res, err := c.do(ctx, http.MethodDelete, c.urls["delete"], map[string]string{"name": name})
+
+	if err != nil {
+		return wrapError("delete", c.urls["delete"], name, "", err)
+	}
+
+	return res.Body.Close()
+}
+
+// CopyModel copies the model src to a new model named dst, i.e: to give it another tag.
+func (c *Client) CopyModel(src, dst string) error {
+	return c.CopyModelContext(context.Background(), src, dst)
+}
+
+// CopyModelContext is identical to CopyModel(), except that the request is bound to the context.
+func (c *Client) CopyModelContext(ctx context.Context, src, dst string) error {
+	if src == "" || dst == "" {
+		return ErrModel
+	}
+
+	res, err := c.do(ctx, http.MethodPost, c.urls["copy"], map[string]string{"source": src, "destination": dst})
+
+	if err != nil {
+		return wrapError("copy", c.urls["copy"], src, "", err)
+	}
+
+	return res.Body.Close()
+}
+
+// Progress reports the progress of a long running model operation, i.e: a download or a creation.
+type Progress struct {
+	Status    string `json:"status"`          // The current status, i.e: "pulling manifest" or "success".
+	Digest    string `json:"digest"`          // The digest of the layer being transferred, if any.
+	Total     int64  `json:"total"`           // The size of the layer in bytes.
+	Completed int64  `json:"completed"`       // The number of bytes of the layer transferred so far.
+	Error     string `json:"error,omitempty"` // The error reported by the server, if any.
+}
+
+// ProgressCallback is the callback handling the progress of a model operation and errors.
+type ProgressCallback func(*Progress, error)
+
+// PullProgress reports the progress of a model download.
+type PullProgress = Progress
+
+// PullProgressCallback is the callback handling the progress of a model download and errors.
+type PullProgressCallback = ProgressCallback
+
+// registryRequest is the request body of the pull and push endpoints.
+type registryRequest struct {
+	Name     string `json:"name"`               // The name of the model to pull or push.
+	Insecure bool   `json:"insecure,omitempty"` // Whether insecure connections to the registry are allowed.
+}
+
+// createRequest is the request body of the create endpoint.
+type createRequest struct {
+	Name      string `json:"name"`      // The name of the model to create.
+	Modelfile string `json:"modelfile"` // The content of the Modelfile.
+}
+
+// PullModel downloads the model from the registry, streaming its progress to the callback.
+//
+// It returns a channel signalling the end of the download like Chat() does. Errors reported by the
+// server while pulling are passed to the callback, after which the stream stops.
+func (c *Client) PullModel(name string, cb PullProgressCallback) (<-chan bool, error) {
+	return c.PullModelContext(context.Background(), name, cb)
+}
+
+// PullModelContext is identical to PullModel(), except that the request is bound to the context.
+func (c *Client) PullModelContext(ctx context.Context, name string, cb PullProgressCallback) (<-chan bool, error) {
+	if name == "" {
+		return nil, ErrModel
+	}
+
+	return c.progress(ctx, "pull", name, c.urls["pull"], registryRequest{Name: name}, cb)
+}
+
+// PushModel uploads the model to its registry, streaming its progress to the callback.
+//
+// The name of the model must include the registry namespace, i.e: "registry.example.com/team/model:latest".
+// The optional insecure argument allows insecure connections to the registry, i.e: a private registry without TLS.
+//
+// It returns a channel signalling the end of the upload like Chat() does. Errors reported by the
+// server while pushing are passed to the callback, after which the stream stops.
+func (c *Client) PushModel(name string, cb ProgressCallback, insecure ...bool) (<-chan bool, error) {
+	return c.PushModelContext(context.Background(), name, cb, insecure...)
+}
+
+// PushModelContext is identical to PushModel(), except that the request is bound to the context.
+func (c *Client) PushModelContext(ctx context.Context, name string, cb ProgressCallback, insecure ...bool) (<-chan bool, error) {
+	if name == "" {
+		return nil, ErrModel
+	}
+
+	request := registryRequest{Name: name}
+
+	if len(insecure) > 0 {
+		request.Insecure = insecure[0]
+	}
+
+	return c.progress(ctx, "push", name, c.urls["push"], request, cb)
+}
+
+// CreateModel creates the model from the content of a Modelfile, streaming its status to the callback.
+//
+// It returns a channel signalling the end of the creation like Chat() does. Errors reported by the
+// server while creating are passed to the callback, after which the stream stops.
+func (c *Client) CreateModel(name, modelfile string, cb ProgressCallback) (<-chan bool, error) {
+	return c.CreateModelContext(context.Background(), name, modelfile, cb)
+}
+
+// CreateModelContext is identical to CreateModel(), except that the request is bound to the context.
+func (c *Client) CreateModelContext(ctx context.Context, name, modelfile string, cb ProgressCallback) (<-chan bool, error) {
+	if name == "" {
+		return nil, ErrModel
+	}
+
+	if modelfile == "" {
+		return nil, ErrModelfile
+	}
+
+	return c.progress(ctx, "create", name, c.urls["create"], createRequest{Name: name, Modelfile: modelfile}, cb)
+}
+
+// progress sends the request of the operation on the model to the endpoint and streams the progress objects
+// of the response to the callback.
+func (c *Client) progress(ctx context.Context, op, model, endpoint string, request any, cb ProgressCallback) (<-chan bool, error) {
+	if cb == nil {
+		return nil, ErrCallback
+	}
+
+	res, err := c.post(ctx, endpoint, request)
+
+	if err != nil {
+		return nil, wrapError(op, endpoint, model, "", err)
+	}
+
+	chDone := make(chan bool, 1)
+
+	go func() {
+		defer c.guard(op, func(err error) {
+			notify(cb, wrapError(op, endpoint, model, "", err))
+
+			signalDone(chDone)
+		})()
+
+		failed := false
+
+		StreamResponse(res.Body, func(progress *Progress, err error) {
+			if failed {
+				return
+			}
+
+			if err == nil && progress.Error != "" {
+				err = errors.New(progress.Error)
+			}
+
+			if err != nil {
+				failed = true
+				cb(nil, wrapError(op, endpoint, model, "", err))
+
+				return
+			}
+
+			cb(progress, nil)
+		})
+
+		signalDone(chDone)
+	}()
+
+	return chDone, nil
+}
@@ -0,0 +1,299 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestListModels tests listing the local models of the server.
+func TestListModels(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/tags", r.URL.Path)
+
+		w.Write([]byte(`{"models": [{
+			"name": "llama2:latest",
+			"model": "llama2:latest",
+			"modified_at": "2024-05-01T10:00:00Z",
+			"size": 3825819519,
+			"digest": "fe938a131f40",
+			"details": {"format": "gguf", "family": "llama", "families": ["llama"], "parameter_size": "7B", "quantization_level": "Q4_0"}
+		}]}`))
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	models, err := client.ListModels()
+	{
+		assert.NoError(t, err)
+		assert.Len(t, models, 1)
+		assert.Equal(t, "llama2:latest", models[0].Name)
+		assert.Equal(t, int64(3825819519), models[0].Size)
+		assert.Equal(t, 2024, models[0].ModifiedAt.Year())
+		assert.Equal(t, "7B", models[0].Details.ParameterSize)
+	}
+}
+
+// TestListModelsError tests the error returned when the server fails.
+func TestListModelsError(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	_, err := client.ListModels()
+	{
+		assert.ErrorIs(t, err, talkative.ErrInvoke)
+	}
+}
+
+// TestPullModel tests streaming the progress of a model download.
+func TestPullModel(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		assert.Equal(t, "/api/pull", r.URL.Path)
+		assert.Equal(t, "llama2", request["name"])
+
+		encoder := json.NewEncoder(w)
+		encoder.Encode(talkative.PullProgress{Status: "pulling manifest"})
+		encoder.Encode(talkative.PullProgress{Status: "downloading", Digest: "sha256:29fdb92e57cf", Total: 100, Completed: 50})
+		encoder.Encode(talkative.PullProgress{Status: "success"})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	progress := []talkative.PullProgress{}
+
+	done, err := client.PullModel("llama2", func(p *talkative.PullProgress, err error) {
+		assert.NoError(t, err)
+		progress = append(progress, *p)
+	})
+	{
+		assert.NoError(t, err)
+	}
+
+	<-done
+	assert.Len(t, progress, 3)
+	assert.Equal(t, int64(50), progress[1].Completed)
+	assert.Equal(t, "success", progress[2].Status)
+}
+
+// TestPullModelError tests errors reported by the server while pulling.
+func TestPullModelError(t *testing.T) {
+	server := streamServer(talkative.PullProgress{Status: "pulling manifest"}, talkative.PullProgress{Error: "pull model manifest: file does not exist"})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	_, err := client.PullModel("", func(p *talkative.PullProgress, err error) {})
+	{
+		assert.ErrorIs(t, err, talkative.ErrModel)
+	}
+
+	var pullErr error
+
+	done, _ := client.PullModel("missing", func(p *talkative.PullProgress, err error) {
+		if err != nil {
+			pullErr = err
+		}
+	})
+
+	<-done
+	assert.ErrorContains(t, pullErr, "pull model manifest: file does not exist")
+
+	var e *talkative.Error
+
+	if assert.ErrorAs(t, pullErr, &e) {
+		assert.Equal(t, "pull", e.Op)
+		assert.Equal(t, "missing", e.Model)
+		assert.Equal(t, server.URL+"/api/pull", e.Endpoint)
+	}
+}
+
+// TestDeleteModel tests deleting a model.
+func TestDeleteModel(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]string
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/delete", r.URL.Path)
+
+		if request["name"] != "llama2" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	assert.NoError(t, client.DeleteModel("llama2"))
+	assert.ErrorIs(t, client.DeleteModel("missing"), talkative.ErrInvoke)
+	assert.ErrorIs(t, client.DeleteModel(""), talkative.ErrModel)
+}
+
+// TestCopyModel tests copying a model.
+func TestCopyModel(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]string
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/copy", r.URL.Path)
+		assert.Equal(t, map[string]string{"source": "llama2", "destination": "llama2-backup"}, request)
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	assert.NoError(t, client.CopyModel("llama2", "llama2-backup"))
+	assert.ErrorIs(t, client.CopyModel("llama2", ""), talkative.ErrModel)
+}
+
+// TestShowModel tests describing a model.
+func TestShowModel(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]string
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		assert.Equal(t, "/api/show", r.URL.Path)
+		assert.Equal(t, "llama2", request["name"])
+
+		w.Write([]byte(`{
+			"modelfile": "FROM llama2",
+			"parameters": "stop \"[INST]\"",
+			"template": "[INST] {{ .Prompt }} [/INST]",
+			"details": {"family": "llama", "parameter_size": "7B"},
+			"model_info": {"general.architecture": "llama", "llama.context_length": 4096}
+		}`))
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	show, err := client.ShowModel("llama2")
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "[INST] {{ .Prompt }} [/INST]", show.Template)
+		assert.Equal(t, "llama", show.Details.Family)
+		assert.Equal(t, 4096, show.ContextLength())
+	}
+
+	_, err = client.ShowModel("")
+	{
+		assert.ErrorIs(t, err, talkative.ErrModel)
+	}
+}
+
+// TestCreateModel tests creating a model from a Modelfile.
+func TestCreateModel(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]string
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		assert.Equal(t, "/api/create", r.URL.Path)
+		assert.Equal(t, "mario", request["name"])
+		assert.Equal(t, "FROM llama2\nSYSTEM You are Mario.", request["modelfile"])
+
+		encoder := json.NewEncoder(w)
+		encoder.Encode(talkative.Progress{Status: "reading model metadata"})
+		encoder.Encode(talkative.Progress{Status: "success"})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	statuses := []string{}
+
+	done, err := client.CreateModel("mario", "FROM llama2\nSYSTEM You are Mario.", func(p *talkative.Progress, err error) {
+		assert.NoError(t, err)
+		statuses = append(statuses, p.Status)
+	})
+	{
+		assert.NoError(t, err)
+	}
+
+	<-done
+	assert.Equal(t, []string{"reading model metadata", "success"}, statuses)
+
+	_, err = client.CreateModel("mario", "", func(p *talkative.Progress, err error) {})
+	{
+		assert.ErrorIs(t, err, talkative.ErrModelfile)
+	}
+}
+
+// TestPushModel tests uploading a model to an insecure registry.
+func TestPushModel(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		assert.Equal(t, "/api/push", r.URL.Path)
+		assert.Equal(t, "registry.local/team/mario:latest", request["name"])
+		assert.Equal(t, true, request["insecure"])
+
+		encoder := json.NewEncoder(w)
+		encoder.Encode(talkative.Progress{Status: "pushing manifest"})
+		encoder.Encode(talkative.Progress{Status: "success"})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	statuses := []string{}
+
+	done, err := client.PushModel("registry.local/team/mario:latest", func(p *talkative.Progress, err error) {
+		assert.NoError(t, err)
+		statuses = append(statuses, p.Status)
+	}, true)
+	{
+		assert.NoError(t, err)
+	}
+
+	<-done
+	assert.Equal(t, []string{"pushing manifest", "success"}, statuses)
+}
+
+// TestRunningModels tests listing the models loaded in memory.
+func TestRunningModels(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/ps", r.URL.Path)
+
+		w.Write([]byte(`{"models": [{
+			"name": "mistral:latest",
+			"model": "mistral:latest",
+			"size": 5137025024,
+			"size_vram": 5137025024,
+			"digest": "2ae6f6dd7a3d",
+			"expires_at": "2024-06-04T14:38:31.83753-07:00",
+			"details": {"family": "llama"}
+		}]}`))
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	models, err := client.RunningModels()
+	{
+		assert.NoError(t, err)
+		assert.Len(t, models, 1)
+		assert.Equal(t, "mistral:latest", models[0].Name)
+		assert.Equal(t, int64(5137025024), models[0].SizeVRAM)
+		assert.Equal(t, 2024, models[0].ExpiresAt.Year())
+	}
+}
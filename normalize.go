@@ -0,0 +1,94 @@
+package talkative
+
+import "strings"
+
+// NormalizeOptions configures NormalizeMessages.
+type NormalizeOptions struct {
+	Separator string // Separator used when merging the content of messages, defaults to a blank line.
+
+	SystemFirst bool // Whether system messages are merged into a single system message at the start.
+	UserFirst   bool // Whether assistant messages preceding the first user message are dropped.
+}
+
+// NormalizeMessages returns a normalized copy of the messages, the given slice is left untouched.
+//
+// Messages without content nor images are dropped and consecutive messages with the same role are merged,
+// so the roles alternate as some models require. The options enforce additional ordering constraints.
+func NormalizeMessages(msgs []ChatMessage, opts NormalizeOptions) []ChatMessage {
+	if opts.Separator == "" {
+		opts.Separator = "\n\n"
+	}
+
+	normalized := make([]ChatMessage, 0, len(msgs))
+	system := []string{}
+
+	for _, msg := range msgs {
+		if strings.TrimSpace(msg.Content) == "" && len(msg.Images) == 0 {
+			continue
+		}
+
+		if opts.SystemFirst && msg.Role == SYSTEM {
+			system = append(system, msg.Content)
+
+			continue
+		}
+
+		if opts.UserFirst && msg.Role == ASSISTANT && !hasRole(normalized, USER) {
+			continue
+		}
+
+		if last := len(normalized) - 1; last >= 0 && normalized[last].Role == msg.Role {
+			normalized[last].Content = joinContent(normalized[last].Content, msg.Content, opts.Separator)
+
+			if len(msg.Images) > 0 {
+				normalized[last].Images = append(append([]string{}, normalized[last].Images...), msg.Images...)
+			}
+
+			continue
+		}
+
+		normalized = append(normalized, msg)
+	}
+
+	if len(system) > 0 {
+		normalized = append([]ChatMessage{{Role: SYSTEM, Content: strings.Join(system, opts.Separator)}}, normalized...)
+	}
+
+	return normalized
+}
+
+// joinContent joins the contents with the separator, skipping blank ones.
+func joinContent(a, b, separator string) string {
+	switch {
+	case strings.TrimSpace(a) == "":
+		return b
+	case strings.TrimSpace(b) == "":
+		return a
+	default:
+		return a + separator + b
+	}
+}
+
+// hasRole reports whether any of the messages has the role.
+func hasRole(msgs []ChatMessage, role Role) bool {
+	for _, msg := range msgs {
+		if msg.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithMessageNormalization normalizes the messages of every chat with NormalizeMessages before it is sent.
+func WithMessageNormalization(opts NormalizeOptions) Option {
+	return WithChatHook(func(request *ChatRequest) error {
+		request.Messages = NormalizeMessages(request.Messages, opts)
+
+		if len(request.Messages) == 0 {
+			return ErrMessage
+		}
+
+		return nil
+	})
+}
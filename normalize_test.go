@@ -0,0 +1,46 @@
+package talkative_test
+
+import (
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNormalizeMessages tests merging, dropping and reordering messages.
+func TestNormalizeMessages(t *testing.T) {
+	msgs := []talkative.ChatMessage{
+		{Role: talkative.ASSISTANT, Content: "Welcome!"},
+		{Role: talkative.USER, Content: "Hi"},
+		{Role: talkative.USER, Content: " "},
+		{Role: talkative.USER, Content: "Are you there?"},
+		{Role: talkative.SYSTEM, Content: "Be brief."},
+		{Role: talkative.ASSISTANT, Content: "Yes."},
+	}
+
+	assert.Equal(t, []talkative.ChatMessage{
+		{Role: talkative.ASSISTANT, Content: "Welcome!"},
+		{Role: talkative.USER, Content: "Hi\n\nAre you there?"},
+		{Role: talkative.SYSTEM, Content: "Be brief."},
+		{Role: talkative.ASSISTANT, Content: "Yes."},
+	}, talkative.NormalizeMessages(msgs, talkative.NormalizeOptions{}))
+
+	assert.Equal(t, []talkative.ChatMessage{
+		{Role: talkative.SYSTEM, Content: "Be brief."},
+		{Role: talkative.USER, Content: "Hi Are you there?"},
+		{Role: talkative.ASSISTANT, Content: "Yes."},
+	}, talkative.NormalizeMessages(msgs, talkative.NormalizeOptions{Separator: " ", SystemFirst: true, UserFirst: true}))
+
+	assert.Equal(t, "Welcome!", msgs[0].Content)
+}
+
+// TestWithMessageNormalization tests rejecting chats left without messages.
+func TestWithMessageNormalization(t *testing.T) {
+	client, _ := talkative.New("http://localhost:11434", talkative.WithMessageNormalization(talkative.NormalizeOptions{}))
+
+	_, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: ""})
+	{
+		assert.ErrorIs(t, err, talkative.ErrMessage)
+	}
+}
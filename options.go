@@ -0,0 +1,173 @@
+package talkative
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ErrTransport is returned by New when an option configures the transport of an HTTP client given with WithHTTPClient
+// whose transport is a custom http.RoundTripper, i.e: WithTLSConfig, WithInsecureSkipVerify or WithUnixSocket.
+var ErrTransport = newError("option requires an *http.Transport")
+
+// Option configures a Client created with New.
+type Option func(*Client)
+
+// Compression represents how request bodies are compressed before being sent to the server.
+type Compression int
+
+const (
+	// No compression is applied to request bodies. This is the default.
+	COMPRESSION_NONE Compression = iota
+
+	// Request bodies are always gzip compressed and sent with "Content-Encoding: gzip".
+	COMPRESSION_GZIP
+
+	// Request bodies are gzip compressed until the server rejects them with "415 Unsupported Media Type",
+	// after which the rejected request is retried uncompressed and compression is disabled for the client.
+	COMPRESSION_AUTO
+)
+
+// WithCompression enables compression of request bodies larger than minSize bytes.
+//
+// Plain Ollama servers do not accept compressed bodies, this is intended for gateways and reverse proxies
+// in front of remote GPU hosts, where long chat histories are sent over slow links.
+func WithCompression(mode Compression, minSize int) Option {
+	return func(c *Client) {
+		c.compression = mode
+		c.compressionMinSize = minSize
+	}
+}
+
+// WithHTTPClient uses the given HTTP client for every request of the client, i.e: to configure proxies,
+// transports, connection pooling or instrumentation. A nil client is ignored.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		if client != nil {
+			c.client = client
+		}
+	}
+}
+
+// WithTLSConfig uses the TLS configuration to connect to the server, i.e: to trust the certificate authority
+// of a self-hosted server or to present a client certificate for mutual TLS.
+//
+// The transport of the HTTP client is copied, leaving HTTP clients given with WithHTTPClient untouched. It requires
+// their transport to be an *http.Transport: custom http.RoundTripper are never replaced, New returns ErrTransport
+// instead, and so do the requests of clients derived with Clone.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		c.transport(func(t *http.Transport) {
+			t.TLSClientConfig = config
+		})
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the server certificate, i.e: for self-signed certificates
+// in development environments. It should never be used in production. Like WithTLSConfig, it requires the
+// transport of the HTTP client to be an *http.Transport.
+func WithInsecureSkipVerify() Option {
+	return func(c *Client) {
+		c.transport(func(t *http.Transport) {
+			if t.TLSClientConfig == nil {
+				t.TLSClientConfig = &tls.Config{}
+			}
+
+			t.TLSClientConfig.InsecureSkipVerify = true
+		})
+	}
+}
+
+// transport configures a copy of the HTTP transport of the client, leaving HTTP clients given with WithHTTPClient
+// and the default transport untouched. Custom http.RoundTripper are left untouched as well, recording ErrTransport.
+func (c *Client) transport(configure func(*http.Transport)) {
+	transport, ok := c.client.Transport.(*http.Transport)
+
+	if !ok && c.client.Transport != nil {
+		c.err = errors.Join(c.err, fmt.Errorf("%w, got %T", ErrTransport, c.client.Transport))
+
+		return
+	}
+
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+
+	transport = transport.Clone()
+	configure(transport)
+
+	client := *c.client
+	client.Transport = transport
+	c.client = &client
+}
+
+// WithUnixSocket connects to the server over the Unix domain socket at path, i.e: "/var/run/ollama.sock",
+// for local deployments where TCP is disabled. The host of the URL given to New is then ignored.
+//
+// New configures it automatically for URLs of the form "unix:///var/run/ollama.sock". The socket is dialed by
+// the HTTP client resulting from all the options, regardless of their order, so it applies to clients given
+// with WithHTTPClient as well, see WithTLSConfig for how their transport is copied and why it must be an *http.Transport.
+func WithUnixSocket(path string) Option {
+	return func(c *Client) {
+		c.socket = path
+	}
+}
+
+// dialSocket makes the HTTP client of the client dial its Unix domain socket.
+func (c *Client) dialSocket() {
+	path := c.socket
+
+	c.transport(func(t *http.Transport) {
+		dialer := &net.Dialer{}
+
+		t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", path)
+		}
+	})
+}
+
+// WithEndpointOverride redirects the named endpoint to path, i.e: for API gateways rewriting paths.
+//
+// Endpoints are named after the Ollama API: "chat", "completion", "embed", "tags", "ps", "show", "pull", "push",
+// "create", "copy", "delete", "blobs", "version" and "transcribe". The path is relative to the base URL of the client,
+// i.e: "/v1/ollama/api/chat", unless it is a full URL.
+func WithEndpointOverride(name, path string) Option {
+	return func(c *Client) {
+		if strings.Contains(path, "://") {
+			c.urls[name] = path
+
+			return
+		}
+
+		c.urls[name] = strings.TrimRight(c.base, "/") + "/" + strings.TrimLeft(path, "/")
+	}
+}
+
+// WithEndpoints redirects the endpoints of the map to their path, like WithEndpointOverride does.
+func WithEndpoints(endpoints map[string]string) Option {
+	return func(c *Client) {
+		for name, path := range endpoints {
+			WithEndpointOverride(name, path)(c)
+		}
+	}
+}
+
+// WithDefaultModel uses the model for the requests which do not specify one, instead of DEFAULT_MODEL.
+func WithDefaultModel(model string) Option {
+	return func(c *Client) {
+		c.defaultModel = model
+	}
+}
+
+// DefaultModel returns the model used for the requests which do not specify one.
+func (c *Client) DefaultModel() string {
+	if c.defaultModel == "" {
+		return DEFAULT_MODEL
+	}
+
+	return c.defaultModel
+}
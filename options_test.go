@@ -0,0 +1,218 @@
+package talkative_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// roundTripper is a http.RoundTripper recording the requests before sending them with the default transport.
+type roundTripper struct {
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.requests = append(rt.requests, req)
+	rt.mu.Unlock()
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// TestWithHTTPClient tests sending the requests with a custom HTTP client.
+func TestWithHTTPClient(t *testing.T) {
+	server := streamServer(talkative.ChatResponse{Done: true})
+	defer server.Close()
+
+	transport := &roundTripper{}
+	client, _ := talkative.New(server.URL, talkative.WithHTTPClient(&http.Client{Transport: transport}))
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+	}
+
+	<-done
+
+	client.ListModels()
+
+	assert.Len(t, transport.requests, 2)
+	assert.Equal(t, "/api/chat", transport.requests[0].URL.Path)
+
+	client, _ = talkative.New(server.URL, talkative.WithHTTPClient(nil))
+	assert.NotNil(t, client)
+}
+
+// TestWithTLSConfig tests connecting to servers with self-signed certificates.
+func TestWithTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"models": []any{}})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	_, err := client.ListModels()
+	assert.Error(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	client, _ = talkative.New(server.URL, talkative.WithTLSConfig(&tls.Config{RootCAs: pool}))
+	_, err = client.ListModels()
+	assert.NoError(t, err)
+
+	custom := &http.Client{}
+	client, _ = talkative.New(server.URL, talkative.WithHTTPClient(custom), talkative.WithInsecureSkipVerify())
+	_, err = client.ListModels()
+	assert.NoError(t, err)
+	assert.Nil(t, custom.Transport)
+}
+
+// TestTransportRoundTripper tests keeping custom round trippers, which transport options cannot configure.
+func TestTransportRoundTripper(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"models": []any{}})
+	})
+	defer server.Close()
+
+	for _, opt := range []talkative.Option{
+		talkative.WithTLSConfig(&tls.Config{}),
+		talkative.WithInsecureSkipVerify(),
+		talkative.WithUnixSocket("/var/run/ollama.sock"),
+	} {
+		client, err := talkative.New(server.URL, talkative.WithHTTPClient(&http.Client{Transport: &roundTripper{}}), opt)
+
+		assert.ErrorIs(t, err, talkative.ErrTransport)
+		assert.Nil(t, client)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	prewarmed := &roundTripper{}
+	client, err := talkative.New(server.URL, talkative.WithHTTPClient(&http.Client{Transport: prewarmed}), talkative.WithPrewarm(talkative.Prewarm{Connections: 4, Context: ctx}))
+	{
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, client.Prewarm(context.Background(), 4))
+
+	prewarmed.mu.Lock()
+	assert.GreaterOrEqual(t, len(prewarmed.requests), 4)
+	prewarmed.mu.Unlock()
+
+	transport := &roundTripper{}
+	client, _ = talkative.New(server.URL, talkative.WithHTTPClient(&http.Client{Transport: transport}))
+
+	_, err = client.Clone(talkative.WithInsecureSkipVerify()).ListModels()
+	assert.ErrorIs(t, err, talkative.ErrTransport)
+	assert.Empty(t, transport.requests)
+
+	_, err = client.ListModels()
+	assert.NoError(t, err)
+	assert.Len(t, transport.requests, 1)
+}
+
+// TestWithUnixSocket tests connecting to servers listening on a Unix domain socket.
+func TestWithUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "ollama.sock")
+	listener, err := net.Listen("unix", socket)
+	{
+		assert.NoError(t, err)
+	}
+
+	server := &httptest.Server{
+		Listener: listener,
+		Config: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]any{"models": []map[string]string{{"name": r.URL.Path}}})
+		})},
+	}
+	server.Start()
+	defer server.Close()
+
+	for _, client := range []*talkative.Client{
+		must(talkative.New("unix://" + socket)),
+		must(talkative.New("http://ollama", talkative.WithUnixSocket(socket))),
+		must(talkative.New("unix://"+socket, talkative.WithHTTPClient(&http.Client{}))),
+		must(talkative.New("http://ollama", talkative.WithUnixSocket(socket), talkative.WithHTTPClient(&http.Client{}))),
+		must(talkative.New("unix://" + socket)).Clone(talkative.WithHTTPClient(&http.Client{})),
+	} {
+		models, err := client.ListModels()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "/api/tags", models[0].Name)
+	}
+}
+
+// must returns the client, ignoring the error of New.
+func must(client *talkative.Client, _ error) *talkative.Client {
+	return client
+}
+
+// TestWithEndpointOverride tests redirecting endpoints to the paths of an API gateway.
+func TestWithEndpointOverride(t *testing.T) {
+	paths := make(chan string, 2)
+
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Done: true})
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL,
+		talkative.WithEndpointOverride("chat", "/v1/ollama/api/chat"),
+		talkative.WithEndpoints(map[string]string{"tags": server.URL + "/gateway/models"}),
+	)
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	assert.NoError(t, err)
+	<-done
+
+	client.ListModels()
+
+	assert.Equal(t, "/v1/ollama/api/chat", <-paths)
+	assert.Equal(t, "/gateway/models", <-paths)
+}
+
+// TestWithDefaultModel tests using the default model of the client for requests without model.
+func TestWithDefaultModel(t *testing.T) {
+	models := make(chan string, 2)
+
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		models <- request.Model
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Done: true})
+	})
+	defer server.Close()
+
+	message := talkative.ChatMessage{Role: talkative.USER, Content: "Hi"}
+
+	for expected, client := range map[string]*talkative.Client{
+		"llama3.1":              must(talkative.New(server.URL, talkative.WithDefaultModel("llama3.1"))),
+		talkative.DEFAULT_MODEL: must(talkative.New(server.URL)),
+	} {
+		assert.Equal(t, expected, client.DefaultModel())
+
+		done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+		assert.NoError(t, err)
+		<-done
+
+		assert.Equal(t, expected, <-models)
+	}
+}
@@ -0,0 +1,486 @@
+package talkative
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DEFAULT_OUTBOX_MAX_BACKOFF is the default maximum delay between two delivery attempts of an outbox entry.
+const DEFAULT_OUTBOX_MAX_BACKOFF = time.Hour
+
+var (
+	ErrOutboxRequest   = newError("outbox entry must contain either a chat or a completion request") // Error for outbox entries holding neither a chat nor a completion request.
+	ErrUnavailable     = newError("backend is unavailable")                                          // Error for outbox entries answered with the fallback response, see WithFallback.
+	ErrOutboxExhausted = newError("outbox entry exhausted its delivery attempts")                    // Error for dead letters, the outbox entries failing MaxAttempts times, see OutboxRetry.
+)
+
+// OutboxEntry represents a request persisted in the outbox until it has been delivered.
+type OutboxEntry struct {
+	Key         string             `json:"key"`                    // The idempotency key identifying the request.
+	Chat        *ChatRequest       `json:"chat,omitempty"`         // The chat request to be delivered.
+	Completion  *CompletionRequest `json:"completion,omitempty"`   // The completion request to be delivered.
+	Attempts    int                `json:"attempts"`               // Number of failed delivery attempts so far.
+	LastError   string             `json:"last_error,omitempty"`   // The error of the last failed delivery attempt.
+	NextAttempt time.Time          `json:"next_attempt,omitempty"` // Time before which the entry is not delivered again, see OutboxRetry.
+	CreatedAt   time.Time          `json:"created_at"`             // Time the entry was added to the outbox.
+}
+
+// OutboxStore persists outbox entries. Implementations must be safe for concurrent use.
+type OutboxStore interface {
+	// Put adds the entry to the store, or replaces the entry having the same key.
+	Put(entry OutboxEntry) error
+
+	// Get returns the entry with the given key, the boolean reports whether it exists.
+	Get(key string) (OutboxEntry, bool, error)
+
+	// List returns all entries ordered by creation time.
+	List() ([]OutboxEntry, error)
+
+	// Delete removes the entry with the given key, deleting a missing entry is not an error.
+	Delete(key string) error
+}
+
+// OutboxHandler receives the outcome of an outbox entry.
+//
+// It is invoked with the aggregated content when the request has been delivered, or with the error
+// when the request was rejected by the server, i.e: bad request or unknown model, and will not be retried. Entries
+// failing MaxAttempts times are handed to it as dead letters, with an error matching ErrOutboxExhausted. Returning an
+// error keeps the entry in the outbox so it is delivered again later (at-least-once semantics).
+type OutboxHandler func(entry OutboxEntry, content string, err error) error
+
+// OutboxOption configures an Outbox created with NewOutbox.
+type OutboxOption func(*Outbox)
+
+// OutboxRetry configures WithOutboxRetry.
+type OutboxRetry struct {
+	MaxAttempts int           // The number of failed attempts after which entries are dead letters, 0 retries them forever.
+	Backoff     time.Duration // The delay before the second attempt of an entry, doubled for every attempt, 0 retries it on every flush.
+	MaxBackoff  time.Duration // The maximum delay between two attempts of an entry, defaults to DEFAULT_OUTBOX_MAX_BACKOFF.
+}
+
+// WithOutboxRetry limits the delivery attempts of the entries and delays their next attempt after every failure,
+// so an entry failing repeatedly neither blocks the entries submitted after it nor stays in the outbox forever.
+func WithOutboxRetry(retry OutboxRetry) OutboxOption {
+	return func(o *Outbox) {
+		if retry.MaxBackoff <= 0 {
+			retry.MaxBackoff = DEFAULT_OUTBOX_MAX_BACKOFF
+		}
+
+		o.retry = retry
+	}
+}
+
+// Outbox is a durable queue for non-interactive requests.
+//
+// Requests are persisted in the store before being sent and removed once delivered. Requests which fail
+// because the server is unavailable stay in the store and are retried by Flush or Run, so they survive
+// process restarts when a durable store is used. Keys make submissions idempotent, submitting a key which
+// is still pending is a no-op.
+type Outbox struct {
+	client  *Client
+	store   OutboxStore
+	handler OutboxHandler
+	retry   OutboxRetry
+	mu      sync.Mutex // Serialises flushes so entries are not delivered concurrently.
+}
+
+// NewOutbox creates a new Outbox delivering its requests through the client.
+func NewOutbox(client *Client, store OutboxStore, handler OutboxHandler, opts ...OutboxOption) *Outbox {
+	o := &Outbox{
+		client:  client,
+		store:   store,
+		handler: handler,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// Submit persists the entry in the outbox and returns its key.
+//
+// A random key is generated when entry.Key is empty. Submitting a key which is already pending is a no-op.
+func (o *Outbox) Submit(entry OutboxEntry) (string, error) {
+	if (entry.Chat == nil) == (entry.Completion == nil) {
+		return "", ErrOutboxRequest
+	}
+
+	if entry.Key == "" {
+		entry.Key = o.client.NewID()
+	}
+
+	if _, ok, err := o.store.Get(entry.Key); err != nil || ok {
+		return entry.Key, err
+	}
+
+	entry.Attempts = 0
+	entry.LastError = ""
+	entry.NextAttempt = time.Time{}
+	entry.CreatedAt = time.Now()
+
+	return entry.Key, o.store.Put(entry)
+}
+
+// Flush attempts to deliver all pending entries in submission order.
+//
+// It stops at the first entry failing because the server is unavailable and returns that error,
+// leaving it and the remaining entries for the next flush. Entries answered with the fallback response of
+// the client, see WithFallback, are considered unavailable and stay in the outbox.
+//
+// With WithOutboxRetry, entries waiting for their next attempt are skipped, and entries failing for the last
+// time are handed to the handler as dead letters instead of stopping the flush.
+func (o *Outbox) Flush() error {
+	return o.FlushContext(context.Background())
+}
+
+// FlushContext is identical to Flush(), except that the deliveries are bound to the context.
+//
+// Cancelling the context aborts the delivery in progress, the entry is then kept for the next flush.
+func (o *Outbox) FlushContext(ctx context.Context) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entries, err := o.store.List()
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.NextAttempt.After(time.Now()) {
+			continue
+		}
+
+		content, err := o.deliver(ctx, entry)
+
+		if err != nil && !rejected(err) {
+			entry.Attempts++
+			entry.LastError = err.Error()
+			entry.NextAttempt = time.Now().Add(o.backoff(entry.Attempts))
+
+			if err := o.store.Put(entry); err != nil {
+				return err
+			}
+
+			// Cancelled deliveries say nothing about the entry, so they never make it a dead letter.
+			if o.retry.MaxAttempts <= 0 || entry.Attempts < o.retry.MaxAttempts || ctx.Err() != nil {
+				return err
+			}
+
+			err = fmt.Errorf("%w after %d attempts: %w", ErrOutboxExhausted, entry.Attempts, err)
+		}
+
+		if o.handler != nil {
+			if err := o.handler(entry, content, err); err != nil {
+				continue
+			}
+		}
+
+		if err := o.store.Delete(entry.Key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backoff returns the delay before the next attempt of an entry which failed the given number of times.
+func (o *Outbox) backoff(attempts int) time.Duration {
+	delay := o.retry.Backoff
+
+	for i := 1; i < attempts && delay < o.retry.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, o.retry.MaxBackoff)
+}
+
+// rejected reports whether the request failed permanently, being rejected by the server with a client error
+// other than a timeout or rate limiting, so retrying it would fail again.
+func rejected(err error) bool {
+	var e *APIError
+
+	if errors.Is(err, ErrBadRequest) {
+		return true
+	}
+
+	return errors.As(err, &e) && e.StatusCode >= 400 && e.StatusCode < 500 &&
+		e.StatusCode != http.StatusRequestTimeout && e.StatusCode != http.StatusTooManyRequests
+}
+
+// Run flushes the outbox every interval until the context is cancelled.
+func (o *Outbox) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		o.FlushContext(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver sends the request of the entry and waits for the aggregated content.
+func (o *Outbox) deliver(ctx context.Context, entry OutboxEntry) (string, error) {
+	var (
+		sb        strings.Builder
+		streamErr error
+		done      <-chan bool
+		err       error
+	)
+
+	if entry.Chat != nil {
+		done, err = o.client.ChatContext(ctx, entry.Chat.Model, func(cr *ChatResponse, err error) {
+			if err != nil {
+				streamErr = err
+
+				return
+			}
+
+			if cr.Degraded {
+				streamErr = ErrUnavailable
+
+				return
+			}
+
+			sb.WriteString(cr.Message.Content)
+		}, entry.Chat.ChatParams, entry.Chat.Messages...)
+	} else {
+		done, err = o.client.CompletionContext(ctx, entry.Completion.Model, func(cr *CompletionResponse, err error) {
+			if err != nil {
+				streamErr = err
+
+				return
+			}
+
+			if cr.Degraded {
+				streamErr = ErrUnavailable
+
+				return
+			}
+
+			sb.WriteString(cr.Response)
+		}, &CompletionMessage{
+			Prompt:           entry.Completion.Prompt,
+			Images:           entry.Completion.Images,
+			CompletionParams: entry.Completion.CompletionParams,
+		})
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	<-done
+
+	return sb.String(), streamErr
+}
+
+// MemoryOutboxStore is an OutboxStore keeping the entries in memory.
+//
+// It is not durable across restarts and mostly useful for tests and short-lived processes.
+type MemoryOutboxStore struct {
+	mu      sync.Mutex
+	entries map[string]OutboxEntry
+}
+
+// NewMemoryOutboxStore creates a new, empty MemoryOutboxStore.
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{entries: map[string]OutboxEntry{}}
+}
+
+// Put adds or replaces the entry.
+func (s *MemoryOutboxStore) Put(entry OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[entry.Key] = entry
+
+	return nil
+}
+
+// Get returns the entry with the given key.
+func (s *MemoryOutboxStore) Get(key string) (OutboxEntry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+
+	return entry, ok, nil
+}
+
+// List returns all entries ordered by creation time.
+func (s *MemoryOutboxStore) List() ([]OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]OutboxEntry, 0, len(s.entries))
+
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+
+	sortOutboxEntries(entries)
+
+	return entries, nil
+}
+
+// Delete removes the entry with the given key.
+func (s *MemoryOutboxStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+
+	return nil
+}
+
+// FileOutboxStore is a durable OutboxStore keeping every entry as a JSON file in a directory.
+type FileOutboxStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileOutboxStore creates a new FileOutboxStore in dir, creating the directory when missing.
+func NewFileOutboxStore(dir string) (*FileOutboxStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileOutboxStore{dir: dir}, nil
+}
+
+// Put atomically writes the entry to its file.
+func (s *FileOutboxStore) Put(entry OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(entry)
+
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(s.dir, ".tmp-*")
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+
+		return err
+	}
+
+	return os.Rename(file.Name(), s.path(entry.Key))
+}
+
+// Get reads the entry with the given key.
+func (s *FileOutboxStore) Get(key string) (OutboxEntry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read(s.path(key))
+}
+
+// List reads all entries ordered by creation time.
+func (s *FileOutboxStore) List() ([]OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]OutboxEntry, 0, len(paths))
+
+	for _, path := range paths {
+		entry, ok, err := s.read(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	sortOutboxEntries(entries)
+
+	return entries, nil
+}
+
+// Delete removes the file of the entry with the given key.
+func (s *FileOutboxStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// path returns the file path of the entry with the given key.
+func (s *FileOutboxStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// read decodes the entry stored in the file at path.
+func (s *FileOutboxStore) read(path string) (OutboxEntry, bool, error) {
+	var entry OutboxEntry
+
+	data, err := os.ReadFile(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return entry, false, nil
+	}
+
+	if err != nil {
+		return entry, false, err
+	}
+
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, false, err
+	}
+
+	return entry, true, nil
+}
+
+// sortOutboxEntries orders the entries by creation time.
+func sortOutboxEntries(entries []OutboxEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+}
@@ -0,0 +1,227 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOutbox tests that requests are kept while the server is unavailable and delivered once it is back,
+// using both the memory and the file based stores.
+func TestOutbox(t *testing.T) {
+	available := false
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		if request.Model == "missing" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "model not found"}`))
+
+			return
+		}
+
+		if request.Model == "unknown" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "model 'unknown' not found"}`))
+
+			return
+		}
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{
+			Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: "Paris"},
+			Done:    true,
+		})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	fileStore, err := talkative.NewFileOutboxStore(t.TempDir())
+	{
+		assert.NoError(t, err)
+	}
+
+	stores := map[string]talkative.OutboxStore{
+		"memory": talkative.NewMemoryOutboxStore(),
+		"file":   fileStore,
+	}
+
+	for name, store := range stores {
+		t.Run("outbox-"+name, func(t *testing.T) {
+			available = false
+			results := map[string]string{}
+			failures := map[string]error{}
+
+			outbox := talkative.NewOutbox(client, store, func(entry talkative.OutboxEntry, content string, err error) error {
+				if err != nil {
+					failures[entry.Key] = err
+				} else {
+					results[entry.Key] = content
+				}
+
+				return nil
+			})
+
+			_, err := outbox.Submit(talkative.OutboxEntry{})
+			assert.ErrorIs(t, err, talkative.ErrOutboxRequest)
+
+			request := &talkative.ChatRequest{
+				Messages: []talkative.ChatMessage{{Role: talkative.USER, Content: "What is the capital of France?"}},
+			}
+
+			key, err := outbox.Submit(talkative.OutboxEntry{Key: "capital", Chat: request})
+			assert.NoError(t, err)
+			assert.Equal(t, "capital", key)
+
+			// submitting the same key again is a no-op
+			outbox.Submit(talkative.OutboxEntry{Key: "capital", Chat: request})
+			outbox.Submit(talkative.OutboxEntry{Key: "invalid", Chat: &talkative.ChatRequest{
+				Model:    "missing",
+				Messages: request.Messages,
+			}})
+			outbox.Submit(talkative.OutboxEntry{Key: "unknown", Chat: &talkative.ChatRequest{
+				Model:    "unknown",
+				Messages: request.Messages,
+			}})
+			outbox.Submit(talkative.OutboxEntry{Key: "after", Chat: request})
+
+			assert.ErrorIs(t, outbox.Flush(), talkative.ErrInvoke)
+
+			entries, _ := store.List()
+			assert.Len(t, entries, 4)
+			assert.Equal(t, 1, entries[0].Attempts)
+
+			available = true
+
+			assert.NoError(t, outbox.Flush())
+			assert.Equal(t, map[string]string{"capital": "Paris", "after": "Paris"}, results)
+			assert.ErrorIs(t, failures["invalid"], talkative.ErrBadRequest)
+			assert.ErrorIs(t, failures["unknown"], talkative.ErrNotFound)
+
+			entries, _ = store.List()
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+// TestOutboxFallback tests that entries answered with the fallback response stay in the outbox.
+func TestOutboxFallback(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithFallback("Unavailable"))
+	store := talkative.NewMemoryOutboxStore()
+	delivered := false
+
+	outbox := talkative.NewOutbox(client, store, func(entry talkative.OutboxEntry, content string, err error) error {
+		delivered = true
+
+		return nil
+	})
+
+	outbox.Submit(talkative.OutboxEntry{Key: "capital", Completion: &talkative.CompletionRequest{Prompt: "What is the capital of France?"}})
+
+	assert.ErrorIs(t, outbox.Flush(), talkative.ErrUnavailable)
+	assert.False(t, delivered)
+
+	entries, _ := store.List()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, 1, entries[0].Attempts)
+}
+
+// TestOutboxFlushContext tests that cancelling the flush aborts the delivery in progress and keeps the entry.
+func TestOutboxFlushContext(t *testing.T) {
+	release := make(chan struct{})
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, _ := talkative.New(server.URL)
+	store := talkative.NewMemoryOutboxStore()
+	outbox := talkative.NewOutbox(client, store, nil)
+
+	outbox.Submit(talkative.OutboxEntry{Key: "capital", Completion: &talkative.CompletionRequest{Prompt: "What is the capital of France?"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, outbox.FlushContext(ctx), context.DeadlineExceeded)
+
+	entries, _ := store.List()
+	assert.Len(t, entries, 1)
+}
+
+// TestOutboxRetry tests that failing entries wait for their next attempt without blocking the other entries,
+// and are handed to the handler as dead letters once they failed MaxAttempts times.
+func TestOutboxRetry(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.CompletionRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		if request.Prompt == "poison" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		json.NewEncoder(w).Encode(talkative.CompletionResponse{Response: "Paris", Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	store := talkative.NewMemoryOutboxStore()
+	results := map[string]string{}
+	failures := map[string]error{}
+
+	outbox := talkative.NewOutbox(client, store, func(entry talkative.OutboxEntry, content string, err error) error {
+		if err != nil {
+			failures[entry.Key] = err
+		} else {
+			results[entry.Key] = content
+		}
+
+		return nil
+	}, talkative.WithOutboxRetry(talkative.OutboxRetry{MaxAttempts: 2, Backoff: time.Hour}))
+
+	outbox.Submit(talkative.OutboxEntry{Key: "poison", Completion: &talkative.CompletionRequest{Prompt: "poison"}})
+	outbox.Submit(talkative.OutboxEntry{Key: "capital", Completion: &talkative.CompletionRequest{Prompt: "What is the capital of France?"}})
+
+	assert.ErrorIs(t, outbox.Flush(), talkative.ErrInvoke)
+
+	entry, _, _ := store.Get("poison")
+	assert.Equal(t, 1, entry.Attempts)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), entry.NextAttempt, time.Minute)
+
+	// the entry waits for its next attempt, so the entries after it are delivered
+	assert.NoError(t, outbox.Flush())
+	assert.Equal(t, map[string]string{"capital": "Paris"}, results)
+	assert.Empty(t, failures)
+
+	entry.NextAttempt = time.Now()
+	store.Put(entry)
+
+	assert.NoError(t, outbox.Flush())
+	assert.ErrorIs(t, failures["poison"], talkative.ErrOutboxExhausted)
+	assert.ErrorIs(t, failures["poison"], talkative.ErrInvoke)
+
+	entries, _ := store.List()
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,102 @@
+package talkative
+
+import (
+	"sync"
+	"time"
+)
+
+// WithPacing delays the delivery of consecutive chunks to the callbacks by at least delay, i.e: for a typewriter effect.
+//
+// Pacing only affects the callbacks, the response is still read from the server as fast as it arrives
+// and buffered meanwhile. The done channel signals once every chunk was delivered.
+func WithPacing(delay time.Duration) Option {
+	return func(c *Client) {
+		c.pacing = delay
+	}
+}
+
+// pace wraps the callback so invocations are queued and delivered at least delay apart from a separate goroutine.
+//
+// The returned wait function must be called once the stream ended, it blocks until every queued invocation
+// was delivered. When delay is not positive the callback is returned as is. The delivering goroutine defers guard,
+// see Client.guard, the invocations still queued when it recovers a panic are dropped.
+func pace[T any](delay time.Duration, cb func(T, error), guard func()) (func(T, error), func()) {
+	if delay <= 0 {
+		return cb, func() {}
+	}
+
+	type call struct {
+		value T
+		err   error
+	}
+
+	var (
+		mu     sync.Mutex
+		queue  []call
+		closed bool
+	)
+
+	signal := make(chan struct{}, 1)
+	done := make(chan struct{})
+
+	notify := func() {
+		select {
+		case signal <- struct{}{}:
+		default:
+		}
+	}
+
+	go func() {
+		defer close(done)
+		defer guard()
+
+		var last time.Time
+
+		for {
+			mu.Lock()
+
+			if len(queue) == 0 {
+				finished := closed
+				mu.Unlock()
+
+				if finished {
+					return
+				}
+
+				<-signal
+
+				continue
+			}
+
+			next := queue[0]
+			queue = queue[1:]
+			mu.Unlock()
+
+			if wait := delay - time.Since(last); !last.IsZero() && wait > 0 {
+				time.Sleep(wait)
+			}
+
+			cb(next.value, next.err)
+			last = time.Now()
+		}
+	}()
+
+	paced := func(value T, err error) {
+		mu.Lock()
+		queue = append(queue, call{value, err})
+		mu.Unlock()
+
+		notify()
+	}
+
+	wait := func() {
+		mu.Lock()
+		closed = true
+		mu.Unlock()
+
+		notify()
+		<-done
+	}
+
+	return paced, wait
+}
@@ -0,0 +1,39 @@
+package talkative_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPacing tests delivering chunks at least the pacing delay apart.
+func TestPacing(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "a"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "b"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "c"}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithPacing(30*time.Millisecond))
+	delivered := []time.Time{}
+	content := ""
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {
+		delivered = append(delivered, time.Now())
+		content += cr.Message.Content
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+	}
+
+	<-done
+
+	assert.Equal(t, "abc", content)
+	assert.Len(t, delivered, 3)
+	assert.GreaterOrEqual(t, delivered[1].Sub(delivered[0]), 30*time.Millisecond)
+	assert.GreaterOrEqual(t, delivered[2].Sub(delivered[1]), 30*time.Millisecond)
+}
@@ -0,0 +1,166 @@
+package talkative
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"net/http"
+	texttemplate "text/template"
+	"time"
+)
+
+// ErrStreamTimeout is returned when the streamed output stalls for longer than the timeout of a PageStream.
+var ErrStreamTimeout = newError("stream timed out")
+
+// StreamFuncs returns the template functions of a PageStream, which must be added to the templates before
+// parsing them, i.e: template.New("page").Funcs(talkative.StreamFuncs()).Parse(page).
+//
+// The "stream" function marks where the model output is streamed into the page, it renders nothing by itself.
+func StreamFuncs() map[string]any {
+	return map[string]any{"stream": func() string { return "" }}
+}
+
+// PageStream streams model output into a page rendered with html/template or text/template.
+//
+// The page is written up to the {{stream}} action, then every chunk is written and flushed as it arrives
+// and the rest of the page is written once the stream ends. Chunks streamed into html/template pages are
+// HTML escaped, so the action must be placed in an HTML text context.
+type PageStream struct {
+	timeout   time.Duration
+	chunks    chan string
+	abandoned chan struct{} // Closed once the page gave up on the stream, so the producer doesn't block.
+	err       error
+}
+
+// NewPageStream creates a new page stream which gives up on the model output once no chunk arrived for timeout.
+//
+// When timeout is less than or equal to zero, the stream waits for the output indefinitely.
+func NewPageStream(timeout time.Duration) *PageStream {
+	return &PageStream{timeout: timeout, chunks: make(chan string, 64), abandoned: make(chan struct{})}
+}
+
+// ChatCallback returns a ChatCallBack feeding the content of the chat responses into the page.
+func (p *PageStream) ChatCallback() ChatCallBack {
+	return func(cr *ChatResponse, err error) {
+		if err != nil {
+			p.fail(err)
+
+			return
+		}
+
+		p.write(cr.Message.Content, cr.Done)
+	}
+}
+
+// CompletionCallback returns a CompletionCallback feeding the completion responses into the page.
+func (p *PageStream) CompletionCallback() CompletionCallback {
+	return func(cr *CompletionResponse, err error) {
+		if err != nil {
+			p.fail(err)
+
+			return
+		}
+
+		p.write(cr.Response, cr.Done)
+	}
+}
+
+// Render executes the template into w, streaming the model output at the {{stream}} action.
+//
+// tmpl is either a *html/template.Template or a *text/template.Template parsed with StreamFuncs.
+// The whole page is rendered even when the stream fails or times out, the error is then returned.
+func (p *PageStream) Render(w io.Writer, tmpl any, data any) error {
+	var (
+		streamErr error
+		escape    bool
+	)
+
+	stream := func() string {
+		streamErr = p.copy(w, escape)
+
+		return ""
+	}
+
+	var err error
+
+	switch t := tmpl.(type) {
+	case *htmltemplate.Template:
+		escape = true
+
+		if t, err = t.Clone(); err == nil {
+			err = t.Funcs(htmltemplate.FuncMap{"stream": stream}).Execute(w, data)
+		}
+	case *texttemplate.Template:
+		if t, err = t.Clone(); err == nil {
+			err = t.Funcs(texttemplate.FuncMap{"stream": stream}).Execute(w, data)
+		}
+	default:
+		err = fmt.Errorf("unsupported template type %T", tmpl)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return streamErr
+}
+
+// copy writes the chunks into w until the stream ends, fails or times out, flushing after every chunk.
+func (p *PageStream) copy(w io.Writer, escape bool) error {
+	flusher, _ := w.(http.Flusher)
+
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	var timeout <-chan time.Time
+
+	for {
+		if p.timeout > 0 {
+			timeout = time.After(p.timeout)
+		}
+
+		select {
+		case chunk, ok := <-p.chunks:
+			if !ok {
+				return p.err
+			}
+
+			if escape {
+				chunk = htmltemplate.HTMLEscapeString(chunk)
+			}
+
+			if _, err := io.WriteString(w, chunk); err != nil {
+				return err
+			}
+
+			if flusher != nil {
+				flusher.Flush()
+			}
+		case <-timeout:
+			close(p.abandoned)
+
+			return ErrStreamTimeout
+		}
+	}
+}
+
+// write queues a chunk, closing the stream with the final one.
+func (p *PageStream) write(chunk string, done bool) {
+	if chunk != "" {
+		select {
+		case p.chunks <- chunk:
+		case <-p.abandoned:
+		}
+	}
+
+	if done {
+		close(p.chunks)
+	}
+}
+
+// fail records the error of the stream and closes it.
+func (p *PageStream) fail(err error) {
+	p.err = err
+	close(p.chunks)
+}
@@ -0,0 +1,59 @@
+package talkative_test
+
+import (
+	htmltemplate "html/template"
+	"net/http/httptest"
+	"testing"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPageStream tests streaming escaped output into an HTML page.
+func TestPageStream(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "<b>Hello</b>"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: " & bye"}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	page := htmltemplate.Must(htmltemplate.New("page").Funcs(talkative.StreamFuncs()).Parse(`<h1>{{.Title}}</h1><p>{{stream}}</p><footer/>`))
+	stream := talkative.NewPageStream(time.Second)
+
+	_, err := client.Chat("", stream.ChatCallback(), nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+	}
+
+	recorder := httptest.NewRecorder()
+
+	err = stream.Render(recorder, page, map[string]string{"Title": "A & B"})
+	{
+		assert.NoError(t, err)
+		assert.True(t, recorder.Flushed)
+		assert.Equal(t, "<h1>A &amp; B</h1><p>&lt;b&gt;Hello&lt;/b&gt; &amp; bye</p><footer/>", recorder.Body.String())
+	}
+}
+
+// TestPageStreamTimeout tests rendering the rest of the page when the stream stalls.
+func TestPageStreamTimeout(t *testing.T) {
+	page := texttemplate.Must(texttemplate.New("page").Funcs(talkative.StreamFuncs()).Parse(`[{{stream}}]`))
+	stream := talkative.NewPageStream(20 * time.Millisecond)
+	cb := stream.CompletionCallback()
+
+	cb(&talkative.CompletionResponse{Response: "<partial>"}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	err := stream.Render(recorder, page, nil)
+	{
+		assert.ErrorIs(t, err, talkative.ErrStreamTimeout)
+		assert.Equal(t, "[<partial>]", recorder.Body.String())
+	}
+
+	cb(&talkative.CompletionResponse{Response: "late"}, nil)
+}
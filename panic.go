@@ -0,0 +1,73 @@
+package talkative
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Panic is a panic recovered from an internal goroutine of the client, i.e: raised by a callback.
+type Panic struct {
+	Op    string // The operation of the goroutine, i.e: chat or completion.
+	Value any    // The value the goroutine panicked with.
+	Stack []byte // The stack trace of the goroutine at the time of the panic.
+}
+
+// Error returns the description of the panic.
+func (p *Panic) Error() string {
+	return fmt.Sprintf("panic in %s: %v", p.Op, p.Value)
+}
+
+// Unwrap returns the value of the panic when it is an error.
+func (p *Panic) Unwrap() error {
+	err, _ := p.Value.(error)
+
+	return err
+}
+
+// PanicHandler function type used for reporting the panics recovered from the internal goroutines of the client.
+type PanicHandler func(p *Panic)
+
+// WithPanicHandler reports the panics recovered from the internal goroutines of the client, i.e: raised by
+// callbacks, to the handler along with their stack trace, so services can report them to their error tracker.
+//
+// Panics are recovered with or without a handler: the request of a recovered goroutine is considered failed,
+// its callback receives the *Panic as error and its done channel signals.
+func WithPanicHandler(handler PanicHandler) Option {
+	return func(c *Client) {
+		c.panics = handler
+	}
+}
+
+// guard returns a function to be deferred by the internal goroutines of an operation, which recovers their
+// panics, reports them to the panic handler if any and calls recovered with the *Panic. (recovered is optional)
+func (c *Client) guard(op string, recovered func(err error)) func() {
+	return func() {
+		value := recover()
+
+		if value == nil {
+			return
+		}
+
+		p := &Panic{Op: op, Value: value, Stack: debug.Stack()}
+
+		if c.panics != nil {
+			c.panics(p)
+		}
+
+		if recovered != nil {
+			recovered(p)
+		}
+	}
+}
+
+// notify passes the error of a recovered panic to the callback, which is likely the origin of the panic,
+// so a panic raised by the callback again is ignored.
+func notify[T any](cb func(T, error), err error) {
+	defer func() {
+		recover()
+	}()
+
+	var zero T
+
+	cb(zero, err)
+}
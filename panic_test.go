@@ -0,0 +1,137 @@
+package talkative_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPanicHandler tests reporting the panics of callbacks instead of crashing.
+func TestPanicHandler(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "!"}, Done: true},
+	)
+	defer server.Close()
+
+	errBoom := errors.New("boom")
+
+	for name, opts := range map[string][]talkative.Option{
+		"direct": nil,
+		"paced":  {talkative.WithPacing(time.Millisecond)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			panics := []*talkative.Panic{}
+			events := []talkative.EventType{}
+
+			client, _ := talkative.New(server.URL, append(opts, talkative.WithPanicHandler(func(p *talkative.Panic) {
+				panics = append(panics, p)
+			}))...)
+
+			client.Subscribe(func(e talkative.Event) { events = append(events, e.Type) }, talkative.EVENT_FAILED, talkative.EVENT_COMPLETED)
+
+			done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {
+				panic(errBoom)
+			}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+			{
+				assert.NoError(t, err)
+				assert.True(t, <-done)
+			}
+
+			assert.Len(t, panics, 1)
+			assert.Equal(t, "chat", panics[0].Op)
+			assert.ErrorIs(t, panics[0], errBoom)
+			assert.Contains(t, string(panics[0].Stack), "panic")
+			assert.Equal(t, "panic in chat: boom", panics[0].Error())
+			assert.Equal(t, []talkative.EventType{talkative.EVENT_FAILED}, events)
+			assert.Zero(t, client.InFlight(talkative.DEFAULT_MODEL))
+		})
+	}
+}
+
+// TestPanicHandlerCompletion tests reporting the panics of completion callbacks of cloned clients.
+func TestPanicHandlerCompletion(t *testing.T) {
+	server := streamServer(talkative.CompletionResponse{Response: "Hello", Done: true})
+	defer server.Close()
+
+	var recovered *talkative.Panic
+
+	client, _ := talkative.New(server.URL, talkative.WithPanicHandler(func(p *talkative.Panic) {
+		recovered = p
+	}))
+
+	done, err := client.Clone().Completion("", func(cr *talkative.CompletionResponse, err error) {
+		panic("boom")
+	}, &talkative.CompletionMessage{Prompt: "Hi"})
+	{
+		assert.NoError(t, err)
+		assert.True(t, <-done)
+		assert.Equal(t, "completion", recovered.Op)
+		assert.Equal(t, "boom", recovered.Value)
+		assert.Nil(t, recovered.Unwrap())
+	}
+}
+
+// TestPanicRecovered tests passing the panics of callbacks to the callback and the done channel without a handler.
+func TestPanicRecovered(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "!"}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	var streamErr error
+
+	done, err := client.ChatDone(context.Background(), "", func(cr *talkative.ChatResponse, err error) {
+		if err != nil {
+			streamErr = err
+
+			return
+		}
+
+		panic("boom")
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+
+		doneErr := <-done
+
+		var p *talkative.Panic
+
+		assert.ErrorAs(t, streamErr, &p)
+		assert.Equal(t, "boom", p.Value)
+		assert.Equal(t, streamErr, doneErr)
+	}
+}
+
+// TestPanicRecoveredProgress tests passing the panics of progress callbacks to the callback.
+func TestPanicRecoveredProgress(t *testing.T) {
+	server := streamServer(talkative.PullProgress{Status: "success"})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	var pullErr error
+
+	done, err := client.PullModel("llama2", func(p *talkative.PullProgress, err error) {
+		if err != nil {
+			pullErr = err
+
+			return
+		}
+
+		panic("boom")
+	})
+	{
+		assert.NoError(t, err)
+		assert.True(t, <-done)
+		assert.ErrorContains(t, pullErr, "panic in pull: boom")
+	}
+}
@@ -0,0 +1,275 @@
+package talkative
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrPIIDetected is returned when a request is blocked because it contains personally identifiable information.
+var ErrPIIDetected = newError("personally identifiable information detected")
+
+// PIIMatch represents an occurrence of personally identifiable information in a text.
+type PIIMatch struct {
+	Type  string // The type of information, i.e: email or phone.
+	Value string // The matched text.
+	Start int    // Byte offset of the start of the match.
+	End   int    // Byte offset of the end of the match.
+}
+
+// Detector finds sensitive content in texts, i.e: personally identifiable information.
+type Detector interface {
+	Detect(text string) []PIIMatch
+}
+
+// RegexDetector is a Detector reporting the matches of a regular expression.
+type RegexDetector struct {
+	Type     string                  // The type reported for the matches.
+	Pattern  *regexp.Regexp          // The pattern to look for.
+	Validate func(match string) bool // Filters out false positives, i.e: Luhn check of card numbers. (Optional)
+}
+
+// Detect returns the validated matches of the pattern in the text.
+func (d *RegexDetector) Detect(text string) []PIIMatch {
+	matches := []PIIMatch{}
+
+	for _, loc := range d.Pattern.FindAllStringIndex(text, -1) {
+		value := text[loc[0]:loc[1]]
+
+		if d.Validate != nil && !d.Validate(value) {
+			continue
+		}
+
+		matches = append(matches, PIIMatch{
+			Type:  d.Type,
+			Value: value,
+			Start: loc[0],
+			End:   loc[1],
+		})
+	}
+
+	return matches
+}
+
+// DefaultDetectors returns regex based detectors for email addresses, phone numbers, credit card numbers,
+// IPv4 addresses and US social security numbers.
+func DefaultDetectors() []Detector {
+	return []Detector{
+		&RegexDetector{
+			Type:    "email",
+			Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+		},
+		&RegexDetector{
+			Type:     "credit_card",
+			Pattern:  regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`),
+			Validate: luhn,
+		},
+		&RegexDetector{
+			Type:    "ssn",
+			Pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+		},
+		&RegexDetector{
+			Type:    "phone",
+			Pattern: regexp.MustCompile(`(?:\+\d{1,3}[ .\-]?)?(?:\(\d{2,4}\)[ .\-]?)?\d{3,4}[ .\-]\d{3,4}(?:[ .\-]\d{2,4})?\b`),
+		},
+		&RegexDetector{
+			Type:    "ipv4",
+			Pattern: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`),
+		},
+	}
+}
+
+// Define an enum-like type to represent what happens to requests containing personally identifiable information.
+type PIIAction int
+
+const (
+	// The request is rejected with ErrPIIDetected.
+	PII_BLOCK PIIAction = iota
+
+	// The matches are replaced with a placeholder naming their type, i.e: [EMAIL].
+	PII_MASK
+
+	// The request is sent unchanged, the matches are only reported to the OnDetect handler.
+	PII_ANNOTATE
+)
+
+// PIIGuard scans requests for personally identifiable information before they are sent.
+//
+// Register it on a client with WithPIIGuard.
+type PIIGuard struct {
+	Detectors []Detector               // The detectors to run, defaults to DefaultDetectors().
+	Action    PIIAction                // What to do with requests containing matches.
+	OnDetect  func(matches []PIIMatch) // Invoked with the matches of every request containing some. (Optional)
+}
+
+// WithPIIGuard scans every chat and completion request with the guard before it is sent.
+func WithPIIGuard(guard *PIIGuard) Option {
+	return func(c *Client) {
+		c.chatHooks = append(c.chatHooks, guard.ChatHook())
+		c.completionHooks = append(c.completionHooks, guard.CompletionHook())
+	}
+}
+
+// Scan returns the matches of all detectors in the text, ordered by position.
+//
+// Overlapping matches are resolved in favour of the earliest detector.
+func (g *PIIGuard) Scan(text string) []PIIMatch {
+	detectors := g.Detectors
+
+	if detectors == nil {
+		detectors = DefaultDetectors()
+	}
+
+	matches := []PIIMatch{}
+
+	for _, detector := range detectors {
+		for _, match := range detector.Detect(text) {
+			overlaps := false
+
+			for _, existing := range matches {
+				if match.Start < existing.End && existing.Start < match.End {
+					overlaps = true
+
+					break
+				}
+			}
+
+			if !overlaps {
+				matches = append(matches, match)
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+
+	return matches
+}
+
+// Mask returns the text with every match replaced by a placeholder naming its type.
+func (g *PIIGuard) Mask(text string) string {
+	matches := g.Scan(text)
+
+	for i := len(matches) - 1; i >= 0; i-- {
+		match := matches[i]
+		text = text[:match.Start] + "[" + strings.ToUpper(match.Type) + "]" + text[match.End:]
+	}
+
+	return text
+}
+
+// ChatHook returns a ChatHook applying the guard to the messages of chat requests.
+func (g *PIIGuard) ChatHook() ChatHook {
+	return func(request *ChatRequest) error {
+		messages := make([]ChatMessage, len(request.Messages))
+		found := []PIIMatch{}
+
+		for i, msg := range request.Messages {
+			content, matches := g.apply(msg.Content)
+
+			messages[i] = msg
+			messages[i].Content = content
+			found = append(found, matches...)
+		}
+
+		if err := g.report(found); err != nil {
+			return err
+		}
+
+		request.Messages = messages
+
+		return nil
+	}
+}
+
+// CompletionHook returns a CompletionHook applying the guard to the prompt and system message of completion requests.
+func (g *PIIGuard) CompletionHook() CompletionHook {
+	return func(request *CompletionRequest) error {
+		prompt, found := g.apply(request.Prompt)
+
+		var system string
+
+		if request.CompletionParams != nil {
+			var matches []PIIMatch
+
+			system, matches = g.apply(request.System)
+			found = append(found, matches...)
+		}
+
+		if err := g.report(found); err != nil {
+			return err
+		}
+
+		request.Prompt = prompt
+
+		if request.CompletionParams != nil {
+			params := *request.CompletionParams
+			params.System = system
+			request.CompletionParams = &params
+		}
+
+		return nil
+	}
+}
+
+// apply scans the text and masks it when configured to.
+func (g *PIIGuard) apply(text string) (string, []PIIMatch) {
+	matches := g.Scan(text)
+
+	if g.Action == PII_MASK && len(matches) > 0 {
+		return g.Mask(text), matches
+	}
+
+	return text, matches
+}
+
+// report hands the matches to the OnDetect handler and blocks the request when configured to.
+func (g *PIIGuard) report(matches []PIIMatch) error {
+	if len(matches) == 0 {
+		return nil
+	}
+
+	if g.OnDetect != nil {
+		g.OnDetect(matches)
+	}
+
+	if g.Action != PII_BLOCK {
+		return nil
+	}
+
+	types := []string{}
+	seen := map[string]bool{}
+
+	for _, match := range matches {
+		if !seen[match.Type] {
+			seen[match.Type] = true
+			types = append(types, match.Type)
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrPIIDetected, strings.Join(types, ", "))
+}
+
+// luhn reports whether the digits of the text pass the Luhn checksum.
+func luhn(text string) bool {
+	sum, double := 0, false
+
+	for i := len(text) - 1; i >= 0; i-- {
+		if text[i] < '0' || text[i] > '9' {
+			continue
+		}
+
+		digit := int(text[i] - '0')
+
+		if double {
+			if digit *= 2; digit > 9 {
+				digit -= 9
+			}
+		}
+
+		sum += digit
+		double = !double
+	}
+
+	return sum%10 == 0
+}
@@ -0,0 +1,74 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPIIGuard tests detection and masking of personally identifiable information.
+func TestPIIGuard(t *testing.T) {
+	guard := &talkative.PIIGuard{}
+	text := "Mail john.doe@example.com or call +1 555-123-4567, card 4111 1111 1111 1111, order #42."
+
+	matches := guard.Scan(text)
+	types := []string{}
+
+	for _, match := range matches {
+		types = append(types, match.Type)
+	}
+
+	assert.Equal(t, []string{"email", "phone", "credit_card"}, types)
+	assert.Equal(t, "Mail [EMAIL] or call [PHONE], card [CREDIT_CARD], order #42.", guard.Mask(text))
+}
+
+// TestPIIGuardClient tests blocking and masking of requests sent by the client.
+func TestPIIGuardClient(t *testing.T) {
+	received := ""
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		received = request.Messages[0].Content
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Done: true})
+	}))
+	defer server.Close()
+
+	message := talkative.ChatMessage{Role: talkative.USER, Content: "My email is jane@example.com"}
+
+	t.Run("pii-block", func(t *testing.T) {
+		client, _ := talkative.New(server.URL, talkative.WithPIIGuard(&talkative.PIIGuard{Action: talkative.PII_BLOCK}))
+
+		done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+
+		assert.Nil(t, done)
+		assert.ErrorIs(t, err, talkative.ErrPIIDetected)
+		assert.Empty(t, received)
+	})
+
+	t.Run("pii-mask", func(t *testing.T) {
+		detected := 0
+		client, _ := talkative.New(server.URL, talkative.WithPIIGuard(&talkative.PIIGuard{
+			Action: talkative.PII_MASK,
+			OnDetect: func(matches []talkative.PIIMatch) {
+				detected += len(matches)
+			},
+		}))
+
+		done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, message)
+		{
+			assert.NoError(t, err)
+		}
+
+		<-done
+
+		assert.Equal(t, "My email is [EMAIL]", received)
+		assert.Equal(t, "My email is jane@example.com", message.Content)
+		assert.Equal(t, 1, detected)
+	})
+}
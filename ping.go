@@ -0,0 +1,76 @@
+package talkative
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Errors classifying the failures of Ping.
+var (
+	ErrUnreachable = newError("server is unreachable")          // The server cannot be connected to.
+	ErrNotOllama   = newError("server is not an ollama server") // The server answered, but not like Ollama does.
+)
+
+// PingError describes why a Ping failed, its Reason is either ErrUnreachable or ErrNotOllama.
+type PingError struct {
+	Reason     error  // ErrUnreachable or ErrNotOllama.
+	URL        string // The URL which was pinged.
+	StatusCode int    // The status code of the response, 0 when the server is unreachable.
+	Err        error  // The underlying error, if any.
+}
+
+// Error implements the error interface.
+func (e *PingError) Error() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("%v: %s: %v", e.Reason, e.URL, e.Err)
+	case e.StatusCode != 0:
+		return fmt.Sprintf("%v: %s: status %d", e.Reason, e.URL, e.StatusCode)
+	default:
+		return fmt.Sprintf("%v: %s", e.Reason, e.URL)
+	}
+}
+
+// Unwrap returns the reason and the underlying error, so both can be matched with errors.Is.
+func (e *PingError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Reason}
+	}
+
+	return []error{e.Reason, e.Err}
+}
+
+// Ping performs a cheap request against the base URL of the client, to which Ollama answers "Ollama is running".
+//
+// It returns a *PingError whose Reason tells apart unreachable servers from servers which are not Ollama,
+// which is useful for readiness probes before starting a chat UI.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.base, nil)
+
+	if err != nil {
+		return &PingError{Reason: ErrUnreachable, URL: c.base, Err: err}
+	}
+
+	res, err := c.roundTrip(req)
+
+	if err != nil {
+		return &PingError{Reason: ErrUnreachable, URL: c.base, Err: err}
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1024))
+
+	if err != nil {
+		return &PingError{Reason: ErrUnreachable, URL: c.base, StatusCode: res.StatusCode, Err: err}
+	}
+
+	if res.StatusCode != http.StatusOK || !strings.Contains(string(body), "Ollama is running") {
+		return &PingError{Reason: ErrNotOllama, URL: c.base, StatusCode: res.StatusCode}
+	}
+
+	return nil
+}
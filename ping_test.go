@@ -0,0 +1,48 @@
+package talkative_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPing tests classifying the servers answering a ping.
+func TestPing(t *testing.T) {
+	ollama := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/", r.URL.Path)
+
+		w.Write([]byte("Ollama is running"))
+	}))
+	defer ollama.Close()
+
+	client, _ := talkative.New(ollama.URL)
+	assert.NoError(t, client.Ping(context.Background()))
+
+	other := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>Welcome to nginx!</html>"))
+	}))
+	defer other.Close()
+
+	client, _ = talkative.New(other.URL)
+	err := client.Ping(context.Background())
+	{
+		var pingErr *talkative.PingError
+
+		assert.ErrorIs(t, err, talkative.ErrNotOllama)
+		assert.True(t, errors.As(err, &pingErr))
+		assert.Equal(t, http.StatusOK, pingErr.StatusCode)
+	}
+
+	other.Close()
+
+	err = client.Ping(context.Background())
+	{
+		assert.ErrorIs(t, err, talkative.ErrUnreachable)
+		assert.NotErrorIs(t, err, talkative.ErrNotOllama)
+	}
+}
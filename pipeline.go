@@ -0,0 +1,215 @@
+package talkative
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrPipeline is returned when a pipeline is misconfigured or one of its steps fails.
+var ErrPipeline = newError("pipeline failed")
+
+// Step is a single prompt of a Pipeline.
+type Step struct {
+	Name   string      // The name of the step, used to reference its output from later steps.
+	Model  string      // The model of the step, defaults to the default model of the client.
+	Params *ChatParams // The chat parameters of the step. (Optional)
+	System string      // The system prompt of the step. (Optional)
+
+	// Prompt is a text/template rendered with the PipelineData of the step, i.e:
+	// "Summarize the following outline: {{.Previous}}" or "{{index .Outputs \"outline\"}}".
+	Prompt string
+}
+
+// PipelineData is the data the prompt templates are rendered with.
+type PipelineData struct {
+	Input    string            // The input given to Pipeline.Run.
+	Previous string            // The output of the previous stage, the outputs of parallel steps are joined with blank lines.
+	Outputs  map[string]string // The outputs of the steps completed so far, by step name.
+}
+
+// StepTrace records the execution of a step.
+type StepTrace struct {
+	Name     string        // The name of the step.
+	Model    string        // The model the step was sent to.
+	Prompt   string        // The rendered prompt.
+	Response string        // The complete response.
+	Start    time.Time     // The time the step started.
+	Elapsed  time.Duration // The duration of the step.
+	Err      error         // The error of the step, if any.
+}
+
+// PipelineResult is the result of a pipeline run.
+type PipelineResult struct {
+	Output  string            // The output of the last stage.
+	Outputs map[string]string // The outputs of every step, by step name.
+	Trace   []StepTrace       // The trace of every step in the order they completed.
+}
+
+// stage is a unit of execution of a pipeline, either a single step, parallel steps or a branch.
+type stage func(p *Pipeline, data *PipelineData, result *PipelineResult) error
+
+// Pipeline composes multi-step workflows where the output of a step is templated into the next ones.
+//
+// Stages run in the order they are added. Parallel steps run concurrently and branches select the
+// sub pipeline to run from the outputs produced so far. A Pipeline can be run several times.
+type Pipeline struct {
+	client *Client
+	stages []stage
+	mu     sync.Mutex // Guards the result while parallel steps are running.
+}
+
+// NewPipeline creates a new empty pipeline running against the client.
+func NewPipeline(client *Client) *Pipeline {
+	return &Pipeline{client: client}
+}
+
+// Step appends a step and returns the pipeline for chaining.
+func (p *Pipeline) Step(step Step) *Pipeline {
+	p.stages = append(p.stages, func(p *Pipeline, data *PipelineData, result *PipelineResult) error {
+		output, err := p.run(step, data, result)
+
+		if err != nil {
+			return err
+		}
+
+		data.Previous = output
+
+		return nil
+	})
+
+	return p
+}
+
+// Parallel appends steps running concurrently and returns the pipeline for chaining.
+//
+// The steps see the same data and cannot reference each other. Once they all complete, their
+// outputs are joined with blank lines, in the given order, as the Previous output of the next stage.
+func (p *Pipeline) Parallel(steps ...Step) *Pipeline {
+	p.stages = append(p.stages, func(p *Pipeline, data *PipelineData, result *PipelineResult) error {
+		outputs := make([]string, len(steps))
+		errs := make([]error, len(steps))
+		snapshot := *data
+
+		var wg sync.WaitGroup
+
+		for i, step := range steps {
+			wg.Add(1)
+
+			go func(i int, step Step) {
+				defer wg.Done()
+
+				outputs[i], errs[i] = p.run(step, &snapshot, result)
+			}(i, step)
+		}
+
+		wg.Wait()
+
+		if err := errors.Join(errs...); err != nil {
+			return err
+		}
+
+		data.Previous = strings.Join(outputs, "\n\n")
+
+		return nil
+	})
+
+	return p
+}
+
+// Branch appends a stage running the branch selected by choose and returns the pipeline for chaining.
+//
+// The output of the selected branch is recorded under name and becomes the Previous output of the next stage.
+// Choosing a branch which does not exist fails the pipeline.
+func (p *Pipeline) Branch(name string, choose func(data PipelineData) string, branches map[string]*Pipeline) *Pipeline {
+	p.stages = append(p.stages, func(p *Pipeline, data *PipelineData, result *PipelineResult) error {
+		key := choose(*data)
+		branch, ok := branches[key]
+
+		if !ok {
+			return fmt.Errorf("%w: branch %q of %q does not exist", ErrPipeline, key, name)
+		}
+
+		for _, stage := range branch.stages {
+			if err := stage(p, data, result); err != nil {
+				return err
+			}
+		}
+
+		p.mu.Lock()
+		result.Outputs[name] = data.Previous
+		p.mu.Unlock()
+
+		return nil
+	})
+
+	return p
+}
+
+// Run executes the pipeline with the given input.
+//
+// The result is returned even when a step fails, so the trace of the completed steps can be inspected.
+func (p *Pipeline) Run(input string) (*PipelineResult, error) {
+	result := &PipelineResult{Outputs: map[string]string{}}
+	data := &PipelineData{Input: input, Previous: input, Outputs: result.Outputs}
+
+	for _, stage := range p.stages {
+		if err := stage(p, data, result); err != nil {
+			return result, err
+		}
+	}
+
+	result.Output = data.Previous
+
+	return result, nil
+}
+
+// run renders the prompt of the step, sends it and records its trace and output.
+func (p *Pipeline) run(step Step, data *PipelineData, result *PipelineResult) (string, error) {
+	trace := StepTrace{Name: step.Name, Model: step.Model, Start: time.Now()}
+
+	if trace.Model == "" {
+		trace.Model = p.client.DefaultModel()
+	}
+
+	p.mu.Lock()
+	prompt, err := DefaultTemplates.Render(step.Prompt, *data)
+	p.mu.Unlock()
+
+	if err == nil {
+		trace.Prompt = prompt
+
+		msgs := []ChatMessage{}
+
+		if step.System != "" {
+			msgs = append(msgs, ChatMessage{Role: SYSTEM, Content: step.System})
+		}
+
+		msgs = append(msgs, ChatMessage{Role: USER, Content: prompt})
+		trace.Response, err = p.client.chatText(trace.Model, step.Params, msgs...)
+	}
+
+	trace.Elapsed = time.Since(trace.Start)
+
+	if err != nil {
+		err = fmt.Errorf("%w: step %q: %w", ErrPipeline, step.Name, err)
+		trace.Err = err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result.Trace = append(result.Trace, trace)
+
+	if err != nil {
+		return "", err
+	}
+
+	if step.Name != "" {
+		result.Outputs[step.Name] = trace.Response
+	}
+
+	return trace.Response, nil
+}
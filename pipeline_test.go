@@ -0,0 +1,73 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPipeline tests chaining, parallel steps and branching of a pipeline.
+func TestPipeline(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		prompt := request.Messages[len(request.Messages)-1].Content
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: request.Model + "(" + prompt + ")"}, Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	pipeline := talkative.NewPipeline(client).
+		Step(talkative.Step{Name: "outline", Model: "a", Prompt: "outline {{.Input}}"}).
+		Parallel(
+			talkative.Step{Name: "intro", Model: "b", Prompt: "intro {{.Previous}}"},
+			talkative.Step{Name: "body", Model: "c", Prompt: "body {{.Previous}}"},
+		).
+		Branch("review", func(data talkative.PipelineData) string {
+			if strings.Contains(data.Previous, "b(") {
+				return "long"
+			}
+
+			return "short"
+		}, map[string]*talkative.Pipeline{
+			"long":  talkative.NewPipeline(client).Step(talkative.Step{Model: "d", Prompt: "shorten {{index .Outputs \"body\"}}"}),
+			"short": talkative.NewPipeline(client),
+		})
+
+	result, err := pipeline.Run("go")
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "a(outline go)", result.Outputs["outline"])
+		assert.Equal(t, "b(intro a(outline go))", result.Outputs["intro"])
+		assert.Equal(t, "d(shorten c(body a(outline go)))", result.Output)
+		assert.Equal(t, result.Output, result.Outputs["review"])
+		assert.Len(t, result.Trace, 4)
+	}
+}
+
+// TestPipelineFailure tests the trace being returned when a step fails.
+func TestPipelineFailure(t *testing.T) {
+	server := streamServer(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "ok"}, Done: true})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	result, err := talkative.NewPipeline(client).
+		Step(talkative.Step{Name: "first", Prompt: "{{.Input}}"}).
+		Step(talkative.Step{Name: "second", Prompt: "{{.Missing}}"}).
+		Run("hi")
+	{
+		assert.ErrorIs(t, err, talkative.ErrPipeline)
+		assert.Len(t, result.Trace, 2)
+		assert.NoError(t, result.Trace[0].Err)
+		assert.Error(t, result.Trace[1].Err)
+	}
+}
@@ -0,0 +1,142 @@
+package talkative
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// PromptPrefix configures WithPromptPrefix.
+type PromptPrefix struct {
+	// Messages is the stable prefix of every chat, typically a system message followed by few-shot examples.
+	// When empty, the leading system messages of the requests are tracked as their prefix. (Optional)
+	Messages []ChatMessage
+
+	// OnInvalidate is invoked when the prefix of a request differs from the previous request of the same
+	// model, which invalidates the prompt cache of the server. (Optional)
+	OnInvalidate func(invalidation PrefixInvalidation)
+}
+
+// PrefixInvalidation reports a change of the prompt prefix of a model.
+type PrefixInvalidation struct {
+	Model    string // The model of the request.
+	Previous string // The fingerprint of the prefix of the previous request.
+	Current  string // The fingerprint of the prefix of the request.
+}
+
+// PrefixStats reports how stable the prompt prefixes of the chats of a client are.
+type PrefixStats struct {
+	Requests      int64 // The number of chat requests tracked.
+	Invalidations int64 // The number of requests whose prefix differed from the previous request of their model.
+}
+
+// promptPrefix keeps the prefix of the chats of a client stable and tracks its changes.
+type promptPrefix struct {
+	config PromptPrefix
+
+	mu           sync.Mutex
+	fingerprints map[string]string // The fingerprint of the prefix of the last request per model.
+
+	requests      atomic.Int64
+	invalidations atomic.Int64
+}
+
+// WithPromptPrefix keeps the prefix of every chat byte-identical across requests, maximizing the hits of
+// the prompt cache of Ollama, which only reuses the part of the prompt identical to the previous request.
+//
+// The configured messages are prepended to every chat which doesn't already start with them, before the
+// chat hooks run. Once the hooks ran, the prefix of the request is compared to the previous request of the
+// same model: a change, i.e: a hook injecting the current time into the system prompt, is counted and
+// reported to PromptPrefix.OnInvalidate.
+func WithPromptPrefix(prefix PromptPrefix) Option {
+	return func(c *Client) {
+		prefix.Messages = slices.Clone(prefix.Messages)
+		c.prefix = &promptPrefix{config: prefix}
+	}
+}
+
+// PrefixStats returns the statistics of the prompt prefix, which are empty unless WithPromptPrefix is used.
+func (c *Client) PrefixStats() PrefixStats {
+	if c.prefix == nil {
+		return PrefixStats{}
+	}
+
+	return PrefixStats{
+		Requests:      c.prefix.requests.Load(),
+		Invalidations: c.prefix.invalidations.Load(),
+	}
+}
+
+// apply prepends the prefix to the request unless it already starts with it.
+func (p *promptPrefix) apply(request *ChatRequest) {
+	n := len(p.config.Messages)
+
+	if n == 0 || (len(request.Messages) >= n && slices.EqualFunc(request.Messages[:n], p.config.Messages, sameMessage)) {
+		return
+	}
+
+	request.Messages = append(slices.Clone(p.config.Messages), request.Messages...)
+}
+
+// observe compares the prefix of the request to the previous request of its model.
+func (p *promptPrefix) observe(request *ChatRequest) {
+	n := len(p.config.Messages)
+
+	if n == 0 {
+		for n < len(request.Messages) && request.Messages[n].Role == SYSTEM {
+			n++
+		}
+	}
+
+	current := fingerprint(request.Messages[:min(n, len(request.Messages))])
+
+	p.requests.Add(1)
+	p.mu.Lock()
+
+	if p.fingerprints == nil {
+		p.fingerprints = map[string]string{}
+	}
+
+	previous, ok := p.fingerprints[request.Model]
+	p.fingerprints[request.Model] = current
+
+	p.mu.Unlock()
+
+	if !ok || previous == current {
+		return
+	}
+
+	p.invalidations.Add(1)
+
+	if p.config.OnInvalidate != nil {
+		p.config.OnInvalidate(PrefixInvalidation{Model: request.Model, Previous: previous, Current: current})
+	}
+}
+
+// sameMessage reports whether both messages are sent identically.
+func sameMessage(a, b ChatMessage) bool {
+	return a.Role == b.Role && a.Content == b.Content && slices.Equal(a.Images, b.Images)
+}
+
+// fingerprint returns the SHA-256 digest of the messages.
+func fingerprint(msgs []ChatMessage) string {
+	h := sha256.New()
+
+	for _, msg := range msgs {
+		h.Write([]byte(msg.Role))
+		h.Write([]byte{0})
+		h.Write([]byte(msg.Content))
+		h.Write([]byte{0})
+
+		for _, image := range msg.Images {
+			h.Write([]byte(image))
+			h.Write([]byte{0})
+		}
+
+		h.Write([]byte{1})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,97 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPromptPrefix tests keeping the prefix of chats stable and reporting the changes made by hooks.
+func TestPromptPrefix(t *testing.T) {
+	requests := make(chan talkative.ChatRequest, 1)
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		requests <- request
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Done: true})
+	}))
+	defer server.Close()
+
+	prefix := []talkative.ChatMessage{
+		{Role: talkative.SYSTEM, Content: "Answer with a single word."},
+		{Role: talkative.USER, Content: "Color of the sky?"},
+		{Role: talkative.ASSISTANT, Content: "Blue"},
+	}
+
+	invalidations := []talkative.PrefixInvalidation{}
+	suffix := ""
+
+	client, _ := talkative.New(server.URL,
+		talkative.WithPromptPrefix(talkative.PromptPrefix{
+			Messages:     prefix,
+			OnInvalidate: func(invalidation talkative.PrefixInvalidation) { invalidations = append(invalidations, invalidation) },
+		}),
+		talkative.WithChatHook(func(request *talkative.ChatRequest) error {
+			request.Messages[0].Content += suffix
+
+			return nil
+		}),
+	)
+
+	cb := func(cr *talkative.ChatResponse, err error) {}
+	user := talkative.ChatMessage{Role: talkative.USER, Content: "Color of grass?"}
+
+	done, _ := client.Chat("", cb, nil, user)
+	<-done
+
+	request := <-requests
+	assert.Equal(t, append(prefix, user), request.Messages)
+
+	// Requests already starting with the prefix are left as is.
+	done, _ = client.Chat("", cb, nil, append(prefix, user)...)
+	<-done
+
+	request = <-requests
+	assert.Equal(t, append(prefix, user), request.Messages)
+	assert.Empty(t, invalidations)
+
+	suffix = " Today is monday."
+	done, _ = client.Chat("", cb, nil, user)
+	<-done
+
+	<-requests
+	assert.Len(t, invalidations, 1)
+	assert.Equal(t, talkative.DEFAULT_MODEL, invalidations[0].Model)
+	assert.NotEqual(t, invalidations[0].Previous, invalidations[0].Current)
+	assert.Equal(t, talkative.PrefixStats{Requests: 3, Invalidations: 1}, client.PrefixStats())
+	assert.Equal(t, "Answer with a single word.", prefix[0].Content)
+
+	assert.Equal(t, talkative.PrefixStats{}, client.Clone().PrefixStats())
+}
+
+// TestPromptPrefixSystem tests tracking the leading system messages when no prefix is configured.
+func TestPromptPrefixSystem(t *testing.T) {
+	server := streamServer(talkative.ChatResponse{Done: true})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithPromptPrefix(talkative.PromptPrefix{}))
+	cb := func(cr *talkative.ChatResponse, err error) {}
+
+	for _, system := range []string{"Be brief.", "Be brief.", "Be polite."} {
+		done, _ := client.Chat("", cb, nil,
+			talkative.ChatMessage{Role: talkative.SYSTEM, Content: system},
+			talkative.ChatMessage{Role: talkative.USER, Content: "Hi " + system},
+		)
+		<-done
+	}
+
+	assert.Equal(t, talkative.PrefixStats{Requests: 3, Invalidations: 1}, client.PrefixStats())
+	assert.Equal(t, talkative.PrefixStats{}, (&talkative.Client{}).PrefixStats())
+}
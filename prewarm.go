@@ -0,0 +1,191 @@
+package talkative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DEFAULT_PREWARM_CONNECTIONS is the default number of connections established per endpoint by WithPrewarm.
+const DEFAULT_PREWARM_CONNECTIONS = 2
+
+// ErrPrewarmConnections is returned by Client.Prewarm for a negative number of connections.
+var ErrPrewarmConnections = newError("number of connections to prewarm cannot be negative")
+
+// Prewarm configures WithPrewarm.
+type Prewarm struct {
+	Connections int           // The number of connections per endpoint, defaults to DEFAULT_PREWARM_CONNECTIONS.
+	Interval    time.Duration // How often the connections are refreshed to keep them warm, 0 only warms them once.
+
+	// Context stops refreshing the connections once done, defaults to context.Background(). (Optional)
+	Context context.Context
+}
+
+// WithPrewarm establishes the TCP and TLS connections to the endpoints of the client in the background when
+// the client is created, so the first requests of latency-sensitive interactive apps don't pay for them.
+//
+// When Interval is set, the connections are refreshed every interval until the context is done, so neither
+// the client nor the server closes them for being idle. The idle connection pool of the transport is grown
+// to hold the connections, unless the client has a custom http.RoundTripper.
+func WithPrewarm(prewarm Prewarm) Option {
+	return func(c *Client) {
+		if prewarm.Connections <= 0 {
+			prewarm.Connections = DEFAULT_PREWARM_CONNECTIONS
+		}
+
+		if prewarm.Context == nil {
+			prewarm.Context = context.Background()
+		}
+
+		c.prewarm = &prewarm
+	}
+}
+
+// Prewarm establishes the given number of connections to every endpoint of the client and returns them to the
+// idle connection pool of the transport, waiting for them to be established.
+//
+// Endpoints are told apart by their scheme and host, the base URL of the client being one of them.
+// It returns ErrPrewarmConnections when the number of connections is negative.
+func (c *Client) Prewarm(ctx context.Context, connections int) error {
+	if connections < 0 {
+		return fmt.Errorf("%w: %d", ErrPrewarmConnections, connections)
+	}
+
+	if connections == 0 {
+		return nil
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	for _, origin := range c.origins() {
+		// The responses are held until all of them arrived, so they can't share a connection.
+		established := &sync.WaitGroup{}
+		established.Add(connections)
+
+		for i := 0; i < connections; i++ {
+			wg.Add(1)
+
+			go func(origin string) {
+				defer wg.Done()
+
+				if err := c.warm(ctx, origin, established); err != nil {
+					mu.Lock()
+					errs = append(errs, wrapError("prewarm", origin, "", "", err))
+					mu.Unlock()
+				}
+			}(origin)
+		}
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// CloseIdleConnections closes the idle connections of the HTTP client of the client, i.e: before measuring
+// the resources of the process. Connections in use are not interrupted.
+func (c *Client) CloseIdleConnections() {
+	c.client.CloseIdleConnections()
+}
+
+// keepWarm warms the connections of the client and refreshes them every interval, see WithPrewarm.
+func (c *Client) keepWarm(prewarm *Prewarm) {
+	if transport, ok := c.client.Transport.(*http.Transport); c.client.Transport == nil || ok {
+		limit := http.DefaultMaxIdleConnsPerHost
+
+		if ok && transport.MaxIdleConnsPerHost > 0 {
+			limit = transport.MaxIdleConnsPerHost
+		}
+
+		if limit < prewarm.Connections {
+			c.transport(func(t *http.Transport) {
+				t.MaxIdleConnsPerHost = prewarm.Connections
+			})
+		}
+	}
+
+	go func() {
+		defer c.guard("prewarm", nil)()
+
+		c.Prewarm(prewarm.Context, prewarm.Connections)
+
+		if prewarm.Interval <= 0 {
+			return
+		}
+
+		ticker := time.NewTicker(prewarm.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-prewarm.Context.Done():
+				return
+			case <-ticker.C:
+				c.Prewarm(prewarm.Context, prewarm.Connections)
+			}
+		}
+	}()
+}
+
+// warm sends a cheap request to the origin and waits for the other connections to be established before
+// draining the response, so its connection returns to the idle pool.
+func (c *Client) warm(ctx context.Context, origin string, established *sync.WaitGroup) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin, nil)
+
+	if err != nil {
+		established.Done()
+
+		return err
+	}
+
+	res, err := c.roundTrip(req)
+	established.Done()
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	established.Wait()
+
+	_, err = io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+
+	return err
+}
+
+// origins returns the distinct scheme and host of the endpoints of the client, starting with its base URL.
+func (c *Client) origins() []string {
+	origins := []string{}
+	seen := map[string]bool{}
+
+	add := func(endpoint string) {
+		u, err := url.Parse(endpoint)
+
+		if err != nil || u.Host == "" {
+			return
+		}
+
+		if origin := u.Scheme + "://" + u.Host; !seen[origin] {
+			seen[origin] = true
+			origins = append(origins, origin)
+		}
+	}
+
+	add(c.base)
+
+	for _, endpoint := range c.urls {
+		add(endpoint)
+	}
+
+	return origins
+}
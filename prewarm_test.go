@@ -0,0 +1,75 @@
+package talkative_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithPrewarm tests establishing the connections to the server when the client is created.
+func TestWithPrewarm(t *testing.T) {
+	var connections, requests atomic.Int32
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("Ollama is running"))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	talkative.New(server.URL, talkative.WithPrewarm(talkative.Prewarm{Connections: 3, Interval: 20 * time.Millisecond, Context: ctx}))
+
+	assert.Eventually(t, func() bool {
+		return requests.Load() >= 6
+	}, time.Second, time.Millisecond, "connections are refreshed every interval")
+
+	cancel()
+	assert.Equal(t, int32(3), connections.Load())
+
+	// The warm connections are reused by the requests.
+	connections.Store(0)
+
+	client, _ := talkative.New(server.URL)
+
+	assert.NoError(t, client.Prewarm(context.Background(), 2))
+	assert.NoError(t, client.Ping(context.Background()))
+	assert.Equal(t, int32(2), connections.Load())
+}
+
+// TestPrewarm tests reporting the endpoints which cannot be warmed.
+func TestPrewarm(t *testing.T) {
+	client, _ := talkative.New("http://127.0.0.1:1")
+
+	err := client.Prewarm(context.Background(), 2)
+	{
+		var e *talkative.Error
+
+		assert.ErrorAs(t, err, &e)
+		assert.Equal(t, "prewarm", e.Op)
+		assert.Equal(t, "http://127.0.0.1:1", e.Endpoint)
+	}
+}
+
+// TestPrewarmConnections tests prewarming no connection and rejecting a negative number of connections.
+func TestPrewarmConnections(t *testing.T) {
+	client, _ := talkative.New("http://127.0.0.1:1")
+
+	assert.NoError(t, client.Prewarm(context.Background(), 0))
+	assert.ErrorIs(t, client.Prewarm(context.Background(), -1), talkative.ErrPrewarmConnections)
+}
@@ -0,0 +1,161 @@
+package talkative
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ProbeOptions configures the liveness and readiness handlers of a client.
+type ProbeOptions struct {
+	CacheTTL time.Duration // How long a probe result is served before the backend is checked again, defaults to 10 seconds.
+	Timeout  time.Duration // Timeout of a single check of the backend, defaults to 5 seconds.
+	Model    string        // The model used by the readiness self test, defaults to the default model of the client.
+}
+
+// probeCheck is a single check of a probe response.
+type probeCheck struct {
+	Name    string `json:"name"`
+	Detail  string `json:"detail,omitempty"`
+	Elapsed int64  `json:"elapsed_ms"`
+	Error   string `json:"error,omitempty"`
+}
+
+// probeResponse is the JSON body served by the probe handlers.
+type probeResponse struct {
+	Status string       `json:"status"` // "ok" or "fail".
+	Checks []probeCheck `json:"checks,omitempty"`
+}
+
+// probeCache serves the cached result of a probe, running it at most once per TTL.
+type probeCache struct {
+	ttl     time.Duration
+	timeout time.Duration
+	run     func(ctx context.Context) probeResponse
+
+	mu       sync.Mutex
+	cachedAt time.Time
+	cached   probeResponse
+}
+
+// ServeHTTP serves the cached result, refreshing it when expired.
+//
+// Concurrent requests wait for a single refresh rather than all hitting the backend.
+func (p *probeCache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+
+	if p.cachedAt.IsZero() || time.Since(p.cachedAt) >= p.ttl {
+		ctx, cancel := context.WithTimeout(r.Context(), p.timeout)
+		p.cached = p.run(ctx)
+		p.cachedAt = time.Now()
+		cancel()
+	}
+
+	response := p.cached
+
+	p.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if response.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// newProbeCache creates a new probe cache with the defaults of the options applied.
+func newProbeCache(opts ProbeOptions, run func(ctx context.Context) probeResponse) *probeCache {
+	if opts.CacheTTL <= 0 {
+		opts.CacheTTL = 10 * time.Second
+	}
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+
+	return &probeCache{ttl: opts.CacheTTL, timeout: opts.Timeout, run: run}
+}
+
+// LivenessHandler returns a handler for a liveness probe, i.e: mounted as /healthz.
+//
+// It answers 200 OK when the backend answers a Ping and 503 Service Unavailable otherwise.
+// Results are cached for opts.CacheTTL to avoid hammering the backend.
+func (c *Client) LivenessHandler(opts ProbeOptions) http.Handler {
+	return newProbeCache(opts, func(ctx context.Context) probeResponse {
+		start := time.Now()
+		check := probeCheck{Name: "connectivity", Detail: c.base}
+
+		err := c.Ping(ctx)
+		check.Elapsed = time.Since(start).Milliseconds()
+
+		if err != nil {
+			check.Error = err.Error()
+
+			return probeResponse{Status: "fail", Checks: []probeCheck{check}}
+		}
+
+		return probeResponse{Status: "ok", Checks: []probeCheck{check}}
+	})
+}
+
+// ReadinessHandler returns a handler for a readiness probe, i.e: mounted as /readyz.
+//
+// It runs SelfTest against opts.Model and answers 200 OK when every check passed and 503 Service
+// Unavailable otherwise, with the report as JSON. Results are cached for opts.CacheTTL.
+func (c *Client) ReadinessHandler(opts ProbeOptions) http.Handler {
+	return newProbeCache(opts, func(ctx context.Context) probeResponse {
+		report := c.SelfTest(ctx, opts.Model)
+		response := probeResponse{Status: "ok"}
+
+		if !report.OK() {
+			response.Status = "fail"
+		}
+
+		for _, check := range report.Checks {
+			item := probeCheck{Name: check.Name, Detail: check.Detail, Elapsed: check.Elapsed.Milliseconds()}
+
+			if check.Err != nil {
+				item.Error = check.Err.Error()
+			}
+
+			response.Checks = append(response.Checks, item)
+		}
+
+		return response
+	})
+}
+
+// ReadinessHandler returns a handler for a readiness probe answering from the state of the monitor,
+// without contacting the endpoints itself.
+//
+// It answers 200 OK when at least one endpoint is ready and 503 Service Unavailable otherwise.
+func (m *HealthMonitor) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := probeResponse{Status: "fail"}
+
+		for _, endpoint := range m.endpoints {
+			check := probeCheck{Name: endpoint, Detail: m.State(endpoint).String()}
+
+			if err := m.LastError(endpoint); err != nil {
+				check.Error = err.Error()
+			}
+
+			if m.Ready(endpoint) {
+				response.Status = "ok"
+			}
+
+			response.Checks = append(response.Checks, check)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if response.Status != "ok" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		json.NewEncoder(w).Encode(response)
+	})
+}
@@ -0,0 +1,109 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLivenessHandler tests caching the result of the liveness probe.
+func TestLivenessHandler(t *testing.T) {
+	var pings atomic.Int32
+
+	healthy := atomic.Bool{}
+	healthy.Store(true)
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pings.Add(1)
+
+		if healthy.Load() {
+			w.Write([]byte("Ollama is running"))
+		}
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	handler := client.LivenessHandler(talkative.ProbeOptions{CacheTTL: 50 * time.Millisecond})
+
+	for i := 0; i < 3; i++ {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+
+	assert.Equal(t, int32(1), pings.Load())
+
+	healthy.Store(false)
+	time.Sleep(60 * time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, int32(2), pings.Load())
+}
+
+// TestReadinessHandler tests serving the self test report.
+func TestReadinessHandler(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte("Ollama is running"))
+		case "/api/tags":
+			w.Write([]byte(`{"models": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	recorder := httptest.NewRecorder()
+
+	client.ReadinessHandler(talkative.ProbeOptions{}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var body struct {
+		Status string `json:"status"`
+		Checks []struct {
+			Name  string `json:"name"`
+			Error string `json:"error"`
+		} `json:"checks"`
+	}
+
+	json.NewDecoder(recorder.Body).Decode(&body)
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "fail", body.Status)
+	assert.Len(t, body.Checks, 2)
+	assert.Equal(t, "models", body.Checks[1].Name)
+	assert.Contains(t, body.Checks[1].Error, "llama2")
+}
+
+// TestHealthMonitorReadinessHandler tests answering readiness from the monitor state.
+func TestHealthMonitorReadinessHandler(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version":"0.3.0"}`))
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	monitor := client.HealthMonitor(talkative.HealthOptions{})
+	handler := monitor.ReadinessHandler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	monitor.Check(context.Background())
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
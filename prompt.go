@@ -0,0 +1,239 @@
+package talkative
+
+import (
+	"sort"
+	"strings"
+)
+
+// Define an enum-like type to represent the kind of a prompt part.
+type PartKind string
+
+const (
+	// System instructions.
+	PART_SYSTEM PartKind = "system"
+
+	// Retrieved documents provided as context.
+	PART_DOCUMENT PartKind = "document"
+
+	// Few-shot examples, rendered with the role of the part.
+	PART_EXAMPLE PartKind = "example"
+
+	// Previous turns of the conversation, rendered with the role of the part.
+	PART_HISTORY PartKind = "history"
+
+	// The input of the user.
+	PART_USER PartKind = "user"
+)
+
+// PromptPart is a labeled part of a Prompt which retains the provenance of its content.
+type PromptPart struct {
+	Kind     PartKind          // The kind of the part.
+	Label    string            // A label identifying the part, i.e: "policy" or "doc-3".
+	Source   string            // The origin of the content, i.e: a document URL or a database key.
+	Content  string            // The content of the part.
+	Role     Role              // The role of example and history parts, defaults to USER.
+	Priority int               // Parts with lower priority are dropped first when trimming.
+	Metadata map[string]string // Arbitrary metadata for auditing purposes.
+}
+
+// Prompt is a structured prompt composed of labeled parts.
+//
+// It renders to chat messages but keeps track of where every piece of content came from,
+// so auditing, redaction and token budget trimming can act on individual parts.
+type Prompt struct {
+	Parts []PromptPart
+}
+
+// NewPrompt creates a new prompt from the given parts.
+func NewPrompt(parts ...PromptPart) *Prompt {
+	return &Prompt{Parts: parts}
+}
+
+// Add appends the part to the prompt and returns the prompt for chaining.
+func (p *Prompt) Add(part PromptPart) *Prompt {
+	p.Parts = append(p.Parts, part)
+
+	return p
+}
+
+// System appends a system part.
+func (p *Prompt) System(label, content string) *Prompt {
+	return p.Add(PromptPart{Kind: PART_SYSTEM, Label: label, Content: content})
+}
+
+// Document appends a retrieved document part along with its source.
+func (p *Prompt) Document(label, source, content string) *Prompt {
+	return p.Add(PromptPart{Kind: PART_DOCUMENT, Label: label, Source: source, Content: content})
+}
+
+// Example appends a few-shot example as a pair of user and assistant parts.
+func (p *Prompt) Example(label, input, output string) *Prompt {
+	p.Add(PromptPart{Kind: PART_EXAMPLE, Label: label, Role: USER, Content: input})
+
+	return p.Add(PromptPart{Kind: PART_EXAMPLE, Label: label, Role: ASSISTANT, Content: output})
+}
+
+// User appends the user input part.
+func (p *Prompt) User(content string) *Prompt {
+	return p.Add(PromptPart{Kind: PART_USER, Label: "input", Content: content})
+}
+
+// Map returns a new prompt with fn applied to every part, i.e: to redact sensitive content.
+//
+// Parts for which fn returns false are dropped.
+func (p *Prompt) Map(fn func(part PromptPart) (PromptPart, bool)) *Prompt {
+	mapped := &Prompt{}
+
+	for _, part := range p.Parts {
+		if part, ok := fn(part); ok {
+			mapped.Parts = append(mapped.Parts, part)
+		}
+	}
+
+	return mapped
+}
+
+// Filter returns the parts of the given kinds.
+func (p *Prompt) Filter(kinds ...PartKind) []PromptPart {
+	parts := []PromptPart{}
+
+	for _, part := range p.Parts {
+		for _, kind := range kinds {
+			if part.Kind == kind {
+				parts = append(parts, part)
+
+				break
+			}
+		}
+	}
+
+	return parts
+}
+
+// Tokens returns the estimated number of tokens of the prompt, using EstimateTokens.
+func (p *Prompt) Tokens() int {
+	tokens := 0
+
+	for _, part := range p.Parts {
+		tokens += EstimateTokens(part.render())
+	}
+
+	return tokens
+}
+
+// Trim returns a new prompt fitting within the token budget along with the dropped parts.
+//
+// Parts are dropped by ascending priority, and among parts of the same priority the earliest
+// ones first, so older documents and history go before newer ones. System and user parts are
+// never dropped, the returned prompt may therefore still exceed the budget.
+func (p *Prompt) Trim(budget int) (*Prompt, []PromptPart) {
+	tokens := p.Tokens()
+
+	if tokens <= budget {
+		return &Prompt{Parts: append([]PromptPart{}, p.Parts...)}, nil
+	}
+
+	candidates := []int{}
+
+	for i, part := range p.Parts {
+		if part.Kind != PART_SYSTEM && part.Kind != PART_USER {
+			candidates = append(candidates, i)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return p.Parts[candidates[i]].Priority < p.Parts[candidates[j]].Priority
+	})
+
+	dropped := map[int]bool{}
+
+	for _, i := range candidates {
+		if tokens <= budget {
+			break
+		}
+
+		dropped[i] = true
+		tokens -= EstimateTokens(p.Parts[i].render())
+	}
+
+	trimmed := &Prompt{}
+	removed := []PromptPart{}
+
+	for i, part := range p.Parts {
+		if dropped[i] {
+			removed = append(removed, part)
+		} else {
+			trimmed.Parts = append(trimmed.Parts, part)
+		}
+	}
+
+	return trimmed, removed
+}
+
+// Messages renders the prompt to chat messages, one message per part in order.
+func (p *Prompt) Messages() []ChatMessage {
+	messages, _ := p.Render()
+
+	return messages
+}
+
+// Render renders the prompt to chat messages, one message per part in order, along with the part
+// every message originates from.
+//
+// System and document parts are rendered as system messages, documents being prefixed with their
+// label and source. Example and history parts use their role and user parts the USER role.
+func (p *Prompt) Render() ([]ChatMessage, []PromptPart) {
+	messages := make([]ChatMessage, 0, len(p.Parts))
+	provenance := make([]PromptPart, 0, len(p.Parts))
+
+	for _, part := range p.Parts {
+		messages = append(messages, ChatMessage{
+			Role:    part.role(),
+			Content: part.render(),
+		})
+		provenance = append(provenance, part)
+	}
+
+	return messages, provenance
+}
+
+// role returns the role of the message the part renders to.
+func (part PromptPart) role() Role {
+	switch part.Kind {
+	case PART_SYSTEM, PART_DOCUMENT:
+		return SYSTEM
+	case PART_EXAMPLE, PART_HISTORY:
+		if part.Role != "" {
+			return part.Role
+		}
+	}
+
+	return USER
+}
+
+// render returns the content of the message the part renders to.
+func (part PromptPart) render() string {
+	if part.Kind != PART_DOCUMENT {
+		return part.Content
+	}
+
+	header := []string{"Document"}
+
+	if part.Label != "" {
+		header = append(header, part.Label)
+	}
+
+	if part.Source != "" {
+		header = append(header, "("+part.Source+")")
+	}
+
+	return strings.Join(header, " ") + ":\n" + part.Content
+}
+
+// EstimateTokens returns a rough estimation of the number of tokens of the text.
+//
+// It assumes about four characters per token, which is accurate enough for budgeting purposes
+// with most models but should not be relied upon for exact accounting.
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
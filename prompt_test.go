@@ -0,0 +1,59 @@
+package talkative_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPrompt tests rendering, redaction and trimming of structured prompts.
+func TestPrompt(t *testing.T) {
+	prompt := talkative.NewPrompt().
+		System("policy", "Answer using the documents only.").
+		Document("doc-1", "https://example.com/paris", strings.Repeat("Paris is the capital of France. ", 10)).
+		Document("doc-2", "https://example.com/lyon", "Lyon is a city in France.").
+		Example("capital", "Capital of Italy?", "Rome").
+		User("What is the capital of France?")
+
+	t.Run("prompt-render", func(t *testing.T) {
+		messages, provenance := prompt.Render()
+
+		assert.Len(t, messages, 6)
+		assert.Len(t, provenance, 6)
+		assert.Equal(t, talkative.SYSTEM, messages[0].Role)
+		assert.Equal(t, "Document doc-2 (https://example.com/lyon):\nLyon is a city in France.", messages[2].Content)
+		assert.Equal(t, talkative.ASSISTANT, messages[4].Role)
+		assert.Equal(t, talkative.USER, messages[5].Role)
+		assert.Equal(t, "https://example.com/paris", provenance[1].Source)
+	})
+
+	t.Run("prompt-redact", func(t *testing.T) {
+		redacted := prompt.Map(func(part talkative.PromptPart) (talkative.PromptPart, bool) {
+			if part.Kind == talkative.PART_DOCUMENT {
+				part.Content = strings.ReplaceAll(part.Content, "France", "[REDACTED]")
+			}
+
+			return part, part.Kind != talkative.PART_EXAMPLE
+		})
+
+		assert.Len(t, redacted.Parts, 4)
+		assert.NotContains(t, redacted.Parts[1].Content, "France")
+		assert.Len(t, prompt.Filter(talkative.PART_EXAMPLE), 2)
+	})
+
+	t.Run("prompt-trim", func(t *testing.T) {
+		trimmed, dropped := prompt.Trim(prompt.Tokens() - 10)
+
+		assert.Len(t, dropped, 1)
+		assert.Equal(t, "doc-1", dropped[0].Label)
+		assert.Len(t, trimmed.Parts, 5)
+
+		trimmed, dropped = prompt.Trim(0)
+
+		assert.Len(t, dropped, 4)
+		assert.Equal(t, []talkative.PartKind{talkative.PART_SYSTEM, talkative.PART_USER}, []talkative.PartKind{trimmed.Parts[0].Kind, trimmed.Parts[1].Kind})
+	})
+}
@@ -0,0 +1,212 @@
+package talkative
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// Default settings of a RepetitionGuard.
+const (
+	DEFAULT_REPETITION_WINDOW  = 6   // Number of words of the n-grams compared.
+	DEFAULT_REPETITION_REPEATS = 3   // Number of times an n-gram may repeat before aborting.
+	DEFAULT_REPEAT_PENALTY     = 1.1 // The repeat_penalty of Ollama when none is set.
+)
+
+// ErrRepetitionDetected is reported when a generation is aborted because it is stuck in a repetition loop.
+var ErrRepetitionDetected = newError("repetition detected")
+
+// RepetitionGuard detects pathological repetition in streamed output and aborts the generation early.
+//
+// The output is split in words and every n-gram of Window words is counted, the generation is
+// aborted once any n-gram occurs more than Repeats times.
+type RepetitionGuard struct {
+	Window  int // Number of words of the n-grams, defaults to DEFAULT_REPETITION_WINDOW.
+	Repeats int // Number of times an n-gram may repeat, defaults to DEFAULT_REPETITION_REPEATS.
+
+	Retries     int     // Number of retries made by GuardedChat after a repetition is detected.
+	PenaltyStep float64 // Amount added to the repeat_penalty option on every retry, i.e: 0.1.
+}
+
+// repetitionTracker counts the n-grams of a single stream.
+type repetitionTracker struct {
+	window  int
+	repeats int
+	pending string
+	words   []string
+	counts  map[string]int
+}
+
+// tracker creates a new tracker with the settings of the guard.
+func (g *RepetitionGuard) tracker() *repetitionTracker {
+	t := &repetitionTracker{window: g.Window, repeats: g.Repeats, counts: map[string]int{}}
+
+	if t.window <= 0 {
+		t.window = DEFAULT_REPETITION_WINDOW
+	}
+
+	if t.repeats <= 0 {
+		t.repeats = DEFAULT_REPETITION_REPEATS
+	}
+
+	return t
+}
+
+// write adds a chunk of output and reports whether a repetition loop was detected.
+//
+// Words are only counted once they are complete, i.e: followed by a space.
+func (t *repetitionTracker) write(chunk string) bool {
+	t.pending += chunk
+
+	end := strings.LastIndexFunc(t.pending, unicode.IsSpace)
+
+	if end < 0 {
+		return false
+	}
+
+	complete := t.pending[:end]
+	t.pending = t.pending[end:]
+
+	for _, word := range strings.Fields(complete) {
+		t.words = append(t.words, strings.ToLower(word))
+
+		if len(t.words) < t.window {
+			continue
+		}
+
+		key := strings.Join(t.words[len(t.words)-t.window:], " ")
+		t.counts[key]++
+
+		if t.counts[key] > t.repeats {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ChatCallback wraps the callback so the generation is aborted through cancel once a repetition loop
+// is detected, the callback then receives ErrRepetitionDetected and no further responses.
+func (g *RepetitionGuard) ChatCallback(cancel context.CancelFunc, cb ChatCallBack) ChatCallBack {
+	t := g.tracker()
+	aborted := false
+
+	return func(cr *ChatResponse, err error) {
+		if aborted {
+			return
+		}
+
+		if err == nil && t.write(cr.Message.Content) {
+			aborted = true
+			cancel()
+			cb(nil, ErrRepetitionDetected)
+
+			return
+		}
+
+		cb(cr, err)
+	}
+}
+
+// CompletionCallback wraps the callback so the generation is aborted through cancel once a repetition loop
+// is detected, the callback then receives ErrRepetitionDetected and no further responses.
+func (g *RepetitionGuard) CompletionCallback(cancel context.CancelFunc, cb CompletionCallback) CompletionCallback {
+	t := g.tracker()
+	aborted := false
+
+	return func(cr *CompletionResponse, err error) {
+		if aborted {
+			return
+		}
+
+		if err == nil && t.write(cr.Response) {
+			aborted = true
+			cancel()
+			cb(nil, ErrRepetitionDetected)
+
+			return
+		}
+
+		cb(cr, err)
+	}
+}
+
+// GuardedChat sends the chat and returns its complete response, aborting generations stuck in a repetition loop.
+//
+// After a repetition is detected, the chat is retried up to guard.Retries times with the repeat_penalty
+// option increased by guard.PenaltyStep. ErrRepetitionDetected is returned when every attempt looped.
+func (c *Client) GuardedChat(ctx context.Context, model string, guard *RepetitionGuard, params *ChatParams, msgs ...ChatMessage) (string, error) {
+	if guard == nil {
+		guard = &RepetitionGuard{}
+	}
+
+	var err error
+
+	for attempt := 0; attempt <= guard.Retries; attempt++ {
+		var response string
+
+		if response, err = c.guardedChat(ctx, model, guard, params, msgs); !errors.Is(err, ErrRepetitionDetected) {
+			return response, err
+		}
+
+		params = withRepeatPenalty(params, guard.PenaltyStep)
+	}
+
+	return "", err
+}
+
+// guardedChat performs a single guarded chat, collecting the complete response.
+func (c *Client) guardedChat(ctx context.Context, model string, guard *RepetitionGuard, params *ChatParams, msgs []ChatMessage) (string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		sb        strings.Builder
+		streamErr error
+	)
+
+	done, err := c.ChatContext(ctx, model, guard.ChatCallback(cancel, func(cr *ChatResponse, err error) {
+		if err != nil {
+			streamErr = err
+
+			return
+		}
+
+		sb.WriteString(cr.Message.Content)
+	}), params, msgs...)
+
+	if err != nil {
+		return "", err
+	}
+
+	<-done
+
+	return sb.String(), streamErr
+}
+
+// withRepeatPenalty returns a copy of the params with the repeat_penalty option increased by step.
+func withRepeatPenalty(params *ChatParams, step float64) *ChatParams {
+	copied := ChatParams{}
+
+	if params != nil {
+		copied = *params
+	}
+
+	options := make(map[string]interface{}, len(copied.Options)+1)
+
+	for key, value := range copied.Options {
+		options[key] = value
+	}
+
+	penalty, ok := options["repeat_penalty"].(float64)
+
+	if !ok {
+		penalty = DEFAULT_REPEAT_PENALTY
+	}
+
+	options["repeat_penalty"] = penalty + step
+	copied.Options = options
+
+	return &copied
+}
@@ -0,0 +1,92 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRepetitionGuard tests aborting a stream stuck in a repetition loop.
+func TestRepetitionGuard(t *testing.T) {
+	guard := &talkative.RepetitionGuard{Window: 3, Repeats: 2}
+	cancelled := false
+	responses := []string{}
+
+	var guardErr error
+
+	cb := guard.ChatCallback(func() { cancelled = true }, func(cr *talkative.ChatResponse, err error) {
+		if err != nil {
+			guardErr = err
+
+			return
+		}
+
+		responses = append(responses, cr.Message.Content)
+	})
+
+	cb(&talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Once upon a time "}}, nil)
+	assert.False(t, cancelled)
+
+	for i := 0; i < 3; i++ {
+		cb(&talkative.ChatResponse{Message: talkative.ChatMessage{Content: "I am stuck. "}}, nil)
+	}
+
+	assert.True(t, cancelled)
+	assert.ErrorIs(t, guardErr, talkative.ErrRepetitionDetected)
+
+	cb(&talkative.ChatResponse{Message: talkative.ChatMessage{Content: "ignored "}}, nil)
+	assert.Len(t, responses, 3)
+}
+
+// TestGuardedChat tests retrying a looping chat with an increased repeat penalty.
+func TestGuardedChat(t *testing.T) {
+	penalties := []any{}
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		var penalty any
+
+		if request.ChatParams != nil {
+			penalty = request.Options["repeat_penalty"]
+		}
+
+		penalties = append(penalties, penalty)
+		encoder := json.NewEncoder(w)
+
+		if penalty == nil {
+			for i := 0; i < 50; i++ {
+				encoder.Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "again and "}})
+			}
+
+			return
+		}
+
+		encoder.Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "A fine answer."}, Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	guard := &talkative.RepetitionGuard{Retries: 1, PenaltyStep: 0.2}
+	message := talkative.ChatMessage{Role: talkative.USER, Content: "Hi"}
+
+	response, err := client.GuardedChat(context.Background(), "", guard, nil, message)
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "A fine answer.", response)
+		assert.Len(t, penalties, 2)
+		assert.InDelta(t, 1.3, penalties[1], 0.001)
+	}
+
+	_, err = client.GuardedChat(context.Background(), "", &talkative.RepetitionGuard{}, nil, message)
+	{
+		assert.ErrorIs(t, err, talkative.ErrRepetitionDetected)
+	}
+}
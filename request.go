@@ -0,0 +1,187 @@
+package talkative
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// post encodes the request as JSON and sends it to the given endpoint of this client.
+//
+// The request body is compressed according to the client's compression settings.
+// Responses other than 200 OK are translated to errors and their body is closed,
+// otherwise the caller is responsible for closing the response body.
+func (c *Client) post(ctx context.Context, endpoint string, request any) (*http.Response, error) {
+	body := &bytes.Buffer{}
+
+	if err := json.NewEncoder(body).Encode(request); err != nil {
+		return nil, fmt.Errorf("%w:%v", ErrEncoding, err)
+	}
+
+	compress := c.compress(body.Len())
+	res, err := c.send(ctx, endpoint, body.Bytes(), compress)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if compress && c.compression == COMPRESSION_AUTO && res.StatusCode == http.StatusUnsupportedMediaType {
+		res.Body.Close()
+		c.compressionRejected.Store(true)
+
+		if res, err = c.send(ctx, endpoint, body.Bytes(), false); err != nil {
+			return nil, err
+		}
+	}
+
+	return check(res)
+}
+
+// get sends a GET request to the given endpoint of this client.
+//
+// Responses other than 200 OK are translated to errors and their body is closed,
+// otherwise the caller is responsible for closing the response body.
+func (c *Client) get(ctx context.Context, endpoint string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.roundTrip(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return check(res)
+}
+
+// do encodes the request as JSON and sends it with the given method, without compression.
+//
+// Responses other than 200 OK are translated to errors and their body is closed,
+// otherwise the caller is responsible for closing the response body.
+func (c *Client) do(ctx context.Context, method, endpoint string, request any) (*http.Response, error) {
+	body := &bytes.Buffer{}
+
+	if err := json.NewEncoder(body).Encode(request); err != nil {
+		return nil, fmt.Errorf("%w:%v", ErrEncoding, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.roundTrip(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return check(res)
+}
+
+// check translates responses other than 200 OK to errors, closing their body.
+func check(res *http.Response) (*http.Response, error) {
+	if res.StatusCode == http.StatusOK {
+		return res, nil
+	}
+
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+
+	e := &APIError{StatusCode: res.StatusCode, Message: string(bytes.TrimSpace(body))}
+
+	if res.Request != nil {
+		e.Endpoint = res.Request.URL.String()
+	}
+
+	var server struct {
+		Error string `json:"error"`
+	}
+
+	if json.Unmarshal(body, &server) == nil && server.Error != "" {
+		e.Message = server.Error
+	}
+
+	return nil, e
+}
+
+// decode reads the JSON body of the response into a new T, closing the body.
+func decode[T any](res *http.Response) (*T, error) {
+	var value T
+
+	if err := decodeInto(res, &value); err != nil {
+		return nil, err
+	}
+
+	return &value, nil
+}
+
+// decodeInto reads the JSON body of the response into value, closing the body.
+func decodeInto(res *http.Response, value any, opts ...DecodeOption) error {
+	defer res.Body.Close()
+
+	options := newDecodeOptions(opts)
+
+	if err := decodeValue(options.decoder(res.Body), options, value); err != nil {
+		return fmt.Errorf("%w: %w", ErrDecoding, err)
+	}
+
+	return nil
+}
+
+// send performs a single POST request with the given payload, gzip compressing it when requested.
+func (c *Client) send(ctx context.Context, url string, payload []byte, compress bool) (*http.Response, error) {
+	var body io.Reader = bytes.NewReader(payload)
+
+	if compress {
+		compressed := &bytes.Buffer{}
+		writer := gzip.NewWriter(compressed)
+
+		if _, err := writer.Write(payload); err != nil {
+			return nil, fmt.Errorf("%w:%v", ErrEncoding, err)
+		}
+
+		if err := writer.Close(); err != nil {
+			return nil, fmt.Errorf("%w:%v", ErrEncoding, err)
+		}
+
+		body = compressed
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	return c.roundTrip(req)
+}
+
+// compress reports whether a request body of the given size should be compressed.
+func (c *Client) compress(size int) bool {
+	switch c.compression {
+	case COMPRESSION_GZIP:
+		return size > c.compressionMinSize
+	case COMPRESSION_AUTO:
+		return size > c.compressionMinSize && !c.compressionRejected.Load()
+	default:
+		return false
+	}
+}
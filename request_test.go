@@ -0,0 +1,100 @@
+package talkative_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCompression tests gzip compression of request bodies, including the automatic fallback
+// when the server rejects compressed bodies.
+func TestCompression(t *testing.T) {
+	message := talkative.ChatMessage{
+		Role:    talkative.USER,
+		Content: strings.Repeat("Hi there! ", 100),
+	}
+	acceptGzip := true
+	encodings := []string{}
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := r.Header.Get("Content-Encoding")
+		encodings = append(encodings, encoding)
+
+		var body io.Reader = r.Body
+
+		if encoding == "gzip" {
+			if !acceptGzip {
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+
+				return
+			}
+
+			body, _ = gzip.NewReader(r.Body)
+		}
+
+		var request talkative.ChatRequest
+
+		if err := json.NewDecoder(body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{
+			Message: request.Messages[0],
+			Done:    true,
+		})
+	}))
+	defer server.Close()
+
+	chat := func(client *talkative.Client) string {
+		content := ""
+
+		done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {
+			if err == nil {
+				content += cr.Message.Content
+			}
+		}, nil, message)
+
+		assert.NoError(t, err)
+
+		if done != nil {
+			<-done
+		}
+
+		return content
+	}
+
+	t.Run("compression-gzip", func(t *testing.T) {
+		encodings = nil
+		client, _ := talkative.New(server.URL, talkative.WithCompression(talkative.COMPRESSION_GZIP, 64))
+
+		assert.Equal(t, message.Content, chat(client))
+		assert.Equal(t, []string{"gzip"}, encodings)
+	})
+
+	t.Run("compression-min-size", func(t *testing.T) {
+		encodings = nil
+		client, _ := talkative.New(server.URL, talkative.WithCompression(talkative.COMPRESSION_GZIP, 1<<20))
+
+		assert.Equal(t, message.Content, chat(client))
+		assert.Equal(t, []string{""}, encodings)
+	})
+
+	t.Run("compression-auto-fallback", func(t *testing.T) {
+		encodings = nil
+		acceptGzip = false
+		client, _ := talkative.New(server.URL, talkative.WithCompression(talkative.COMPRESSION_AUTO, 0))
+
+		assert.Equal(t, message.Content, chat(client))
+		assert.Equal(t, message.Content, chat(client))
+		assert.Equal(t, []string{"gzip", "", ""}, encodings)
+	})
+}
@@ -0,0 +1,213 @@
+package talkative
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DEFAULT_RETENTION_INTERVAL is the default interval between two purges of a RetentionConversationStore.
+const DEFAULT_RETENTION_INTERVAL = time.Hour
+
+// RetentionPolicy configures how long conversations are retained.
+type RetentionPolicy struct {
+	TTL           time.Duration   // Conversations not updated for longer are purged, 0 retains them forever.
+	RestoreWindow time.Duration   // Deleted conversations can be restored during this window before being purged, 0 deletes them immediately.
+	Interval      time.Duration   // Interval between two purges of Run, defaults to DEFAULT_RETENTION_INTERVAL.
+	OnPurge       func(id string) // Invoked for every purged conversation, i.e: for auditing. (Optional)
+	OnError       func(error)     // Invoked when a purge of Run fails. (Optional)
+}
+
+// RetentionConversationStore wraps a ConversationStore and applies a retention policy to its conversations:
+// deleted conversations are soft-deleted and restorable during the restore window, and expired conversations
+// are hidden until purged by Purge or Run.
+type RetentionConversationStore struct {
+	store  ConversationStore
+	policy RetentionPolicy
+}
+
+// NewRetentionConversationStore creates a new store applying the retention policy to the underlying store.
+func NewRetentionConversationStore(store ConversationStore, policy RetentionPolicy) *RetentionConversationStore {
+	return &RetentionConversationStore{store: store, policy: policy}
+}
+
+// Save saves the conversation to the underlying store.
+//
+// It returns ErrConversationNotFound when the stored conversation is deleted or expired, so saving a conversation
+// loaded before its deletion doesn't undelete it. Deleted conversations are brought back with Restore only.
+func (s *RetentionConversationStore) Save(conversation *Conversation) error {
+	stored, err := s.store.Load(conversation.ID)
+
+	if err != nil && !errors.Is(err, ErrConversationNotFound) {
+		return err
+	}
+
+	if err == nil && (stored.DeletedAt != nil || s.expired(stored, time.Now())) {
+		return ErrConversationNotFound
+	}
+
+	return s.store.Save(conversation)
+}
+
+// Load returns the conversation with the given ID, or ErrConversationNotFound when it is deleted or expired.
+func (s *RetentionConversationStore) Load(id string) (*Conversation, error) {
+	conversation, err := s.store.Load(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if conversation.DeletedAt != nil || s.expired(conversation, time.Now()) {
+		return nil, ErrConversationNotFound
+	}
+
+	return conversation, nil
+}
+
+// List returns the conversations which are neither deleted nor expired, ordered by creation time.
+func (s *RetentionConversationStore) List() ([]*Conversation, error) {
+	conversations, err := s.store.List()
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	retained := make([]*Conversation, 0, len(conversations))
+
+	for _, conversation := range conversations {
+		if conversation.DeletedAt == nil && !s.expired(conversation, now) {
+			retained = append(retained, conversation)
+		}
+	}
+
+	return retained, nil
+}
+
+// Delete soft-deletes the conversation with the given ID, or deletes it when the policy has no restore window.
+func (s *RetentionConversationStore) Delete(id string) error {
+	if s.policy.RestoreWindow <= 0 {
+		return s.store.Delete(id)
+	}
+
+	conversation, err := s.store.Load(id)
+
+	if errors.Is(err, ErrConversationNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if conversation.DeletedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	conversation.DeletedAt = &now
+
+	return s.store.Save(conversation)
+}
+
+// Restore restores the soft-deleted conversation with the given ID.
+//
+// It returns ErrConversationNotFound when the conversation doesn't exist, is expired or its restore window elapsed.
+// Restoring a conversation which is not deleted is a no-op.
+func (s *RetentionConversationStore) Restore(id string) error {
+	conversation, err := s.store.Load(id)
+
+	if err != nil {
+		return err
+	}
+
+	if s.purgeable(conversation, time.Now()) {
+		return ErrConversationNotFound
+	}
+
+	if conversation.DeletedAt == nil {
+		return nil
+	}
+
+	conversation.DeletedAt = nil
+
+	return s.store.Save(conversation)
+}
+
+// Purge permanently deletes the expired conversations and the deleted conversations whose restore window elapsed,
+// returning the number of purged conversations.
+func (s *RetentionConversationStore) Purge() (int, error) {
+	conversations, err := s.store.List()
+
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	purged := 0
+
+	for _, conversation := range conversations {
+		if !s.purgeable(conversation, now) {
+			continue
+		}
+
+		if err := s.store.Delete(conversation.ID); err != nil {
+			return purged, err
+		}
+
+		purged++
+
+		if s.policy.OnPurge != nil {
+			s.policy.OnPurge(conversation.ID)
+		}
+	}
+
+	return purged, nil
+}
+
+// Run purges the store every interval until the context is cancelled.
+func (s *RetentionConversationStore) Run(ctx context.Context) {
+	interval := s.policy.Interval
+
+	if interval <= 0 {
+		interval = DEFAULT_RETENTION_INTERVAL
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Purge(); err != nil && s.policy.OnError != nil {
+				s.policy.OnError(err)
+			}
+		}
+	}
+}
+
+// expired reports whether the conversation was not updated for longer than the TTL.
+func (s *RetentionConversationStore) expired(conversation *Conversation, now time.Time) bool {
+	if s.policy.TTL <= 0 {
+		return false
+	}
+
+	updated := conversation.UpdatedAt
+
+	if updated.IsZero() {
+		updated = conversation.CreatedAt
+	}
+
+	return now.Sub(updated) > s.policy.TTL
+}
+
+// purgeable reports whether the conversation is expired, or deleted for longer than the restore window.
+func (s *RetentionConversationStore) purgeable(conversation *Conversation, now time.Time) bool {
+	if conversation.DeletedAt != nil && now.Sub(*conversation.DeletedAt) > s.policy.RestoreWindow {
+		return true
+	}
+
+	return s.expired(conversation, now)
+}
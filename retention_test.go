@@ -0,0 +1,104 @@
+package talkative_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRetentionSoftDelete tests soft-deleting, restoring and purging conversations.
+func TestRetentionSoftDelete(t *testing.T) {
+	underlying := talkative.NewMemoryConversationStore()
+	purged := []string{}
+
+	store := talkative.NewRetentionConversationStore(underlying, talkative.RetentionPolicy{
+		RestoreWindow: 50 * time.Millisecond,
+		OnPurge:       func(id string) { purged = append(purged, id) },
+	})
+
+	now := time.Now()
+
+	store.Save(&talkative.Conversation{ID: "1", CreatedAt: now, UpdatedAt: now})
+	store.Save(&talkative.Conversation{ID: "2", CreatedAt: now, UpdatedAt: now})
+
+	assert.NoError(t, store.Delete("1"))
+
+	_, err := store.Load("1")
+	assert.ErrorIs(t, err, talkative.ErrConversationNotFound)
+
+	// saving a copy loaded before the deletion doesn't undelete it
+	err = store.Save(&talkative.Conversation{ID: "1", CreatedAt: now, UpdatedAt: time.Now()})
+	assert.ErrorIs(t, err, talkative.ErrConversationNotFound)
+
+	stored, _ := underlying.Load("1")
+	assert.NotNil(t, stored.DeletedAt)
+
+	list, _ := store.List()
+	assert.Len(t, list, 1)
+
+	assert.NoError(t, store.Restore("1"))
+	_, err = store.Load("1")
+	assert.NoError(t, err)
+
+	store.Delete("1")
+	time.Sleep(60 * time.Millisecond)
+
+	assert.ErrorIs(t, store.Restore("1"), talkative.ErrConversationNotFound)
+
+	count, err := store.Purge()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{"1"}, purged)
+
+	_, err = underlying.Load("1")
+	assert.ErrorIs(t, err, talkative.ErrConversationNotFound)
+}
+
+// TestRetentionTTL tests hiding and purging expired conversations with the janitor.
+func TestRetentionTTL(t *testing.T) {
+	underlying := talkative.NewMemoryConversationStore()
+	purged := make(chan string, 1)
+
+	store := talkative.NewRetentionConversationStore(underlying, talkative.RetentionPolicy{
+		TTL:      time.Hour,
+		Interval: 10 * time.Millisecond,
+		OnPurge:  func(id string) { purged <- id },
+	})
+
+	now := time.Now()
+
+	store.Save(&talkative.Conversation{ID: "old", CreatedAt: now.Add(-2 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour)})
+	store.Save(&talkative.Conversation{ID: "recent", CreatedAt: now.Add(-2 * time.Hour), UpdatedAt: now})
+
+	_, err := store.Load("old")
+	assert.ErrorIs(t, err, talkative.ErrConversationNotFound)
+
+	list, _ := store.List()
+	assert.Len(t, list, 1)
+
+	// expired conversations can neither be restored nor saved again
+	assert.ErrorIs(t, store.Restore("old"), talkative.ErrConversationNotFound)
+	assert.ErrorIs(t, store.Save(&talkative.Conversation{ID: "old", CreatedAt: now, UpdatedAt: now}), talkative.ErrConversationNotFound)
+	assert.NoError(t, store.Restore("recent"))
+
+	// Without a restore window conversations are deleted immediately.
+	store.Delete("recent")
+	_, err = underlying.Load("recent")
+	assert.ErrorIs(t, err, talkative.ErrConversationNotFound)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go store.Run(ctx)
+
+	select {
+	case id := <-purged:
+		assert.Equal(t, "old", id)
+	case <-time.After(time.Second):
+		t.Fatal("expired conversation was not purged")
+	}
+}
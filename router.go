@@ -0,0 +1,190 @@
+package talkative
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Define an enum-like type to represent the capabilities of a model served by an endpoint.
+type Capability string
+
+const (
+	// The model understands images.
+	CAPABILITY_VISION Capability = "vision"
+
+	// The model supports tool calling.
+	CAPABILITY_TOOLS Capability = "tools"
+
+	// The model produces embeddings.
+	CAPABILITY_EMBEDDING Capability = "embedding"
+
+	// The model reliably produces structured JSON output.
+	CAPABILITY_JSON Capability = "json"
+)
+
+// ErrNoRoute is returned when no target of a Router meets the needs of a request.
+var ErrNoRoute = newError("no target meets the needs of the request")
+
+// Target is a model served by an endpoint, tagged with the capabilities it offers.
+type Target struct {
+	Client        *Client      // The client of the endpoint.
+	Model         string       // The model, defaults to the default model of the client.
+	Capabilities  []Capability // The capabilities of the model.
+	ContextLength int          // The context length of the model, 0 when it doesn't matter. (Optional)
+	Weight        int          // The relative share of the requests the target receives, defaults to 1.
+}
+
+// Needs are the capabilities and context length a request requires from its target.
+type Needs struct {
+	Capabilities  []Capability // The capabilities the target must offer.
+	ContextLength int          // The context length the target must offer, 0 when any is fine.
+}
+
+// String returns the description of the needs, i.e: "vision, tools, 8192 tokens of context".
+func (n Needs) String() string {
+	parts := make([]string, 0, len(n.Capabilities)+1)
+
+	for _, capability := range n.Capabilities {
+		parts = append(parts, string(capability))
+	}
+
+	if n.ContextLength > 0 {
+		parts = append(parts, fmt.Sprintf("%d tokens of context", n.ContextLength))
+	}
+
+	if len(parts) == 0 {
+		return "nothing"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// ChatNeeds returns the needs of a chat from its messages: messages with images need CAPABILITY_VISION, and
+// the estimated tokens of the messages plus DEFAULT_CONTEXT_RESERVE need as much context length.
+func ChatNeeds(msgs ...ChatMessage) Needs {
+	needs := Needs{ContextLength: DEFAULT_CONTEXT_RESERVE}
+
+	for _, msg := range msgs {
+		if len(msg.Images) > 0 && !slices.Contains(needs.Capabilities, CAPABILITY_VISION) {
+			needs.Capabilities = append(needs.Capabilities, CAPABILITY_VISION)
+		}
+
+		needs.ContextLength += EstimateTokens(msg.Content)
+	}
+
+	return needs
+}
+
+// Router routes requests to the targets meeting their needs, i.e: chats with images to vision models and
+// long conversations to models with a large context window.
+//
+// The requests are spread over the compatible targets in proportion to their weight with a smooth weighted
+// round-robin, which interleaves the targets instead of sending bursts to the heaviest one.
+type Router struct {
+	mu      sync.Mutex
+	targets []*route
+	health  *HealthMonitor
+}
+
+// route is a target along with the state of the weighted round-robin.
+type route struct {
+	Target
+	current int
+}
+
+// NewRouter creates a new Router over the targets.
+func NewRouter(targets ...Target) *Router {
+	r := &Router{}
+
+	for _, target := range targets {
+		if target.Weight <= 0 {
+			target.Weight = 1
+		}
+
+		if target.Model == "" {
+			target.Model = target.Client.DefaultModel()
+		}
+
+		r.targets = append(r.targets, &route{Target: target})
+	}
+
+	return r
+}
+
+// SetHealthMonitor makes the router skip the targets whose endpoint the monitor reports as not ready.
+func (r *Router) SetHealthMonitor(monitor *HealthMonitor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.health = monitor
+}
+
+// Route returns the target of the next request with the given needs. It returns an error matching
+// ErrNoRoute when no ready target offers every capability and enough context length.
+func (r *Router) Route(needs Needs) (Target, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		best  *route
+		total int
+	)
+
+	for _, target := range r.targets {
+		if !target.meets(needs) || (r.health != nil && r.health.NotReady(target.Client.base)) {
+			continue
+		}
+
+		target.current += target.Weight
+		total += target.Weight
+
+		if best == nil || target.current > best.current {
+			best = target
+		}
+	}
+
+	if best == nil {
+		return Target{}, fmt.Errorf("%w: needs %s", ErrNoRoute, needs)
+	}
+
+	best.current -= total
+
+	return best.Target, nil
+}
+
+// Chat routes the chat to a target meeting the given needs along with the needs of its messages, see ChatNeeds,
+// and sends it like ChatContext() does with the model of the target.
+func (r *Router) Chat(ctx context.Context, needs Needs, cb ChatCallBack, params *ChatParams, msgs ...ChatMessage) (<-chan bool, error) {
+	inferred := ChatNeeds(msgs...)
+
+	for _, capability := range needs.Capabilities {
+		if !slices.Contains(inferred.Capabilities, capability) {
+			inferred.Capabilities = append(inferred.Capabilities, capability)
+		}
+	}
+
+	inferred.ContextLength = max(inferred.ContextLength, needs.ContextLength)
+
+	target, err := r.Route(inferred)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return target.Client.ChatContext(ctx, target.Model, cb, params, msgs...)
+}
+
+// meets reports whether the target offers every capability and enough context length for the needs.
+// Targets without a context length meet any context length.
+func (t *route) meets(needs Needs) bool {
+	for _, capability := range needs.Capabilities {
+		if !slices.Contains(t.Capabilities, capability) {
+			return false
+		}
+	}
+
+	return t.ContextLength == 0 || t.ContextLength >= needs.ContextLength
+}
@@ -0,0 +1,91 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRouterRoute tests picking the targets meeting the needs in proportion to their weight.
+func TestRouterRoute(t *testing.T) {
+	client, _ := talkative.New("http://localhost:11434")
+
+	router := talkative.NewRouter(
+		talkative.Target{Client: client, Model: "llama3", ContextLength: 8192, Weight: 2},
+		talkative.Target{Client: client, Model: "mistral", ContextLength: 32768},
+		talkative.Target{Client: client, Model: "llava", Capabilities: []talkative.Capability{talkative.CAPABILITY_VISION}, ContextLength: 4096},
+	)
+
+	picked := map[string]int{}
+
+	for i := 0; i < 6; i++ {
+		target, err := router.Route(talkative.Needs{ContextLength: 4096})
+		{
+			assert.NoError(t, err)
+		}
+
+		picked[target.Model]++
+	}
+
+	assert.Equal(t, map[string]int{"llama3": 3, "mistral": 2, "llava": 1}, picked)
+
+	target, err := router.Route(talkative.Needs{ContextLength: 16384})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "mistral", target.Model)
+	}
+
+	target, err = router.Route(talkative.Needs{Capabilities: []talkative.Capability{talkative.CAPABILITY_VISION}})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "llava", target.Model)
+	}
+
+	_, err = router.Route(talkative.Needs{Capabilities: []talkative.Capability{talkative.CAPABILITY_VISION}, ContextLength: 8192})
+	{
+		assert.ErrorIs(t, err, talkative.ErrNoRoute)
+		assert.EqualError(t, err, "no target meets the needs of the request: needs vision, 8192 tokens of context")
+	}
+}
+
+// TestRouterChat tests routing a chat from the needs of its messages.
+func TestRouterChat(t *testing.T) {
+	var model string
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		model = request.Model
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "A cat"}, Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	router := talkative.NewRouter(
+		talkative.Target{Client: client, Model: "llama3", Capabilities: []talkative.Capability{talkative.CAPABILITY_TOOLS}},
+		talkative.Target{Client: client, Model: "llava", Capabilities: []talkative.Capability{talkative.CAPABILITY_VISION}},
+	)
+
+	image := talkative.ChatMessage{Role: talkative.USER, Content: "What is this?", Images: []string{"aGVsbG8="}}
+
+	done, err := router.Chat(context.Background(), talkative.Needs{}, func(cr *talkative.ChatResponse, err error) {}, nil, image)
+	{
+		assert.NoError(t, err)
+		<-done
+		assert.Equal(t, "llava", model)
+	}
+
+	_, err = router.Chat(context.Background(), talkative.Needs{Capabilities: []talkative.Capability{talkative.CAPABILITY_TOOLS}}, func(cr *talkative.ChatResponse, err error) {}, nil, image)
+	{
+		assert.ErrorIs(t, err, talkative.ErrNoRoute)
+		assert.ErrorContains(t, err, "vision, tools")
+	}
+}
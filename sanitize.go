@@ -0,0 +1,368 @@
+package talkative
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// SanitizePolicy configures which HTML an output sanitizer lets through.
+//
+// Tags which are not allowed are removed while their text is kept, except for the tags of
+// DropContent whose content is removed too. URLs of links are checked against the allowed schemes.
+type SanitizePolicy struct {
+	AllowedTags    map[string][]string // Allowed tags, lower cased, with their allowed attributes.
+	DropContent    []string            // Tags removed along with their content, i.e: "script".
+	AllowedSchemes []string            // Allowed schemes of URL attributes and markdown links, relative URLs are always allowed.
+	URLAttributes  []string            // Attributes holding URLs, checked against the allowed schemes.
+}
+
+// DefaultSanitizePolicy returns a policy allowing basic formatting tags and links over http, https and mailto.
+func DefaultSanitizePolicy() *SanitizePolicy {
+	tags := map[string][]string{"a": {"href", "title"}}
+
+	for _, tag := range []string{"b", "i", "em", "strong", "code", "pre", "p", "br", "hr", "ul", "ol", "li", "blockquote", "h1", "h2", "h3", "h4", "h5", "h6", "table", "thead", "tbody", "tr", "th", "td", "del", "sup", "sub", "span", "div"} {
+		tags[tag] = nil
+	}
+
+	return &SanitizePolicy{
+		AllowedTags:    tags,
+		DropContent:    []string{"script", "style", "iframe", "object", "embed", "noscript", "template", "svg", "math"},
+		AllowedSchemes: []string{"http", "https", "mailto"},
+		URLAttributes:  []string{"href", "src", "action", "formaction", "cite", "poster"},
+	}
+}
+
+// Sanitize returns the output with the dangerous HTML and markdown links removed according to the policy.
+func (p *SanitizePolicy) Sanitize(output string) string {
+	s := p.sanitizer()
+
+	return s.feed(output) + s.flush()
+}
+
+// Sanitizer is a streaming filter removing dangerous HTML from model output before it reaches web contexts.
+//
+// Text which could be the start of a tag or of a markdown link is held back until it is complete,
+// so the sanitized output never contains a dangerous construct split across chunks.
+type Sanitizer struct {
+	state *sanitizer
+	cb    func(string, error)
+}
+
+// NewSanitizer creates a new streaming sanitizer calling cb with the sanitized chunks.
+//
+// When policy is nil, DefaultSanitizePolicy is used.
+func NewSanitizer(policy *SanitizePolicy, cb func(string, error)) *Sanitizer {
+	if policy == nil {
+		policy = DefaultSanitizePolicy()
+	}
+
+	return &Sanitizer{state: policy.sanitizer(), cb: cb}
+}
+
+// Write sanitizes a chunk, passing the part which can be safely emitted to the callback.
+func (s *Sanitizer) Write(chunk string) {
+	if out := s.state.feed(chunk); out != "" {
+		s.cb(out, nil)
+	}
+}
+
+// Flush sanitizes and emits the text held back, once the stream ended.
+func (s *Sanitizer) Flush() {
+	if out := s.state.flush(); out != "" {
+		s.cb(out, nil)
+	}
+}
+
+// ChatCallback returns a ChatCallBack sanitizing the content of the chat responses, flushing once the final
+// response arrives. Errors are passed to the callback.
+func (s *Sanitizer) ChatCallback() ChatCallBack {
+	return func(cr *ChatResponse, err error) {
+		if err != nil {
+			s.cb("", err)
+
+			return
+		}
+
+		s.Write(cr.Message.Content)
+
+		if cr.Done {
+			s.Flush()
+		}
+	}
+}
+
+// CompletionCallback returns a CompletionCallback sanitizing the completion responses, flushing once the final
+// response arrives. Errors are passed to the callback.
+func (s *Sanitizer) CompletionCallback() CompletionCallback {
+	return func(cr *CompletionResponse, err error) {
+		if err != nil {
+			s.cb("", err)
+
+			return
+		}
+
+		s.Write(cr.Response)
+
+		if cr.Done {
+			s.Flush()
+		}
+	}
+}
+
+// sanitizer holds the state of a sanitization.
+type sanitizer struct {
+	policy      *SanitizePolicy
+	pending     string // Text held back until the tag it starts is complete.
+	linkPending string // Text held back until the markdown link it starts is complete.
+	drop        string // The tag whose content is being dropped, if any.
+}
+
+// sanitizer creates a new sanitization state for the policy.
+func (p *SanitizePolicy) sanitizer() *sanitizer {
+	return &sanitizer{policy: p}
+}
+
+var (
+	tagAttribute  = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'=<>` + "`" + `]+)))?`)
+	markdownLink  = regexp.MustCompile(`\]\(\s*<?([^()\s>]*(?:\([^()\s]*\)[^()\s>]*)*)>?((?:\s+"[^"]*")?)\s*\)`)
+	markdownStart = "]("
+)
+
+// feed sanitizes the chunk, returning the part which can be emitted and holding back incomplete constructs.
+func (s *sanitizer) feed(chunk string) string {
+	text := s.pending + chunk
+	s.pending = ""
+
+	out := strings.Builder{}
+
+	for len(text) > 0 {
+		i := strings.IndexByte(text, '<')
+
+		if s.drop != "" {
+			if i < 0 {
+				// Keep a potential partial closing tag.
+				s.pending = text[max(0, len(text)-len(s.drop)-3):]
+
+				return s.links(out.String(), false)
+			}
+
+			text = text[i:]
+		} else {
+			if i < 0 {
+				out.WriteString(text)
+
+				break
+			}
+
+			out.WriteString(text[:i])
+			text = text[i:]
+		}
+
+		end, complete := tagEnd(text)
+
+		if !complete {
+			s.pending = text
+
+			break
+		}
+
+		tag := text[:end]
+		text = text[end:]
+
+		if s.drop != "" {
+			if name, closing := tagName(tag); closing && name == s.drop {
+				s.drop = ""
+			}
+
+			continue
+		}
+
+		out.WriteString(s.tag(tag))
+	}
+
+	return s.links(out.String(), false)
+}
+
+// flush sanitizes the text held back once the stream ended.
+func (s *sanitizer) flush() string {
+	text := s.pending
+	s.pending = ""
+
+	if s.drop != "" {
+		s.drop = ""
+
+		return s.links("", true)
+	}
+
+	// An unterminated tag is not a tag, it is rendered as text.
+	if strings.HasPrefix(text, "<") {
+		text = "&lt;" + text[1:]
+	}
+
+	return s.links(text, true)
+}
+
+// links neutralizes markdown links with disallowed schemes in the text, holding back a trailing
+// incomplete link unless final is set.
+func (s *sanitizer) links(text string, final bool) string {
+	text = s.linkPending + text
+	s.linkPending = ""
+
+	if !final {
+		if i := strings.LastIndex(text, markdownStart); i >= 0 && !strings.Contains(text[i:], ")") {
+			text, s.linkPending = text[:i], text[i:]
+		}
+	}
+
+	return markdownLink.ReplaceAllStringFunc(text, func(link string) string {
+		match := markdownLink.FindStringSubmatch(link)
+
+		if s.policy.allowedURL(match[1]) {
+			return link
+		}
+
+		return "](#" + match[2] + ")"
+	})
+}
+
+// tag sanitizes a complete tag, returning what should be written in its place.
+func (s *sanitizer) tag(tag string) string {
+	if strings.HasPrefix(tag, "<!--") || strings.HasPrefix(tag, "<!") || strings.HasPrefix(tag, "<?") {
+		return ""
+	}
+
+	name, closing := tagName(tag)
+
+	if name == "" {
+		return html.EscapeString(tag)
+	}
+
+	for _, dropped := range s.policy.DropContent {
+		if name == dropped {
+			if !closing && !strings.HasSuffix(tag, "/>") {
+				s.drop = name
+			}
+
+			return ""
+		}
+	}
+
+	attributes, ok := s.policy.AllowedTags[name]
+
+	if !ok {
+		return ""
+	}
+
+	if closing {
+		return "</" + name + ">"
+	}
+
+	out := strings.Builder{}
+	out.WriteString("<" + name)
+
+	body := strings.TrimSuffix(strings.TrimSuffix(tag[1+len(name):], ">"), "/")
+
+	for _, match := range tagAttribute.FindAllStringSubmatch(body, -1) {
+		attribute := strings.ToLower(match[1])
+		value := html.UnescapeString(match[2] + match[3] + match[4])
+
+		if !contains(attributes, attribute) {
+			continue
+		}
+
+		if contains(s.policy.URLAttributes, attribute) && !s.policy.allowedURL(value) {
+			continue
+		}
+
+		out.WriteString(" " + attribute + `="` + html.EscapeString(value) + `"`)
+	}
+
+	out.WriteString(">")
+
+	return out.String()
+}
+
+// allowedURL reports whether the URL is relative or uses an allowed scheme.
+func (p *SanitizePolicy) allowedURL(url string) bool {
+	url = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return -1
+		}
+
+		return r
+	}, html.UnescapeString(url))
+
+	colon := strings.IndexByte(url, ':')
+
+	if colon < 0 || strings.ContainsAny(url[:colon], "/?#") {
+		return true
+	}
+
+	return contains(p.AllowedSchemes, strings.ToLower(url[:colon]))
+}
+
+// tagEnd returns the offset right after the end of the tag starting the text, and whether it is complete.
+//
+// A "<" which doesn't start a tag, i.e: "a < b", is returned as a tag of its own, escaped by tag.
+func tagEnd(text string) (int, bool) {
+	if strings.HasPrefix(text, "<!--") {
+		if end := strings.Index(text, "-->"); end >= 0 {
+			return end + 3, true
+		}
+
+		return 0, false
+	}
+
+	if len(text) < 2 {
+		return 0, false
+	}
+
+	if next := rune(text[1]); !unicode.IsLetter(next) && next != '/' && next != '!' && next != '?' {
+		return 1, true
+	}
+
+	quote := byte(0)
+
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote != 0:
+			if text[i] == quote {
+				quote = 0
+			}
+		case text[i] == '"' || text[i] == '\'':
+			quote = text[i]
+		case text[i] == '>':
+			return i + 1, true
+		}
+	}
+
+	return 0, false
+}
+
+// tagName returns the lower cased name of the tag and whether it is a closing tag.
+func tagName(tag string) (string, bool) {
+	tag = strings.TrimPrefix(tag, "<")
+	closing := strings.HasPrefix(tag, "/")
+	tag = strings.TrimPrefix(tag, "/")
+
+	end := strings.IndexFunc(tag, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+
+	if end < 0 {
+		end = len(tag)
+	}
+
+	return strings.ToLower(tag[:end]), closing
+}
+
+// contains reports whether the values contain the value.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
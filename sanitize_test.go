@@ -0,0 +1,67 @@
+package talkative_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSanitize tests removing dangerous HTML and markdown links from an output.
+func TestSanitize(t *testing.T) {
+	policy := talkative.DefaultSanitizePolicy()
+
+	tests := map[string]string{
+		"<b>bold</b> text":                                    "<b>bold</b> text",
+		"hi<script>alert(1)</script> there":                   "hi there",
+		`<a href="https://example.com" onclick="x()">x</a>`:   `<a href="https://example.com">x</a>`,
+		`<a href="javascript:alert(1)">x</a>`:                 `<a>x</a>`,
+		`<a href="java&#x09;script:alert(1)">x</a>`:           `<a>x</a>`,
+		`<img src=x onerror=alert(1)>ok`:                      "ok",
+		"<custom>kept</custom>":                               "kept",
+		"a < b and <!-- hidden -->c":                          "a &lt; b and c",
+		"[click](javascript:alert(1)) [docs](https://go.dev)": "[click](#) [docs](https://go.dev)",
+		"unterminated <b":                                     "unterminated &lt;b",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, policy.Sanitize(input), input)
+	}
+}
+
+// TestSanitizePolicy tests configuring the allowed tags and schemes.
+func TestSanitizePolicy(t *testing.T) {
+	policy := &talkative.SanitizePolicy{
+		AllowedTags:    map[string][]string{"img": {"src"}},
+		AllowedSchemes: []string{"https"},
+		URLAttributes:  []string{"src"},
+	}
+
+	assert.Equal(t, `<img src="https://example.com/a.png">`, policy.Sanitize(`<img src="https://example.com/a.png" alt="a">`))
+	assert.Equal(t, "<img>bold", policy.Sanitize(`<img src="http://example.com/a.png"><b>bold</b>`))
+}
+
+// TestSanitizer tests sanitizing a stream whose tags and links are split across chunks.
+func TestSanitizer(t *testing.T) {
+	sb := strings.Builder{}
+
+	sanitizer := talkative.NewSanitizer(nil, func(chunk string, err error) {
+		assert.NoError(t, err)
+		assert.NotContains(t, chunk, "script")
+		assert.NotContains(t, chunk, "javascript")
+
+		sb.WriteString(chunk)
+	})
+
+	cb := sanitizer.ChatCallback()
+
+	for _, chunk := range []string{"Hello <scr", "ipt>alert(", "1)</scr", "ipt> <b>wor", "ld</b> [x](java", "script:alert(1))"} {
+		cb(&talkative.ChatResponse{Message: talkative.ChatMessage{Content: chunk}}, nil)
+	}
+
+	cb(&talkative.ChatResponse{Done: true}, nil)
+
+	assert.Equal(t, "Hello  <b>world</b> [x](#)", sb.String())
+}
@@ -0,0 +1,130 @@
+package talkative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SelfTestCheck is the result of a single check of SelfTest.
+type SelfTestCheck struct {
+	Name    string        // The name of the check, i.e: "connectivity".
+	Detail  string        // A human readable detail of the outcome.
+	Elapsed time.Duration // The duration of the check.
+	Err     error         // The error of the check, nil when it passed.
+}
+
+// SelfTestReport is the structured report of SelfTest.
+type SelfTestReport struct {
+	Model  string          // The model the generation was run against.
+	Checks []SelfTestCheck // The checks in the order they ran.
+}
+
+// OK reports whether every check passed.
+func (r *SelfTestReport) OK() bool {
+	return r.Err() == nil
+}
+
+// Err returns the errors of the failed checks joined together, nil when every check passed.
+func (r *SelfTestReport) Err() error {
+	errs := []error{}
+
+	for _, check := range r.Checks {
+		if check.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", check.Name, check.Err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SelfTest verifies the client can be used, which is ideal for readiness probes of services embedding it.
+//
+// It checks the connectivity with Ping, lists the models and runs a 1-token generation against the model,
+// which defaults to the default model of the client. Checks are skipped once one fails, the report is returned in any case.
+func (c *Client) SelfTest(ctx context.Context, model ...string) *SelfTestReport {
+	report := &SelfTestReport{Model: c.DefaultModel()}
+
+	if len(model) > 0 && model[0] != "" {
+		report.Model = model[0]
+	}
+
+	report.Model = c.resolve(report.Model)
+
+	checks := []struct {
+		name string
+		run  func() (string, error)
+	}{
+		{"connectivity", func() (string, error) {
+			return c.base, c.Ping(ctx)
+		}},
+		{"models", func() (string, error) {
+			models, err := c.ListModelsContext(ctx)
+
+			if err != nil {
+				return "", err
+			}
+
+			for _, m := range models {
+				if m.Name == report.Model || strings.TrimSuffix(m.Name, ":latest") == report.Model {
+					return fmt.Sprintf("%d models available", len(models)), nil
+				}
+			}
+
+			return "", fmt.Errorf("%w: model %q is not available", ErrModel, report.Model)
+		}},
+		{"generation", func() (string, error) {
+			return c.selfTestGeneration(ctx, report.Model)
+		}},
+	}
+
+	for _, check := range checks {
+		start := time.Now()
+		detail, err := check.run()
+
+		report.Checks = append(report.Checks, SelfTestCheck{Name: check.name, Detail: detail, Elapsed: time.Since(start), Err: err})
+
+		if err != nil {
+			break
+		}
+	}
+
+	return report
+}
+
+// selfTestGeneration generates a single token with the model.
+func (c *Client) selfTestGeneration(ctx context.Context, model string) (string, error) {
+	var (
+		response  string
+		streamErr error
+	)
+
+	message := &CompletionMessage{
+		Prompt:           "Hi",
+		CompletionParams: &CompletionParams{Options: map[string]interface{}{"num_predict": 1}},
+	}
+
+	done, err := c.CompletionContext(ctx, model, func(cr *CompletionResponse, err error) {
+		if err != nil {
+			streamErr = err
+
+			return
+		}
+
+		response += cr.Response
+	}, message)
+
+	if err != nil {
+		return "", err
+	}
+
+	<-done
+
+	if streamErr != nil {
+		return "", streamErr
+	}
+
+	return fmt.Sprintf("generated %q", response), nil
+}
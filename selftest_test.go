@@ -0,0 +1,49 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSelfTest tests the report of a successful and a failing self test.
+func TestSelfTest(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte("Ollama is running"))
+		case "/api/tags":
+			w.Write([]byte(`{"models": [{"name": "llama2:latest"}]}`))
+		case "/api/generate":
+			var request talkative.CompletionRequest
+
+			json.NewDecoder(r.Body).Decode(&request)
+			assert.Equal(t, float64(1), request.Options["num_predict"])
+
+			json.NewEncoder(w).Encode(talkative.CompletionResponse{Response: "Hello", Done: true})
+		}
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	report := client.SelfTest(context.Background())
+	{
+		assert.True(t, report.OK())
+		assert.Equal(t, "llama2", report.Model)
+		assert.Len(t, report.Checks, 3)
+		assert.Equal(t, `generated "Hello"`, report.Checks[2].Detail)
+	}
+
+	report = client.SelfTest(context.Background(), "mistral")
+	{
+		assert.False(t, report.OK())
+		assert.Len(t, report.Checks, 2)
+		assert.ErrorIs(t, report.Err(), talkative.ErrModel)
+	}
+}
@@ -0,0 +1,207 @@
+package talkative
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DEFAULT_ABBREVIATIONS are the abbreviations which don't end a sentence, compared case insensitively without their final period.
+var DEFAULT_ABBREVIATIONS = []string{
+	"mr", "mrs", "ms", "dr", "prof", "sr", "jr", "st", "vs", "e.g", "i.e", "cf", "approx", "no", "fig", "inc", "ltd", "co", "jan", "feb", "mar", "apr", "jun", "jul", "aug", "sep", "sept", "oct", "nov", "dec",
+}
+
+// SentenceCallback is the callback handling complete sentences and errors.
+type SentenceCallback func(string, error)
+
+// SentenceOptions configures a SentenceStream.
+type SentenceOptions struct {
+	MinLength     int      // Sentences shorter than MinLength characters are merged with the following one.
+	Abbreviations []string // Abbreviations which don't end a sentence, defaults to DEFAULT_ABBREVIATIONS.
+}
+
+// SentenceStream segments streamed output into complete sentences as they finish.
+//
+// It is designed for piping responses into text-to-speech engines, which produce better speech
+// from whole sentences than from arbitrary chunks. A sentence ends with a terminal punctuation
+// followed by a space, or with a line break. Abbreviations, initials and decimal numbers don't end sentences.
+type SentenceStream struct {
+	cb            SentenceCallback
+	minLength     int
+	abbreviations map[string]bool
+	buf           string
+}
+
+// NewSentenceStream creates a new stream calling cb with every complete sentence.
+func NewSentenceStream(cb SentenceCallback, opts *SentenceOptions) *SentenceStream {
+	if opts == nil {
+		opts = &SentenceOptions{}
+	}
+
+	abbreviations := opts.Abbreviations
+
+	if abbreviations == nil {
+		abbreviations = DEFAULT_ABBREVIATIONS
+	}
+
+	s := &SentenceStream{cb: cb, minLength: opts.MinLength, abbreviations: map[string]bool{}}
+
+	for _, abbreviation := range abbreviations {
+		s.abbreviations[strings.ToLower(abbreviation)] = true
+	}
+
+	return s
+}
+
+// Write adds a chunk of text, emitting the sentences it completes.
+func (s *SentenceStream) Write(text string) {
+	s.buf += text
+
+	for {
+		end := s.boundary()
+
+		if end < 0 {
+			return
+		}
+
+		sentence := strings.TrimSpace(s.buf[:end])
+		s.buf = strings.TrimLeftFunc(s.buf[end:], unicode.IsSpace)
+
+		if sentence != "" {
+			s.cb(sentence, nil)
+		}
+	}
+}
+
+// Flush emits the remaining text as the last sentence.
+func (s *SentenceStream) Flush() {
+	if sentence := strings.TrimSpace(s.buf); sentence != "" {
+		s.cb(sentence, nil)
+	}
+
+	s.buf = ""
+}
+
+// ChatCallback returns a ChatCallBack writing the content of the chat responses into the stream,
+// flushing it once the final response arrives. Errors are passed to the sentence callback.
+func (s *SentenceStream) ChatCallback() ChatCallBack {
+	return func(cr *ChatResponse, err error) {
+		if err != nil {
+			s.cb("", err)
+
+			return
+		}
+
+		s.Write(cr.Message.Content)
+
+		if cr.Done {
+			s.Flush()
+		}
+	}
+}
+
+// CompletionCallback returns a CompletionCallback writing the completion responses into the stream,
+// flushing it once the final response arrives. Errors are passed to the sentence callback.
+func (s *SentenceStream) CompletionCallback() CompletionCallback {
+	return func(cr *CompletionResponse, err error) {
+		if err != nil {
+			s.cb("", err)
+
+			return
+		}
+
+		s.Write(cr.Response)
+
+		if cr.Done {
+			s.Flush()
+		}
+	}
+}
+
+// boundary returns the offset right after the first sentence end of the buffer, or -1.
+//
+// A terminal punctuation is only a boundary once the following character is known to be a space.
+func (s *SentenceStream) boundary() int {
+	// Skip the boundaries which would produce a sentence shorter than the minimum length.
+	searchFrom := s.minOffset()
+
+	for i, r := range s.buf {
+		if i < searchFrom {
+			continue
+		}
+
+		if r == '\n' {
+			return i + 1
+		}
+
+		if r != '.' && r != '!' && r != '?' && r != '…' {
+			continue
+		}
+
+		end := i + utf8.RuneLen(r)
+
+		// Include closing punctuation, i.e: quotes and parentheses.
+		for end < len(s.buf) {
+			next, size := utf8.DecodeRuneInString(s.buf[end:])
+
+			if !strings.ContainsRune(`"')]”’»`, next) && next != r {
+				break
+			}
+
+			end += size
+		}
+
+		if end >= len(s.buf) {
+			return -1
+		}
+
+		next, _ := utf8.DecodeRuneInString(s.buf[end:])
+
+		if !unicode.IsSpace(next) {
+			continue
+		}
+
+		if r == '.' && s.abbreviation(s.buf[:i]) {
+			continue
+		}
+
+		return end
+	}
+
+	return -1
+}
+
+// minOffset returns the byte offset of the buffer where the sentence reaches the minimum length.
+func (s *SentenceStream) minOffset() int {
+	if s.minLength <= 1 {
+		return 0
+	}
+
+	trimmed := strings.TrimLeftFunc(s.buf, unicode.IsSpace)
+	offset := len(s.buf) - len(trimmed)
+	count := 0
+
+	for i := range trimmed {
+		if count == s.minLength-1 {
+			return offset + i
+		}
+
+		count++
+	}
+
+	return len(s.buf)
+}
+
+// abbreviation reports whether the text ends with an abbreviation or an initial.
+func (s *SentenceStream) abbreviation(text string) bool {
+	start := strings.LastIndexFunc(text, func(r rune) bool { return unicode.IsSpace(r) || r == '(' || r == '"' })
+	word := text[start+1:]
+
+	if utf8.RuneCountInString(word) == 1 {
+		r, _ := utf8.DecodeRuneInString(word)
+
+		return unicode.IsUpper(r)
+	}
+
+	return s.abbreviations[strings.ToLower(word)]
+}
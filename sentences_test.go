@@ -0,0 +1,50 @@
+package talkative_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSentenceStream tests segmenting streamed chunks into sentences.
+func TestSentenceStream(t *testing.T) {
+	sentences := []string{}
+	stream := talkative.NewSentenceStream(func(sentence string, err error) {
+		assert.NoError(t, err)
+		sentences = append(sentences, sentence)
+	}, nil)
+
+	cb := stream.ChatCallback()
+	text := "Hello Dr. Smith! The value is 3.14 today. J. R. R. Tolkien wrote \"The Hobbit.\" Really?! Yes\nNew line then done"
+
+	for _, chunk := range strings.SplitAfter(text, " ") {
+		cb(&talkative.ChatResponse{Message: talkative.ChatMessage{Content: chunk}}, nil)
+	}
+
+	assert.Equal(t, []string{
+		"Hello Dr. Smith!",
+		"The value is 3.14 today.",
+		"J. R. R. Tolkien wrote \"The Hobbit.\"",
+		"Really?!",
+		"Yes",
+	}, sentences)
+
+	cb(&talkative.ChatResponse{Done: true}, nil)
+	assert.Equal(t, "New line then done", sentences[len(sentences)-1])
+}
+
+// TestSentenceStreamMinLength tests merging short sentences.
+func TestSentenceStreamMinLength(t *testing.T) {
+	sentences := []string{}
+	stream := talkative.NewSentenceStream(func(sentence string, err error) {
+		sentences = append(sentences, sentence)
+	}, &talkative.SentenceOptions{MinLength: 10})
+
+	stream.Write("Hi. Ok. This is longer. End")
+	stream.Flush()
+
+	assert.Equal(t, []string{"Hi. Ok. This is longer.", "End"}, sentences)
+}
@@ -0,0 +1,54 @@
+//go:build go1.23
+
+package talkative
+
+import (
+	"context"
+	"iter"
+)
+
+// ChatSeq is identical to ChatStream(), except that the responses are delivered as an iterator, so they can be
+// ranged over directly:
+//
+//	for response, err := range client.ChatSeq(ctx, model, nil, msgs...) {
+//		...
+//	}
+//
+// The error ending the stream, if any, is yielded last with a nil response. Breaking out of the loop aborts
+// the request and releases its resources. The iterator sends a new request every time it is ranged over.
+func (c *Client) ChatSeq(ctx context.Context, model string, params *ChatParams, msgs ...ChatMessage) iter.Seq2[*ChatResponse, error] {
+	return func(yield func(*ChatResponse, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		responses, errs := c.ChatStream(ctx, model, params, msgs...)
+
+		seq(responses, errs, yield)
+	}
+}
+
+// CompletionSeq is identical to CompletionStream(), except that the responses are delivered as an iterator,
+// like ChatSeq() does.
+func (c *Client) CompletionSeq(ctx context.Context, model string, msg *CompletionMessage) iter.Seq2[*CompletionResponse, error] {
+	return func(yield func(*CompletionResponse, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		responses, errs := c.CompletionStream(ctx, model, msg)
+
+		seq(responses, errs, yield)
+	}
+}
+
+// seq yields the responses of a stream followed by its error, stopping as soon as the consumer does.
+func seq[T any](responses <-chan T, errs <-chan error, yield func(*T, error) bool) {
+	for response := range responses {
+		if !yield(&response, nil) {
+			return
+		}
+	}
+
+	if err := <-errs; err != nil {
+		yield(nil, err)
+	}
+}
@@ -0,0 +1,87 @@
+//go:build go1.23
+
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChatSeq tests ranging over the responses of a chat with an iterator.
+func TestChatSeq(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "!"}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	content := ""
+
+	for response, err := range client.ChatSeq(context.Background(), "", nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"}) {
+		assert.NoError(t, err)
+
+		content += response.Message.Content
+	}
+
+	assert.Equal(t, "Hello!", content)
+
+	for response, err := range client.ChatSeq(context.Background(), "", nil) {
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, talkative.ErrMessage)
+	}
+}
+
+// TestCompletionSeq tests breaking out of an iterator early and receiving the error ending a stream.
+func TestCompletionSeq(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+
+		for i := 0; i < 100; i++ {
+			encoder.Encode(talkative.CompletionResponse{Response: "x"})
+		}
+
+		w.Write([]byte("{invalid"))
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	message := &talkative.CompletionMessage{Prompt: "Hi"}
+
+	var (
+		count   int
+		lastErr error
+	)
+
+	for _, err := range client.CompletionSeq(context.Background(), "", message) {
+		if err != nil {
+			lastErr = err
+
+			continue
+		}
+
+		count++
+	}
+
+	assert.Equal(t, 100, count)
+	assert.ErrorIs(t, lastErr, talkative.ErrDecoding)
+
+	count = 0
+
+	for response, err := range client.CompletionSeq(context.Background(), "", message) {
+		assert.NoError(t, err)
+		assert.Equal(t, "x", response.Response)
+
+		if count++; count == 3 {
+			break
+		}
+	}
+
+	assert.Equal(t, 3, count)
+}
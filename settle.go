@@ -0,0 +1,45 @@
+package talkative
+
+import "encoding/json"
+
+// IgnoredChunks returns the number of chunks, and errors, received after the final response of a stream
+// and ignored, i.e: because a gateway sent the final response twice. It is meant for debugging gateways.
+func (c *Client) IgnoredChunks() int64 {
+	return c.ignored.Load()
+}
+
+// settle wraps the callback so nothing is delivered once the final response was, making the completion
+// of streams idempotent. Ignored responses and errors are counted, see IgnoredChunks.
+//
+// The lines of plain streams are told final by their "done" field.
+func settle[T any](c *Client, cb func(T, error)) func(T, error) {
+	done := false
+
+	return func(response T, err error) {
+		if done {
+			c.ignored.Add(1)
+
+			return
+		}
+
+		if err == nil {
+			switch r := any(response).(type) {
+			case interface{ chunk() (string, bool) }:
+				_, done = r.chunk()
+			case string:
+				done = final(r)
+			}
+		}
+
+		cb(response, err)
+	}
+}
+
+// final reports whether the line of a plain stream is the final response.
+func final(line string) bool {
+	var response struct {
+		Done bool `json:"done"`
+	}
+
+	return json.Unmarshal([]byte(line), &response) == nil && response.Done
+}
@@ -0,0 +1,79 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDuplicateDone tests ignoring the final response sent twice and the data following it.
+func TestDuplicateDone(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+
+		encoder.Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}})
+		encoder.Encode(talkative.ChatResponse{Done: true})
+		encoder.Encode(talkative.ChatResponse{Done: true})
+		encoder.Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "late"}})
+		w.Write([]byte("garbage"))
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	events := []talkative.EventType{}
+	client.Subscribe(func(e talkative.Event) { events = append(events, e.Type) })
+
+	dones, content := 0, ""
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {
+		assert.NoError(t, err)
+
+		content += cr.Message.Content
+
+		if cr.Done {
+			dones++
+		}
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+
+	assert.NoError(t, err)
+	<-done
+
+	assert.Equal(t, 1, dones)
+	assert.Equal(t, "Hello", content)
+	assert.Equal(t, int64(3), client.IgnoredChunks())
+	assert.Equal(t, []talkative.EventType{talkative.EVENT_REQUEST_STARTED, talkative.EVENT_FIRST_TOKEN, talkative.EVENT_COMPLETED}, events)
+}
+
+// TestDuplicateDonePlain tests ignoring the lines following the final response of a plain stream.
+func TestDuplicateDonePlain(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+
+		encoder.Encode(talkative.CompletionResponse{Response: "Hello"})
+		encoder.Encode(talkative.CompletionResponse{Done: true})
+		encoder.Encode(talkative.CompletionResponse{Done: true})
+		encoder.Encode(talkative.CompletionResponse{Response: "late"})
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	lines := []string{}
+
+	done, err := client.PlainCompletion("", func(line string, err error) {
+		assert.NoError(t, err)
+
+		lines = append(lines, line)
+	}, &talkative.CompletionMessage{Prompt: "Hi"})
+
+	assert.NoError(t, err)
+	<-done
+
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[1], `"done":true`)
+	assert.Equal(t, int64(2), client.IgnoredChunks())
+}
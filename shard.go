@@ -0,0 +1,120 @@
+package talkative
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"sync"
+)
+
+// ErrNoShards is returned when items are sharded without any endpoint.
+var ErrNoShards = newError("no shards")
+
+// Keyed pairs a batch item with the key it is sharded by, i.e: a session or user identifier.
+type Keyed[T any] struct {
+	Key  string // The shard key, items of the same key are sent to the same endpoint.
+	Item T      // The item.
+}
+
+// Shards assigns keys to endpoints by a stable hash, so related requests share the KV-cache and
+// the warm models of a single endpoint.
+//
+// Keys are assigned with rendezvous hashing on the base URLs of the clients: the assignment does not
+// depend on the order of the clients, and adding or removing an endpoint only moves the keys of that
+// endpoint.
+type Shards struct {
+	clients []*Client
+
+	mu     sync.RWMutex
+	health *HealthMonitor
+}
+
+// NewShards creates new Shards spreading keys over the endpoints of the given clients.
+func NewShards(clients ...*Client) *Shards {
+	return &Shards{clients: clients}
+}
+
+// SetHealthMonitor makes keys skip the endpoints the monitor reports as not ready, they are assigned to
+// their next endpoint until the endpoint is ready again.
+func (s *Shards) SetHealthMonitor(monitor *HealthMonitor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.health = monitor
+}
+
+// Pick returns the client of the endpoint the key is assigned to, nil when there is no endpoint.
+func (s *Shards) Pick(key string) *Client {
+	if ranked := s.rank(key); len(ranked) > 0 {
+		return ranked[0]
+	}
+
+	return nil
+}
+
+// rank returns the clients of the ready endpoints, ordered by their preference for the key.
+func (s *Shards) rank(key string) []*Client {
+	s.mu.RLock()
+	health := s.health
+	s.mu.RUnlock()
+
+	clients := make([]*Client, 0, len(s.clients))
+	scores := map[*Client]uint64{}
+
+	for _, client := range s.clients {
+		if health != nil && health.NotReady(client.base) {
+			continue
+		}
+
+		h := fnv.New64a()
+		h.Write([]byte(client.base))
+		h.Write([]byte{0})
+		h.Write([]byte(key))
+
+		clients = append(clients, client)
+		scores[client] = mix(h.Sum64())
+	}
+
+	sort.SliceStable(clients, func(i, j int) bool {
+		return scores[clients[i]] > scores[clients[j]]
+	})
+
+	return clients
+}
+
+// ShardedBatch is identical to Batch, except that every item is processed by the client of the
+// endpoint its key is assigned to.
+func ShardedBatch[T, R any](ctx context.Context, shards *Shards, items []Keyed[T], opts BatchOptions, fn func(context.Context, *Client, T) (R, error)) BatchResults[R] {
+	return Batch(ctx, items, opts, func(ctx context.Context, keyed Keyed[T]) (R, error) {
+		client := shards.Pick(keyed.Key)
+
+		if client == nil {
+			var zero R
+
+			return zero, ErrNoShards
+		}
+
+		return fn(ctx, client, keyed.Item)
+	})
+}
+
+// ChatBatch is identical to Client.ChatBatch, except that every conversation is sent to the endpoint
+// its key is assigned to.
+//
+// When model is empty, the default model of the picked client is used.
+func (s *Shards) ChatBatch(ctx context.Context, model string, params *ChatParams, conversations []Keyed[[]ChatMessage], opts BatchOptions) BatchResults[string] {
+	return ShardedBatch(ctx, s, conversations, opts, func(ctx context.Context, client *Client, msgs []ChatMessage) (string, error) {
+		return client.chatTextContext(ctx, model, params, msgs...)
+	})
+}
+
+// mix scrambles the bits of the hash, FNV alone spreads keys differing by their last bytes poorly.
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+
+	return h
+}
@@ -0,0 +1,95 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestShardsPick tests that keys are assigned to endpoints stably and regardless of the order of the clients.
+func TestShardsPick(t *testing.T) {
+	a, _ := talkative.New("http://a:11434")
+	b, _ := talkative.New("http://b:11434")
+	c, _ := talkative.New("http://c:11434")
+
+	shards := talkative.NewShards(a, b, c)
+	reordered := talkative.NewShards(c, a, b)
+	used := map[*talkative.Client]int{}
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("session-%d", i)
+		picked := shards.Pick(key)
+
+		assert.Same(t, picked, shards.Pick(key))
+		assert.Same(t, picked, reordered.Pick(key))
+
+		used[picked]++
+	}
+
+	assert.Len(t, used, 3)
+
+	// Removing an endpoint only moves its own keys.
+	reduced := talkative.NewShards(a, b)
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("session-%d", i)
+
+		if picked := shards.Pick(key); picked != c {
+			assert.Same(t, picked, reduced.Pick(key))
+		}
+	}
+
+	assert.Nil(t, talkative.NewShards().Pick("session"))
+}
+
+// TestShardsChatBatch tests that conversations of the same key are sent to the same endpoint.
+func TestShardsChatBatch(t *testing.T) {
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var request talkative.ChatRequest
+
+			json.NewDecoder(r.Body).Decode(&request)
+
+			json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: name}, Done: true})
+		}
+	}
+
+	first := mockServer(handler("first"))
+	defer first.Close()
+
+	second := mockServer(handler("second"))
+	defer second.Close()
+
+	a, _ := talkative.New(first.URL)
+	b, _ := talkative.New(second.URL)
+
+	shards := talkative.NewShards(a, b)
+	conversations := []talkative.Keyed[[]talkative.ChatMessage]{}
+
+	for i := 0; i < 10; i++ {
+		conversations = append(conversations, talkative.Keyed[[]talkative.ChatMessage]{
+			Key:  fmt.Sprintf("user-%d", i%2),
+			Item: []talkative.ChatMessage{{Role: talkative.USER, Content: "hello"}},
+		})
+	}
+
+	results := shards.ChatBatch(context.Background(), "", nil, conversations, talkative.BatchOptions{})
+	{
+		assert.NoError(t, results.Err())
+
+		for i, result := range results {
+			assert.Equal(t, results[i%2].Value, result.Value)
+		}
+	}
+
+	results = talkative.NewShards().ChatBatch(context.Background(), "", nil, conversations[:1], talkative.BatchOptions{})
+	{
+		assert.ErrorIs(t, results.Err(), talkative.ErrNoShards)
+	}
+}
@@ -0,0 +1,184 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DEFAULT_FANOUT_BUFFER is the default number of messages buffered per sink of a FanOut.
+const DEFAULT_FANOUT_BUFFER = 64
+
+// Pre-defined errors used by the fan-out.
+var (
+	ErrSinkBehind = errors.New("sink fell behind")  // Error for sinks whose buffer stayed full for longer than the maximum lag.
+	ErrClosed     = errors.New("fan-out is closed") // Error for messages published after Close.
+)
+
+// FanOutOptions configures a FanOut.
+type FanOutOptions struct {
+	Buffer  int                       // Number of messages buffered per sink, defaults to DEFAULT_FANOUT_BUFFER.
+	Strict  bool                      // Fail the whole fan-out as soon as a sink fails or falls behind.
+	MaxLag  time.Duration             // How long a full sink is waited for in strict mode before failing, 0 waits forever.
+	OnError func(sink int, err error) // Invoked when a sink fails to publish, in non-strict mode. (Optional)
+	OnFail  func(err error)           // Invoked once when the fan-out fails in strict mode, i.e: to cancel the stream. (Optional)
+}
+
+// FanOut is a Publisher teeing every message to multiple sinks.
+//
+// Every sink receives the messages in the exact order they were published, each sink being fed by its own
+// goroutine through a buffer so a slow sink doesn't delay the others until its buffer is full. In strict mode,
+// meant for audit-critical deployments, the first sink failing or falling behind fails the whole fan-out:
+// publishing returns the error from then on and OnFail is invoked, i.e: to cancel the context of the stream.
+type FanOut struct {
+	opts   FanOutOptions
+	sinks  []chan fanOutMessage
+	mu     sync.Mutex // Serialises publishing so every sink receives the messages in the same order.
+	wg     sync.WaitGroup
+	closed bool
+
+	once   sync.Once
+	failed chan struct{}
+	err    error
+}
+
+// fanOutMessage is a message queued for a sink.
+type fanOutMessage struct {
+	ctx        context.Context
+	topic      string
+	key, value []byte
+}
+
+// NewFanOut creates a new FanOut publishing to the publishers.
+func NewFanOut(opts FanOutOptions, publishers ...Publisher) *FanOut {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DEFAULT_FANOUT_BUFFER
+	}
+
+	f := &FanOut{opts: opts, failed: make(chan struct{})}
+
+	for i, publisher := range publishers {
+		queue := make(chan fanOutMessage, opts.Buffer)
+		f.sinks = append(f.sinks, queue)
+		f.wg.Add(1)
+
+		go f.run(i, publisher, queue)
+	}
+
+	return f
+}
+
+// Publish queues the message for every sink, waiting while a sink's buffer is full.
+//
+// In strict mode it returns the error of the fan-out once failed, or ErrSinkBehind when a sink's buffer stays
+// full for longer than the maximum lag.
+func (f *FanOut) Publish(ctx context.Context, topic string, key, value []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrClosed
+	}
+
+	if err := f.Err(); err != nil {
+		return err
+	}
+
+	msg := fanOutMessage{ctx: ctx, topic: topic, key: key, value: value}
+
+	for i, queue := range f.sinks {
+		if err := f.enqueue(ctx, i, queue, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enqueue queues the message for the sink, bounding the wait by the maximum lag in strict mode.
+func (f *FanOut) enqueue(ctx context.Context, i int, queue chan<- fanOutMessage, msg fanOutMessage) error {
+	var lag <-chan time.Time
+
+	if f.opts.Strict && f.opts.MaxLag > 0 {
+		timer := time.NewTimer(f.opts.MaxLag)
+		defer timer.Stop()
+
+		lag = timer.C
+	}
+
+	select {
+	case queue <- msg:
+		return nil
+	case <-f.failed:
+		return f.Err()
+	case <-lag:
+		f.fail(fmt.Errorf("%w: sink %d did not keep up for %s", ErrSinkBehind, i, f.opts.MaxLag))
+
+		return f.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close waits for every sink to publish the queued messages, returning the error of the fan-out in strict mode.
+func (f *FanOut) Close() error {
+	f.mu.Lock()
+
+	if !f.closed {
+		f.closed = true
+
+		for _, queue := range f.sinks {
+			close(queue)
+		}
+	}
+
+	f.mu.Unlock()
+	f.wg.Wait()
+
+	return f.Err()
+}
+
+// Err returns the error which failed the fan-out in strict mode, if any.
+func (f *FanOut) Err() error {
+	select {
+	case <-f.failed:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// run publishes the queued messages to the sink, in order.
+func (f *FanOut) run(i int, publisher Publisher, queue <-chan fanOutMessage) {
+	defer f.wg.Done()
+
+	for msg := range queue {
+		if f.opts.Strict && f.Err() != nil {
+			continue
+		}
+
+		err := publisher.Publish(msg.ctx, msg.topic, msg.key, msg.value)
+
+		switch {
+		case err == nil:
+		case f.opts.Strict:
+			f.fail(fmt.Errorf("sink %d: %w", i, err))
+		case f.opts.OnError != nil:
+			f.opts.OnError(i, err)
+		}
+	}
+}
+
+// fail fails the fan-out with the error, only the first failure is retained.
+func (f *FanOut) fail(err error) {
+	f.once.Do(func() {
+		f.err = err
+		close(f.failed)
+
+		if f.opts.OnFail != nil {
+			f.opts.OnFail(err)
+		}
+	})
+}
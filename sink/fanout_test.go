@@ -0,0 +1,108 @@
+package sink_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+	"github.com/rifaideen/talkative/sink"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is a publisher recording the sequence numbers of the published messages, after an optional delay.
+type recorder struct {
+	mu        sync.Mutex
+	delay     time.Duration
+	err       error
+	sequences []int
+}
+
+func (r *recorder) Publish(ctx context.Context, topic string, key, value []byte) error {
+	time.Sleep(r.delay)
+
+	var msg sink.Message
+
+	json.Unmarshal(value, &msg)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sequences = append(r.sequences, msg.Sequence)
+
+	return r.err
+}
+
+// TestFanOut tests every sink receives the chunks in the same order, whatever its speed.
+func TestFanOut(t *testing.T) {
+	fast, slow := &recorder{}, &recorder{delay: time.Millisecond}
+	fanout := sink.NewFanOut(sink.FanOutOptions{Buffer: 4}, fast, slow)
+
+	cb := sink.Chat(fanout, sink.Options{Topic: "chat", Mode: sink.CHUNKS}, nil)
+
+	for i := 0; i < 20; i++ {
+		cb(&talkative.ChatResponse{Message: talkative.ChatMessage{Content: "x"}, Done: i == 19}, nil)
+	}
+
+	assert.NoError(t, fanout.Close())
+
+	expected := make([]int, 20)
+
+	for i := range expected {
+		expected[i] = i
+	}
+
+	assert.Equal(t, expected, fast.sequences)
+	assert.Equal(t, expected, slow.sequences)
+	assert.ErrorIs(t, fanout.Publish(context.Background(), "chat", nil, nil), sink.ErrClosed)
+}
+
+// TestFanOutStrict tests failing the whole fan-out when a sink falls behind or fails.
+func TestFanOutStrict(t *testing.T) {
+	var failure error
+
+	stuck := &recorder{delay: 100 * time.Millisecond}
+	fanout := sink.NewFanOut(sink.FanOutOptions{
+		Buffer: 1,
+		Strict: true,
+		MaxLag: 10 * time.Millisecond,
+		OnFail: func(err error) { failure = err },
+	}, &recorder{}, stuck)
+
+	var err error
+
+	for i := 0; i < 5 && err == nil; i++ {
+		err = fanout.Publish(context.Background(), "chat", nil, []byte(`{}`))
+	}
+
+	assert.ErrorIs(t, err, sink.ErrSinkBehind)
+	assert.ErrorIs(t, failure, sink.ErrSinkBehind)
+	assert.ErrorIs(t, fanout.Publish(context.Background(), "chat", nil, nil), sink.ErrSinkBehind)
+	assert.ErrorIs(t, fanout.Close(), sink.ErrSinkBehind)
+
+	broken := &recorder{err: errors.New("disk full")}
+	fanout = sink.NewFanOut(sink.FanOutOptions{Strict: true}, &recorder{}, broken)
+
+	fanout.Publish(context.Background(), "chat", nil, []byte(`{}`))
+	assert.EqualError(t, fanout.Close(), "sink 1: disk full")
+}
+
+// TestFanOutNonStrict tests reporting the errors of a sink without failing the others.
+func TestFanOutNonStrict(t *testing.T) {
+	failed := []int{}
+	healthy, broken := &recorder{}, &recorder{err: errors.New("unavailable")}
+
+	fanout := sink.NewFanOut(sink.FanOutOptions{OnError: func(sink int, err error) { failed = append(failed, sink) }}, healthy, broken)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, fanout.Publish(context.Background(), "chat", nil, []byte(`{}`)))
+	}
+
+	assert.NoError(t, fanout.Close())
+	assert.Len(t, healthy.sequences, 3)
+	assert.Equal(t, []int{1, 1, 1}, failed)
+}
@@ -0,0 +1,67 @@
+// Package kafka publishes streamed model output to Kafka topics.
+//
+// It does not depend on any Kafka client library. The Writer interface is a single method which is
+// easily implemented on top of the client of your choice, i.e: with github.com/segmentio/kafka-go:
+//
+//	w := &kafkago.Writer{Addr: kafkago.TCP("localhost:9092")}
+//	writer := kafkasink.WriterFunc(func(ctx context.Context, msg kafkasink.Message) error {
+//		return w.WriteMessages(ctx, kafkago.Message{Topic: msg.Topic, Key: msg.Key, Value: msg.Value, Headers: ...})
+//	})
+//	cb := sink.Chat(kafkasink.New(writer), sink.Options{Topic: "chat-output", Key: conversationID}, nil)
+package kafka
+
+import (
+	"context"
+
+	"github.com/rifaideen/talkative/sink"
+)
+
+// Message is a Kafka record to be written.
+type Message struct {
+	Topic   string            // The topic to write to.
+	Key     []byte            // The record key, stream keys keep the chunks of a stream on the same partition.
+	Value   []byte            // The JSON encoded sink.Message.
+	Headers map[string]string // The record headers.
+}
+
+// Writer writes records to Kafka.
+type Writer interface {
+	WriteMessage(ctx context.Context, msg Message) error
+}
+
+// WriterFunc is an adapter allowing the use of ordinary functions as writers.
+type WriterFunc func(ctx context.Context, msg Message) error
+
+// WriteMessage calls f(ctx, msg).
+func (f WriterFunc) WriteMessage(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+// publisher publishes to Kafka topics.
+type publisher struct {
+	writer  Writer
+	headers map[string]string
+}
+
+// New creates a sink.Publisher writing records through the writer.
+//
+// The optional headers are attached to every record, i.e: to identify the producing service.
+func New(writer Writer, headers ...map[string]string) sink.Publisher {
+	p := &publisher{writer: writer}
+
+	if len(headers) > 0 {
+		p.headers = headers[0]
+	}
+
+	return p
+}
+
+// Publish writes the value as a record with the given key to the topic.
+func (p *publisher) Publish(ctx context.Context, topic string, key, value []byte) error {
+	return p.writer.WriteMessage(ctx, Message{
+		Topic:   topic,
+		Key:     key,
+		Value:   value,
+		Headers: p.headers,
+	})
+}
@@ -0,0 +1,82 @@
+package kafka_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+	"github.com/rifaideen/talkative/sink"
+	"github.com/rifaideen/talkative/sink/kafka"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writer is a fake Kafka writer recording the written records, failing with err when set.
+type writer struct {
+	records []kafka.Message
+	err     error
+}
+
+func (w *writer) WriteMessage(ctx context.Context, msg kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	w.records = append(w.records, msg)
+
+	return nil
+}
+
+// TestPublish tests writing records with the topic, key, value and headers of the published messages.
+func TestPublish(t *testing.T) {
+	w := &writer{}
+	headers := map[string]string{"service": "assistant"}
+
+	cb := sink.Chat(kafka.New(w, headers), sink.Options{Topic: "chat-output", Key: "conversation-1"}, nil)
+
+	cb(&talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}}, nil)
+	cb(&talkative.ChatResponse{Message: talkative.ChatMessage{Content: "!"}, Done: true}, nil)
+
+	assert.Len(t, w.records, 3)
+
+	for i, record := range w.records {
+		var msg sink.Message
+
+		assert.NoError(t, json.Unmarshal(record.Value, &msg))
+		assert.Equal(t, "chat-output", record.Topic)
+		assert.Equal(t, []byte("conversation-1"), record.Key)
+		assert.Equal(t, headers, record.Headers)
+		assert.Equal(t, "conversation-1", msg.Key)
+		assert.Equal(t, i, msg.Sequence)
+	}
+
+	var (
+		final    sink.Message
+		response talkative.ChatResponse
+	)
+
+	json.Unmarshal(w.records[2].Value, &final)
+	json.Unmarshal(final.Response, &response)
+
+	assert.True(t, final.Final)
+	assert.Equal(t, "Hello!", final.Content)
+	assert.Equal(t, "!", response.Message.Content)
+}
+
+// TestPublishError tests that the errors of the writer are returned by Publish and reported to OnError.
+func TestPublishError(t *testing.T) {
+	failure := errors.New("broker unavailable")
+	publisher := kafka.New(&writer{err: failure})
+
+	assert.ErrorIs(t, publisher.Publish(context.Background(), "chat-output", nil, []byte("{}")), failure)
+
+	reported := []error{}
+	cb := sink.Chat(publisher, sink.Options{Topic: "chat-output", OnError: func(err error) { reported = append(reported, err) }}, nil)
+
+	cb(&talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}, Done: true}, nil)
+
+	assert.Len(t, reported, 2)
+	assert.ErrorIs(t, reported[0], failure)
+}
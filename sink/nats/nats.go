@@ -0,0 +1,40 @@
+// Package nats publishes streamed model output to NATS subjects.
+//
+// It does not depend on the NATS client library, the Conn interface is satisfied by *nats.Conn
+// from github.com/nats-io/nats.go:
+//
+//	nc, _ := nats.Connect(nats.DefaultURL)
+//	cb := sink.Chat(natssink.New(nc), sink.Options{Topic: "chat.output"}, nil)
+package nats
+
+import (
+	"context"
+
+	"github.com/rifaideen/talkative/sink"
+)
+
+// Conn is the subset of *nats.Conn used to publish messages.
+type Conn interface {
+	Publish(subject string, data []byte) error
+}
+
+// publisher publishes to NATS subjects, topics are used as subjects.
+type publisher struct {
+	conn Conn
+}
+
+// New creates a sink.Publisher publishing through the NATS connection.
+//
+// NATS messages have no key, the stream key is part of the published sink.Message instead.
+func New(conn Conn) sink.Publisher {
+	return &publisher{conn: conn}
+}
+
+// Publish publishes the value to the subject named by topic.
+func (p *publisher) Publish(ctx context.Context, topic string, key, value []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return p.conn.Publish(topic, value)
+}
@@ -0,0 +1,80 @@
+package nats_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+	"github.com/rifaideen/talkative/sink"
+	"github.com/rifaideen/talkative/sink/nats"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// conn is a fake NATS connection recording the published messages, failing with err when set.
+type conn struct {
+	subjects []string
+	payloads [][]byte
+	err      error
+}
+
+func (c *conn) Publish(subject string, data []byte) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	c.subjects = append(c.subjects, subject)
+	c.payloads = append(c.payloads, data)
+
+	return nil
+}
+
+// TestPublish tests publishing the messages to the subject named by the topic, carrying the stream key.
+func TestPublish(t *testing.T) {
+	nc := &conn{}
+
+	cb := sink.Completion(nats.New(nc), sink.Options{Topic: "completions.job-1", Key: "job-1", Mode: sink.CHUNKS}, nil)
+
+	cb(&talkative.CompletionResponse{Response: "Par"}, nil)
+	cb(&talkative.CompletionResponse{Response: "is", Done: true}, nil)
+
+	assert.Equal(t, []string{"completions.job-1", "completions.job-1"}, nc.subjects)
+
+	for i, payload := range nc.payloads {
+		var (
+			msg      sink.Message
+			response talkative.CompletionResponse
+		)
+
+		assert.NoError(t, json.Unmarshal(payload, &msg))
+		assert.NoError(t, json.Unmarshal(msg.Response, &response))
+
+		assert.Equal(t, "job-1", msg.Key)
+		assert.Equal(t, i, msg.Sequence)
+		assert.Equal(t, response.Response, msg.Content)
+		assert.Equal(t, response.Done, msg.Done)
+		assert.False(t, msg.Final)
+	}
+}
+
+// TestPublishError tests that the errors of the connection and cancelled contexts are returned by Publish.
+func TestPublishError(t *testing.T) {
+	failure := errors.New("connection closed")
+
+	assert.ErrorIs(t, nats.New(&conn{err: failure}).Publish(context.Background(), "chat.output", nil, []byte("{}")), failure)
+
+	nc := &conn{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	reported := []error{}
+	cb := sink.Chat(nats.New(nc), sink.Options{Topic: "chat.output", Context: ctx, OnError: func(err error) { reported = append(reported, err) }}, nil)
+
+	cb(&talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}, Done: true}, nil)
+
+	assert.Empty(t, nc.subjects)
+	assert.Len(t, reported, 2)
+	assert.ErrorIs(t, reported[0], context.Canceled)
+}
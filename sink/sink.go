@@ -0,0 +1,179 @@
+// Package sink publishes streamed model output to message buses.
+//
+// The adapters in this package wrap the chat and completion callbacks of talkative and publish every
+// chunk and/or the final aggregated result through a Publisher. Broker specific publishers live in
+// subpackages (nats, kafka) and only depend on small interfaces satisfied by the respective client
+// libraries, so this module does not pull in any broker dependency.
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/rifaideen/talkative"
+)
+
+// Publisher publishes a message to a topic of a message bus.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, key, value []byte) error
+}
+
+// PublisherFunc is an adapter allowing the use of ordinary functions as publishers.
+type PublisherFunc func(ctx context.Context, topic string, key, value []byte) error
+
+// Publish calls f(ctx, topic, key, value).
+func (f PublisherFunc) Publish(ctx context.Context, topic string, key, value []byte) error {
+	return f(ctx, topic, key, value)
+}
+
+// Define an enum-like type to represent what is published to the sink.
+type Mode int
+
+const (
+	// Publish every streamed chunk.
+	CHUNKS Mode = 1 << iota
+
+	// Publish the aggregated result once the stream is done.
+	FINAL
+
+	// Publish every streamed chunk as well as the aggregated result.
+	ALL = CHUNKS | FINAL
+)
+
+// Message is the JSON payload published to the sink.
+type Message struct {
+	Key      string          `json:"key,omitempty"` // The key identifying the stream, i.e: a conversation ID.
+	Sequence int             `json:"sequence"`      // Sequence number of the chunk within the stream, starting from 0.
+	Content  string          `json:"content"`       // The chunk content, or the aggregated content for the final message.
+	Done     bool            `json:"done"`          // Whether this chunk is the last one of the stream.
+	Final    bool            `json:"final"`         // Whether this message holds the aggregated result.
+	Response json.RawMessage `json:"response"`      // The raw response object as received from the server.
+}
+
+// Options configures the adapters.
+type Options struct {
+	Topic   string          // The topic (or subject) messages are published to.
+	Key     string          // The key of the stream, used as message key for partitioning.
+	Mode    Mode            // What is published, defaults to ALL.
+	Context context.Context // The context used for publishing, defaults to context.Background().
+	OnError func(err error) // Invoked when publishing fails. (Optional)
+}
+
+// Chat wraps the callback so chat responses are published to the sink before being handed to next.
+//
+// The next callback is optional and may be nil.
+func Chat(p Publisher, opts Options, next talkative.ChatCallBack) talkative.ChatCallBack {
+	s := newStream(p, opts)
+
+	return func(cr *talkative.ChatResponse, err error) {
+		if err == nil {
+			s.publish(cr, cr.Message.Content, cr.Done)
+		}
+
+		if next != nil {
+			next(cr, err)
+		}
+	}
+}
+
+// Completion wraps the callback so completion responses are published to the sink before being handed to next.
+//
+// The next callback is optional and may be nil.
+func Completion(p Publisher, opts Options, next talkative.CompletionCallback) talkative.CompletionCallback {
+	s := newStream(p, opts)
+
+	return func(cr *talkative.CompletionResponse, err error) {
+		if err == nil {
+			s.publish(cr, cr.Response, cr.Done)
+		}
+
+		if next != nil {
+			next(cr, err)
+		}
+	}
+}
+
+// stream holds the publishing state of a single stream.
+type stream struct {
+	mu        sync.Mutex
+	publisher Publisher
+	opts      Options
+	sequence  int
+	content   strings.Builder
+}
+
+// newStream creates the publishing state of a stream, applying the defaults of the options.
+func newStream(p Publisher, opts Options) *stream {
+	if opts.Mode == 0 {
+		opts.Mode = ALL
+	}
+
+	if opts.Context == nil {
+		opts.Context = context.Background()
+	}
+
+	return &stream{publisher: p, opts: opts}
+}
+
+// publish publishes the chunk and, when done, the aggregated result according to the mode.
+func (s *stream) publish(response any, content string, done bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, err := json.Marshal(response)
+
+	if err != nil {
+		s.fail(err)
+
+		return
+	}
+
+	s.content.WriteString(content)
+
+	if s.opts.Mode&CHUNKS != 0 {
+		s.send(Message{
+			Key:      s.opts.Key,
+			Sequence: s.sequence,
+			Content:  content,
+			Done:     done,
+			Response: raw,
+		})
+	}
+
+	s.sequence++
+
+	if done && s.opts.Mode&FINAL != 0 {
+		s.send(Message{
+			Key:      s.opts.Key,
+			Sequence: s.sequence,
+			Content:  s.content.String(),
+			Done:     true,
+			Final:    true,
+			Response: raw,
+		})
+	}
+}
+
+// send encodes and publishes a single message.
+func (s *stream) send(msg Message) {
+	value, err := json.Marshal(msg)
+
+	if err != nil {
+		s.fail(err)
+
+		return
+	}
+
+	if err := s.publisher.Publish(s.opts.Context, s.opts.Topic, []byte(s.opts.Key), value); err != nil {
+		s.fail(err)
+	}
+}
+
+// fail reports the error to the configured error handler.
+func (s *stream) fail(err error) {
+	if s.opts.OnError != nil {
+		s.opts.OnError(err)
+	}
+}
@@ -0,0 +1,79 @@
+package sink_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+	"github.com/rifaideen/talkative/sink"
+	"github.com/rifaideen/talkative/sink/kafka"
+	"github.com/rifaideen/talkative/sink/nats"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// conn is a fake NATS connection recording the published messages.
+type conn struct {
+	subjects []string
+	messages []sink.Message
+}
+
+func (c *conn) Publish(subject string, data []byte) error {
+	var msg sink.Message
+
+	json.Unmarshal(data, &msg)
+
+	c.subjects = append(c.subjects, subject)
+	c.messages = append(c.messages, msg)
+
+	return nil
+}
+
+// TestChat tests publishing of chat chunks and the final result through the NATS adapter.
+func TestChat(t *testing.T) {
+	nc := &conn{}
+	received := ""
+
+	cb := sink.Chat(nats.New(nc), sink.Options{Topic: "chat.output", Key: "conversation-1"}, func(cr *talkative.ChatResponse, err error) {
+		received += cr.Message.Content
+	})
+
+	cb(&talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}}, nil)
+	cb(&talkative.ChatResponse{Message: talkative.ChatMessage{Content: "!"}, Done: true}, nil)
+
+	assert.Equal(t, "Hello!", received)
+	assert.Equal(t, []string{"chat.output", "chat.output", "chat.output"}, nc.subjects)
+	assert.Equal(t, "Hello", nc.messages[0].Content)
+	assert.Equal(t, 1, nc.messages[1].Sequence)
+	assert.True(t, nc.messages[1].Done)
+	assert.True(t, nc.messages[2].Final)
+	assert.Equal(t, "Hello!", nc.messages[2].Content)
+	assert.Equal(t, "conversation-1", nc.messages[2].Key)
+}
+
+// TestCompletion tests publishing of the final completion result only through the Kafka adapter.
+func TestCompletion(t *testing.T) {
+	records := []kafka.Message{}
+	writer := kafka.WriterFunc(func(ctx context.Context, msg kafka.Message) error {
+		records = append(records, msg)
+
+		return nil
+	})
+
+	cb := sink.Completion(kafka.New(writer), sink.Options{Topic: "completions", Key: "job-1", Mode: sink.FINAL}, nil)
+
+	cb(&talkative.CompletionResponse{Response: "Par"}, nil)
+	cb(&talkative.CompletionResponse{Response: "is", Done: true}, nil)
+
+	assert.Len(t, records, 1)
+	assert.Equal(t, "completions", records[0].Topic)
+	assert.Equal(t, []byte("job-1"), records[0].Key)
+
+	var msg sink.Message
+
+	json.Unmarshal(records[0].Value, &msg)
+
+	assert.Equal(t, "Paris", msg.Content)
+	assert.True(t, msg.Final)
+}
@@ -0,0 +1,182 @@
+package talkative
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+const (
+	// DEFAULT_NUM_CTX is the context length Ollama uses when none is requested.
+	DEFAULT_NUM_CTX = 2048
+
+	// DEFAULT_CONTEXT_RESERVE is the number of tokens reserved for the response when num_predict is not set.
+	DEFAULT_CONTEXT_RESERVE = 1024
+)
+
+// ContextSizing configures WithContextSizing.
+type ContextSizing struct {
+	Min     int // The context length below which num_ctx is left unset, defaults to DEFAULT_NUM_CTX.
+	Reserve int // The tokens reserved for the response when num_predict is not set, defaults to DEFAULT_CONTEXT_RESERVE.
+}
+
+// contextSizing sets num_ctx of the requests of a client from the size of their prompt.
+type contextSizing struct {
+	config ContextSizing
+
+	mu      sync.Mutex
+	lengths map[string]int // The maximum context length per model, as reported by /api/show.
+}
+
+// WithContextSizing sets the num_ctx option of every chat and completion from the estimated size of its
+// prompt, so long prompts are no longer silently truncated to the default context length of the server.
+//
+// The context length is the estimated tokens of the messages, or the system prompt and prompt, plus the
+// tokens reserved for the response, rounded up to the next power of two of Min to limit the reloads of the
+// model, which happen whenever num_ctx changes. It is bounded by the context length of the model, looked up
+// once per model with ShowModel. Requests fitting in Min or setting num_ctx themselves are left untouched.
+func WithContextSizing(sizing ContextSizing) Option {
+	return func(c *Client) {
+		if sizing.Min <= 0 {
+			sizing.Min = DEFAULT_NUM_CTX
+		}
+
+		if sizing.Reserve <= 0 {
+			sizing.Reserve = DEFAULT_CONTEXT_RESERVE
+		}
+
+		c.sizing = &contextSizing{config: sizing}
+	}
+}
+
+// sizeChat sets num_ctx of the chat request, see WithContextSizing.
+func (s *contextSizing) sizeChat(ctx context.Context, c *Client, request *ChatRequest) {
+	tokens := 0
+
+	for _, msg := range request.Messages {
+		tokens += EstimateTokens(msg.Content)
+	}
+
+	var options map[string]interface{}
+
+	if request.ChatParams != nil {
+		options = request.Options
+	}
+
+	if size, ok := s.size(ctx, c, request.Model, tokens, options); ok {
+		params := ChatParams{}
+
+		if request.ChatParams != nil {
+			params = *request.ChatParams
+		}
+
+		params.Options = withNumCtx(options, size)
+		request.ChatParams = &params
+	}
+}
+
+// sizeCompletion sets num_ctx of the completion request, see WithContextSizing.
+func (s *contextSizing) sizeCompletion(ctx context.Context, c *Client, request *CompletionRequest) {
+	tokens := EstimateTokens(request.Prompt)
+
+	var options map[string]interface{}
+
+	if request.CompletionParams != nil {
+		tokens += EstimateTokens(request.System)
+		options = request.Options
+	}
+
+	if size, ok := s.size(ctx, c, request.Model, tokens, options); ok {
+		params := CompletionParams{}
+
+		if request.CompletionParams != nil {
+			params = *request.CompletionParams
+		}
+
+		params.Options = withNumCtx(options, size)
+		request.CompletionParams = &params
+	}
+}
+
+// size returns the context length of a prompt of the given tokens, reporting false when num_ctx must be left unset.
+func (s *contextSizing) size(ctx context.Context, c *Client, model string, tokens int, options map[string]interface{}) (int, bool) {
+	if _, ok := options["num_ctx"]; ok {
+		return 0, false
+	}
+
+	reserve := s.config.Reserve
+
+	switch predict := options["num_predict"].(type) {
+	case int:
+		if predict > 0 {
+			reserve = predict
+		}
+	case float64:
+		if predict > 0 {
+			reserve = int(predict)
+		}
+	}
+
+	needed := tokens + reserve
+
+	if needed <= s.config.Min {
+		return 0, false
+	}
+
+	size := s.config.Min
+
+	for size < needed {
+		size *= 2
+	}
+
+	if length := s.length(ctx, c, model); length > 0 {
+		size = min(size, length)
+	}
+
+	return size, size > s.config.Min
+}
+
+// length returns the maximum context length of the model, 0 when it is unknown.
+func (s *contextSizing) length(ctx context.Context, c *Client, model string) int {
+	s.mu.Lock()
+	length, ok := s.lengths[model]
+	s.mu.Unlock()
+
+	if ok {
+		return length
+	}
+
+	show, err := c.ShowModelContext(ctx, model)
+
+	// Failed lookups are retried by the next request.
+	if err != nil {
+		return 0
+	}
+
+	length = show.ContextLength()
+
+	s.mu.Lock()
+
+	if s.lengths == nil {
+		s.lengths = map[string]int{}
+	}
+
+	s.lengths[model] = length
+
+	s.mu.Unlock()
+
+	return length
+}
+
+// withNumCtx returns a copy of the options with num_ctx set, leaving the options of the caller untouched.
+func withNumCtx(options map[string]interface{}, size int) map[string]interface{} {
+	options = maps.Clone(options)
+
+	if options == nil {
+		options = map[string]interface{}{}
+	}
+
+	options["num_ctx"] = size
+
+	return options
+}
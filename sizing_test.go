@@ -0,0 +1,83 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestContextSizing tests sizing the context of requests from their prompt, bounded by the context length of the model.
+func TestContextSizing(t *testing.T) {
+	var shows atomic.Int32
+
+	options := make(chan map[string]interface{}, 1)
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/show":
+			shows.Add(1)
+			json.NewEncoder(w).Encode(talkative.ShowResponse{ModelInfo: map[string]any{"llama.context_length": 8192}})
+		case "/api/chat":
+			var request talkative.ChatRequest
+
+			json.NewDecoder(r.Body).Decode(&request)
+
+			if request.ChatParams != nil {
+				options <- request.Options
+			} else {
+				options <- nil
+			}
+
+			json.NewEncoder(w).Encode(talkative.ChatResponse{Done: true})
+		case "/api/generate":
+			var request talkative.CompletionRequest
+
+			json.NewDecoder(r.Body).Decode(&request)
+			options <- request.Options
+
+			json.NewEncoder(w).Encode(talkative.CompletionResponse{Done: true})
+		}
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithContextSizing(talkative.ContextSizing{}))
+	cb := func(cr *talkative.ChatResponse, err error) {}
+
+	chat := func(params *talkative.ChatParams, content string) map[string]interface{} {
+		done, err := client.Chat("", cb, params, talkative.ChatMessage{Role: talkative.USER, Content: content})
+
+		assert.NoError(t, err)
+		<-done
+
+		return <-options
+	}
+
+	assert.Nil(t, chat(nil, "Hi"))
+	assert.Equal(t, float64(4096), chat(nil, strings.Repeat("x", 10000))["num_ctx"])
+	assert.Equal(t, float64(8192), chat(nil, strings.Repeat("x", 40000))["num_ctx"])
+
+	params := &talkative.ChatParams{Options: map[string]interface{}{"num_predict": 4096}}
+	assert.Equal(t, float64(8192), chat(params, "Hi")["num_ctx"])
+	assert.Equal(t, map[string]interface{}{"num_predict": 4096}, params.Options)
+
+	params = &talkative.ChatParams{Options: map[string]interface{}{"num_ctx": 1024}}
+	assert.Equal(t, float64(1024), chat(params, strings.Repeat("x", 40000))["num_ctx"])
+
+	done, err := client.Completion("", func(cr *talkative.CompletionResponse, err error) {}, &talkative.CompletionMessage{
+		Prompt:           strings.Repeat("x", 10000),
+		CompletionParams: &talkative.CompletionParams{System: strings.Repeat("x", 4000)},
+	})
+	{
+		assert.NoError(t, err)
+		<-done
+		assert.Equal(t, float64(8192), (<-options)["num_ctx"])
+	}
+
+	assert.Equal(t, int32(1), shows.Load())
+}
@@ -0,0 +1,76 @@
+package talkative
+
+import "time"
+
+// WithClockSkewTolerance validates the creation time of the streamed chat and completion responses against
+// the local clock, i.e: to protect downstream ordering from misconfigured hosts producing future-dated chunks.
+//
+// Responses whose creation time is off by more than the tolerance, or invalid, get the time they were received
+// at instead, and EVENT_CLOCK_SKEW is emitted once per request. A tolerance of 0 disables the validation.
+func WithClockSkewTolerance(tolerance time.Duration) Option {
+	return func(c *Client) {
+		c.skewTolerance = tolerance
+	}
+}
+
+// timestamped is implemented by the responses carrying the time they were created on the server.
+type timestamped interface {
+	created() (time.Time, bool)
+	normalize(received time.Time)
+}
+
+// created returns the creation time of the response, and whether it is set.
+func (r *ChatResponse) created() (time.Time, bool) {
+	return r.CreatedAt, !r.CreatedAt.IsZero()
+}
+
+// normalize replaces the creation time of the response.
+func (r *ChatResponse) normalize(received time.Time) {
+	r.CreatedAt = received
+}
+
+// created returns the creation time of the response, and whether it is valid.
+func (r *CompletionResponse) created() (time.Time, bool) {
+	created, err := time.Parse(time.RFC3339Nano, r.CreatedAt)
+
+	return created, err == nil
+}
+
+// normalize replaces the creation time of the response.
+func (r *CompletionResponse) normalize(received time.Time) {
+	r.CreatedAt = received.Format(time.RFC3339Nano)
+}
+
+// deskew wraps the callback so the creation time of the responses off by more than the tolerance is normalized.
+func deskew[T any](l *lifecycle, tolerance time.Duration, cb func(T, error)) func(T, error) {
+	if tolerance <= 0 {
+		return cb
+	}
+
+	flagged := false
+
+	return func(response T, err error) {
+		if r, ok := any(response).(timestamped); ok && err == nil {
+			received := time.Now()
+			created, valid := r.created()
+
+			if skew := created.Sub(received); !valid || skew > tolerance || skew < -tolerance {
+				r.normalize(received)
+
+				if !flagged {
+					flagged = true
+
+					event := l.event(EVENT_CLOCK_SKEW, nil)
+
+					if valid {
+						event.Skew = skew
+					}
+
+					l.client.events.emit(event)
+				}
+			}
+		}
+
+		cb(response, err)
+	}
+}
@@ -0,0 +1,74 @@
+package talkative_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithClockSkewTolerance tests normalizing and flagging future-dated chunks.
+func TestWithClockSkewTolerance(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}, CreatedAt: future},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "!"}, CreatedAt: future},
+		talkative.ChatResponse{CreatedAt: time.Now(), Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithClockSkewTolerance(time.Minute))
+	metrics := talkative.NewMetrics()
+	metrics.Observe(client)
+
+	events := []talkative.Event{}
+	client.Subscribe(func(e talkative.Event) { events = append(events, e) }, talkative.EVENT_CLOCK_SKEW)
+
+	start := time.Now()
+	created := []time.Time{}
+
+	done, err := client.Chat("llama2", func(cr *talkative.ChatResponse, err error) {
+		assert.NoError(t, err)
+		created = append(created, cr.CreatedAt)
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+
+	assert.NoError(t, err)
+	<-done
+
+	for _, at := range created {
+		assert.WithinDuration(t, start, at, time.Minute)
+	}
+
+	if assert.Len(t, events, 1) {
+		assert.InDelta(t, time.Hour, events[0].Skew, float64(time.Minute))
+	}
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, nil)
+	assert.Contains(t, recorder.Body.String(), `talkative_clock_skew_total{op="chat",model="llama2"} 1`)
+}
+
+// TestWithClockSkewToleranceCompletion tests normalizing invalid creation times of completions.
+func TestWithClockSkewToleranceCompletion(t *testing.T) {
+	server := streamServer(talkative.CompletionResponse{Response: "Hi", CreatedAt: "yesterday", Done: true})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithClockSkewTolerance(time.Minute))
+
+	var createdAt string
+
+	done, err := client.Completion("", func(cr *talkative.CompletionResponse, err error) {
+		createdAt = cr.CreatedAt
+	}, &talkative.CompletionMessage{Prompt: "Hi"})
+
+	assert.NoError(t, err)
+	<-done
+
+	parsed, err := time.Parse(time.RFC3339Nano, createdAt)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), parsed, time.Minute)
+}
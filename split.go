@@ -0,0 +1,192 @@
+package talkative
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Define an enum-like type to represent the strategies processing prompts exceeding the context window.
+type SplitStrategy string
+
+const (
+	// Every pass refines the answer of the previous pass with the next chunk of the prompt.
+	SPLIT_REFINE SplitStrategy = "refine"
+
+	// Every pass answers a chunk of the prompt on its own, the answers are joined with blank lines.
+	SPLIT_ACCUMULATE SplitStrategy = "accumulate"
+)
+
+// DEFAULT_REFINE_PROMPT is the template of the passes of SPLIT_REFINE when none is configured.
+const DEFAULT_REFINE_PROMPT = `{{if .Previous}}Here is the answer so far:
+{{.Previous}}
+
+Refine it with the following continuation of the input, keep it unchanged when nothing is relevant.
+
+{{end}}{{.Chunk}}`
+
+// ErrSplit is returned when a pass of a split prompt fails.
+var ErrSplit = newError("split prompt failed")
+
+// SplitOptions configures CompletionSplit.
+type SplitOptions struct {
+	Strategy SplitStrategy // The strategy of the passes, defaults to SPLIT_REFINE.
+
+	// MaxTokens is the estimated number of tokens of the prompt of a pass. It defaults to the context length
+	// of the model looked up with ShowModel, or DEFAULT_NUM_CTX when unknown, minus DEFAULT_CONTEXT_RESERVE.
+	MaxTokens int
+
+	// Prompt is a text/template rendered for every pass with SplitData, defaults to DEFAULT_REFINE_PROMPT
+	// for SPLIT_REFINE and "{{.Chunk}}" for SPLIT_ACCUMULATE.
+	Prompt string
+
+	// OnPass is called with the answer of every pass. (Optional)
+	OnPass func(data SplitData, answer string)
+}
+
+// SplitData is the data the prompt of a pass is rendered with.
+type SplitData struct {
+	Chunk    string // The chunk of the prompt processed by the pass.
+	Index    int    // The index of the pass, starting from 0.
+	Total    int    // The total number of passes.
+	Previous string // The answer of the previous pass, only set for SPLIT_REFINE.
+}
+
+// CompletionSplit is identical to CompletionContext(), except that prompts exceeding the context window are
+// processed in several passes instead of being truncated, and the aggregated answer is returned.
+//
+// Prompts fitting in opts.MaxTokens are sent as is. Longer prompts are split into chunks at paragraph,
+// line, sentence or word boundaries. With SPLIT_REFINE, which keeps half of the tokens of a pass for the
+// answer so far, the answer of the last pass is returned. With SPLIT_ACCUMULATE, the answers of all passes
+// are joined with blank lines.
+func (c *Client) CompletionSplit(ctx context.Context, model string, msg *CompletionMessage, opts SplitOptions) (string, error) {
+	if msg == nil {
+		return "", ErrMessage
+	}
+
+	if model == "" {
+		model = c.DefaultModel()
+	}
+
+	if opts.Strategy == "" {
+		opts.Strategy = SPLIT_REFINE
+	}
+
+	if opts.Prompt == "" {
+		opts.Prompt = DEFAULT_REFINE_PROMPT
+
+		if opts.Strategy == SPLIT_ACCUMULATE {
+			opts.Prompt = "{{.Chunk}}"
+		}
+	}
+
+	if _, err := DefaultTemplates.Compile(opts.Prompt); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrSplit, err)
+	}
+
+	if opts.MaxTokens <= 0 {
+		opts.MaxTokens = DEFAULT_NUM_CTX
+
+		if show, err := c.ShowModelContext(ctx, c.resolve(model)); err == nil && show.ContextLength() > 0 {
+			opts.MaxTokens = show.ContextLength()
+		}
+
+		opts.MaxTokens = max(opts.MaxTokens-DEFAULT_CONTEXT_RESERVE, 1)
+	}
+
+	budget := opts.MaxTokens
+
+	if msg.CompletionParams != nil {
+		budget -= EstimateTokens(msg.System)
+	}
+
+	if EstimateTokens(msg.Prompt) <= budget {
+		response, err := c.CompletionTo(ctx, io.Discard, model, msg)
+
+		if err != nil {
+			return "", err
+		}
+
+		return response.Response, nil
+	}
+
+	if opts.Strategy == SPLIT_REFINE {
+		budget /= 2
+	}
+
+	chunks := splitText(msg.Prompt, max(budget, 1)*4)
+	answers := make([]string, 0, len(chunks))
+	previous := ""
+
+	for i, chunk := range chunks {
+		data := SplitData{Chunk: chunk, Index: i, Total: len(chunks)}
+
+		if opts.Strategy == SPLIT_REFINE {
+			data.Previous = previous
+		}
+
+		prompt, err := DefaultTemplates.Render(opts.Prompt, data)
+
+		if err != nil {
+			return "", fmt.Errorf("%w: pass %d: %w", ErrSplit, i, err)
+		}
+
+		pass := *msg
+		pass.Prompt = prompt
+
+		response, err := c.CompletionTo(ctx, io.Discard, model, &pass)
+
+		if err != nil {
+			return "", fmt.Errorf("%w: pass %d: %w", ErrSplit, i, err)
+		}
+
+		if opts.OnPass != nil {
+			opts.OnPass(data, response.Response)
+		}
+
+		previous = response.Response
+		answers = append(answers, response.Response)
+	}
+
+	if opts.Strategy == SPLIT_ACCUMULATE {
+		return strings.Join(answers, "\n\n"), nil
+	}
+
+	return previous, nil
+}
+
+// splitText splits the text into chunks of at most size bytes, preferably at paragraph, line, sentence
+// or word boundaries.
+func splitText(text string, size int) []string {
+	var chunks []string
+
+	for len(text) > size {
+		window := text[:size]
+		cut := -1
+
+		for _, separator := range []string{"\n\n", "\n", ". ", " "} {
+			if i := strings.LastIndex(window, separator); i > 0 {
+				cut = i + len(separator)
+
+				break
+			}
+		}
+
+		// Hard cuts never split a multi-byte character.
+		if cut <= 0 {
+			for cut = size; cut > 1 && !utf8.RuneStart(text[cut]); cut-- {
+			}
+		}
+
+		chunks = append(chunks, text[:cut])
+		text = text[cut:]
+	}
+
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+
+	return chunks
+}
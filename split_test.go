@@ -0,0 +1,78 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCompletionSplit tests processing a long prompt in several passes with both strategies.
+func TestCompletionSplit(t *testing.T) {
+	prompts := []string{}
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/generate" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		var request talkative.CompletionRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		prompts = append(prompts, request.Prompt)
+
+		json.NewEncoder(w).Encode(talkative.CompletionResponse{Response: strings.ToUpper(request.Prompt), Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	message := &talkative.CompletionMessage{Prompt: "one two three. four five six.\n\nseven eight nine"}
+
+	answer, err := client.CompletionSplit(context.Background(), "", message, talkative.SplitOptions{
+		Strategy:  talkative.SPLIT_ACCUMULATE,
+		MaxTokens: 5,
+		Prompt:    "<{{.Index}}/{{.Total}}>{{.Chunk}}",
+	})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"<0/3>one two three. ", "<1/3>four five six.\n\n", "<2/3>seven eight nine"}, prompts)
+		assert.Equal(t, "<0/3>ONE TWO THREE. \n\n<1/3>FOUR FIVE SIX.\n\n\n\n<2/3>SEVEN EIGHT NINE", answer)
+	}
+
+	prompts = prompts[:0]
+	passes := 0
+
+	answer, err = client.CompletionSplit(context.Background(), "", message, talkative.SplitOptions{
+		MaxTokens: 10,
+		Prompt:    "{{.Previous}}+{{.Chunk}}",
+		OnPass:    func(data talkative.SplitData, answer string) { passes++ },
+	})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"+one two three. ", "+ONE TWO THREE. +four five six.\n\n", "+ONE TWO THREE. +FOUR FIVE SIX.\n\n+seven eight nine"}, prompts)
+		assert.Equal(t, "+ONE TWO THREE. +FOUR FIVE SIX.\n\n+SEVEN EIGHT NINE", answer)
+		assert.Equal(t, 3, passes)
+	}
+
+	prompts = prompts[:0]
+
+	answer, err = client.CompletionSplit(context.Background(), "", &talkative.CompletionMessage{Prompt: "short"}, talkative.SplitOptions{})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "SHORT", answer)
+		assert.Equal(t, []string{"short"}, prompts)
+	}
+
+	_, err = client.CompletionSplit(context.Background(), "", message, talkative.SplitOptions{MaxTokens: 5, Prompt: "{{.Missing}}"})
+	assert.ErrorIs(t, err, talkative.ErrSplit)
+
+	_, err = client.CompletionSplit(context.Background(), "", nil, talkative.SplitOptions{})
+	assert.ErrorIs(t, err, talkative.ErrMessage)
+}
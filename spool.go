@@ -0,0 +1,204 @@
+package talkative
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+)
+
+// DEFAULT_SPOOL_THRESHOLD is the number of bytes kept in memory before a Spool moves its content to a temporary file.
+const DEFAULT_SPOOL_THRESHOLD int64 = 1 << 20
+
+// ErrSpoolClosed is returned when a closed Spool is written to or read from.
+var ErrSpoolClosed = newError("spool is closed")
+
+// Spool accumulates streamed content in memory and transparently moves it to a temporary file
+// once the accumulated size exceeds the configured threshold.
+//
+// It is meant for workloads producing very large generations (reports, documents) where keeping
+// the whole response in memory is undesirable. A Spool is safe for concurrent use.
+type Spool struct {
+	mu        sync.Mutex
+	threshold int64  // Number of bytes to keep in memory before spilling to disk.
+	dir       string // Directory used for the temporary file, empty for os.TempDir().
+	buf       bytes.Buffer
+	file      *os.File // Temporary file holding the content once spilled.
+	size      int64    // Total number of bytes written.
+	err       error    // First error reported by the stream.
+	closed    bool
+}
+
+// NewSpool creates a new Spool which spills to a temporary file after threshold bytes.
+//
+// When threshold is less than or equal to zero, DEFAULT_SPOOL_THRESHOLD is used.
+// The optional dir argument selects the directory of the temporary file, it defaults to os.TempDir().
+func NewSpool(threshold int64, dir ...string) *Spool {
+	if threshold <= 0 {
+		threshold = DEFAULT_SPOOL_THRESHOLD
+	}
+
+	spool := &Spool{threshold: threshold}
+
+	if len(dir) > 0 {
+		spool.dir = dir[0]
+	}
+
+	return spool
+}
+
+// Write appends p to the spool, moving the content to a temporary file when the threshold is exceeded.
+func (s *Spool) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, ErrSpoolClosed
+	}
+
+	if s.file == nil && s.size+int64(len(p)) > s.threshold {
+		file, err := os.CreateTemp(s.dir, "talkative-spool-*")
+
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := s.buf.WriteTo(file); err != nil {
+			file.Close()
+			os.Remove(file.Name())
+
+			return 0, err
+		}
+
+		s.file = file
+	}
+
+	var (
+		n   int
+		err error
+	)
+
+	if s.file != nil {
+		n, err = s.file.Write(p)
+	} else {
+		n, err = s.buf.Write(p)
+	}
+
+	s.size += int64(n)
+
+	return n, err
+}
+
+// Size returns the total number of bytes written to the spool.
+func (s *Spool) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.size
+}
+
+// Spilled reports whether the content has been moved to a temporary file.
+func (s *Spool) Spilled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file != nil
+}
+
+// Err returns the first error reported to the spool by the streaming callback, if any.
+func (s *Spool) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// Reader returns a new reader over the whole spooled content, starting from the beginning.
+//
+// Each call returns an independent reader, the caller is responsible for closing it.
+// The first error reported by the stream (if any) is returned alongside the reader.
+func (s *Spool) Reader() (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrSpoolClosed
+	}
+
+	if s.file == nil {
+		return io.NopCloser(bytes.NewReader(bytes.Clone(s.buf.Bytes()))), s.err
+	}
+
+	file, err := os.Open(s.file.Name())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return file, s.err
+}
+
+// Close releases the memory buffer and removes the temporary file, if any.
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+	s.buf.Reset()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+
+	if rmErr := os.Remove(s.file.Name()); err == nil {
+		err = rmErr
+	}
+
+	return err
+}
+
+// fail records the first error reported by the stream.
+func (s *Spool) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// ChatCallback returns a ChatCallBack writing the content of every chat response into the spool.
+func (s *Spool) ChatCallback() ChatCallBack {
+	return func(cr *ChatResponse, err error) {
+		if err != nil {
+			s.fail(err)
+
+			return
+		}
+
+		if _, err := io.WriteString(s, cr.Message.Content); err != nil {
+			s.fail(err)
+		}
+	}
+}
+
+// CompletionCallback returns a CompletionCallback writing the content of every completion response into the spool.
+func (s *Spool) CompletionCallback() CompletionCallback {
+	return func(cr *CompletionResponse, err error) {
+		if err != nil {
+			s.fail(err)
+
+			return
+		}
+
+		if _, err := io.WriteString(s, cr.Response); err != nil {
+			s.fail(err)
+		}
+	}
+}
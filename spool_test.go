@@ -0,0 +1,88 @@
+package talkative_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSpool tests the in-memory and spilled behaviour of the Spool.
+func TestSpool(t *testing.T) {
+	t.Run("spool-in-memory", func(t *testing.T) {
+		spool := talkative.NewSpool(16)
+		defer spool.Close()
+
+		io.WriteString(spool, "Hello")
+
+		assert.False(t, spool.Spilled())
+		assert.EqualValues(t, 5, spool.Size())
+
+		reader, err := spool.Reader()
+		{
+			assert.NoError(t, err)
+		}
+
+		data, _ := io.ReadAll(reader)
+		reader.Close()
+
+		assert.Equal(t, "Hello", string(data))
+	})
+
+	t.Run("spool-spilled", func(t *testing.T) {
+		spool := talkative.NewSpool(8, t.TempDir())
+
+		io.WriteString(spool, "Hello")
+		io.WriteString(spool, ", It is nice talking to you.")
+
+		assert.True(t, spool.Spilled())
+
+		reader, err := spool.Reader()
+		{
+			assert.NoError(t, err)
+		}
+
+		data, _ := io.ReadAll(reader)
+		reader.Close()
+
+		assert.Equal(t, "Hello, It is nice talking to you.", string(data))
+		assert.NoError(t, spool.Close())
+
+		_, err = spool.Reader()
+		assert.ErrorIs(t, err, talkative.ErrSpoolClosed)
+	})
+}
+
+// TestSpoolChat tests spooling of a streamed chat response.
+func TestSpoolChat(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: strings.Repeat("a", 10)}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: strings.Repeat("b", 10)}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	spool := talkative.NewSpool(15, t.TempDir())
+	defer spool.Close()
+
+	done, err := client.Chat("", spool.ChatCallback(), nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+	}
+
+	<-done
+
+	reader, err := spool.Reader()
+	{
+		assert.NoError(t, err)
+		assert.True(t, spool.Spilled())
+	}
+
+	data, _ := io.ReadAll(reader)
+	reader.Close()
+
+	assert.Equal(t, strings.Repeat("a", 10)+strings.Repeat("b", 10), string(data))
+}
@@ -0,0 +1,87 @@
+package talkative
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// ErrStreamStalled is the error of generations which produced no chunk for longer than the stall timeout.
+var ErrStreamStalled = newError("stream stalled")
+
+// WithStallTimeout aborts chat and completion streams producing no chunk for longer than the timeout, i.e:
+// a hung Ollama worker, closing their body. Their callback receives an error matching ErrStreamStalled.
+//
+// Unlike WithStreamIdleTimeout, which watches the bytes of every response, it watches the decoded chunks of
+// generations, so a server trickling keep-alive bytes or partial lines is detected too. The time spent in
+// the callback is not counted, a slow consumer doesn't stall the stream.
+func WithStallTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.stallTimeout = timeout
+	}
+}
+
+// stallBody is a response body closed when no chunk is decoded from it for longer than its timeout.
+type stallBody struct {
+	body    io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	stalled atomic.Bool
+}
+
+// watch replaces the body of the response with a stallBody when the client has a stall timeout, which is
+// returned to be passed to stall(). It returns nil otherwise.
+func (c *Client) watch(res *http.Response) *stallBody {
+	if c.stallTimeout <= 0 {
+		return nil
+	}
+
+	b := &stallBody{body: res.Body, timeout: c.stallTimeout}
+
+	b.timer = time.AfterFunc(b.timeout, func() {
+		b.stalled.Store(true)
+		b.body.Close()
+	})
+
+	res.Body = b
+
+	return b
+}
+
+// Read reads from the body, reporting ErrStreamStalled once the body was closed for being stalled.
+func (b *stallBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+
+	if err != nil && b.stalled.Load() {
+		return n, fmt.Errorf("%w: no chunk for %s", ErrStreamStalled, b.timeout)
+	}
+
+	return n, err
+}
+
+// Close stops the stall timer and closes the body.
+func (b *stallBody) Close() error {
+	b.timer.Stop()
+
+	return b.body.Close()
+}
+
+// stall wraps the callback so the stall timer of the body restarts with every chunk, it is paused while the
+// callback runs. The callback is returned as is when the body is not watched.
+func stall[T any](b *stallBody, cb func(T, error)) func(T, error) {
+	if b == nil {
+		return cb
+	}
+
+	return func(response T, err error) {
+		running := b.timer.Stop()
+
+		cb(response, err)
+
+		if running && err == nil {
+			b.timer.Reset(b.timeout)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithStallTimeout tests aborting generations trickling bytes without producing chunks.
+func TestWithStallTimeout(t *testing.T) {
+	release := make(chan struct{})
+
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(talkative.CompletionResponse{Response: "Hello"})
+		w.(http.Flusher).Flush()
+
+		// Whitespace keeps the connection busy without ever completing a chunk.
+		for {
+			select {
+			case <-release:
+				return
+			case <-time.After(10 * time.Millisecond):
+				w.Write([]byte(" "))
+				w.(http.Flusher).Flush()
+			}
+		}
+	})
+	defer server.Close()
+	defer close(release)
+
+	client, _ := talkative.New(server.URL, talkative.WithStreamIdleTimeout(time.Second), talkative.WithStallTimeout(100*time.Millisecond))
+
+	var (
+		content   string
+		streamErr error
+	)
+
+	done, err := client.Completion("", func(cr *talkative.CompletionResponse, err error) {
+		if err != nil {
+			streamErr = err
+
+			return
+		}
+
+		content += cr.Response
+
+		// The time spent in the callback doesn't count towards the stall timeout.
+		time.Sleep(150 * time.Millisecond)
+	}, &talkative.CompletionMessage{Prompt: "Hi"})
+
+	assert.NoError(t, err)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream was not aborted")
+	}
+
+	assert.Equal(t, "Hello", content)
+	assert.ErrorIs(t, streamErr, talkative.ErrStreamStalled)
+}
+
+// TestWithStallTimeoutCompleted tests generations producing chunks in time are not aborted.
+func TestWithStallTimeoutCompleted(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "!"}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithStallTimeout(time.Second))
+	content := ""
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {
+		assert.NoError(t, err)
+
+		content += cr.Message.Content
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+
+	assert.NoError(t, err)
+	<-done
+	assert.Equal(t, "Hello!", content)
+}
@@ -2,8 +2,6 @@ package talkative
 
 import (
 	"bufio"
-	"encoding/json"
-	"fmt"
 	"io"
 )
 
@@ -15,26 +13,10 @@ import (
 //
 // In case of errors during decoding or processing, the callback is invoked with the error
 // and processing stops. The function closes the response body before exiting.
+//
+// Use DecodeStream to customise how the messages are decoded.
 func StreamResponse[T any](body io.ReadCloser, cb func(*T, error)) {
-	defer body.Close()
-
-	for {
-		var response T
-
-		err := json.NewDecoder(body).Decode(&response)
-
-		if err == io.EOF {
-			return
-		}
-
-		if err != nil {
-			cb(nil, fmt.Errorf("%w: %v", ErrDecoding, err))
-
-			return
-		}
-
-		cb(&response, nil)
-	}
+	DecodeStream(body, cb)
 }
 
 // Streaming the plain response from the server asynchronously.
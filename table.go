@@ -0,0 +1,336 @@
+package talkative
+
+import (
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pre-defined errors used by the table parser.
+var (
+	ErrTableHeader = newError("invalid table header") // Error for missing headers or required columns.
+	ErrTableRow    = newError("malformed table row")  // Error for rows which cannot be parsed.
+)
+
+// TableOptions configures ParseTable and ParseTableInto.
+type TableOptions struct {
+	Comma    rune     // The field delimiter, detected from the header (tab or comma) when zero.
+	Required []string // Columns which must be present in the header, compared case insensitively.
+
+	// Repair enables re-prompting the model to fix malformed rows. (Optional)
+	Repair *TableRepair
+}
+
+// TableRepair configures the repair of malformed rows by the model.
+type TableRepair struct {
+	Client   *Client // The client used to ask for repairs.
+	Model    string  // The model asked to repair the rows.
+	Attempts int     // Maximum number of repair rounds, defaults to 1.
+}
+
+// RowError describes a row which could not be parsed.
+type RowError struct {
+	Row  int    // The index of the row within the data rows, starting from 0.
+	Line string // The raw line of the row.
+	Err  error  // The reason the row could not be parsed.
+}
+
+// Error implements the error interface.
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// ParseTable parses CSV or TSV model output into one map per row, keyed by the header columns.
+//
+// Blank lines and markdown code fences are ignored. Rows which cannot be parsed are reported as
+// row errors rather than failing the whole table, unless they can be repaired by the model.
+func ParseTable(text string, opts TableOptions) ([]map[string]string, []*RowError, error) {
+	return ParseTableInto[map[string]string](text, opts)
+}
+
+// ParseTableInto parses CSV or TSV model output into values of type T, which is either a
+// map[string]string or a struct.
+//
+// Struct fields are matched with the columns by their `table` tag, or by their name case insensitively.
+// Values are coerced to the type of the field, supporting strings, booleans, integers, floats and
+// durations. Rows which cannot be coerced are reported as row errors.
+func ParseTableInto[T any](text string, opts TableOptions) ([]T, []*RowError, error) {
+	lines := tableLines(text)
+
+	if len(lines) == 0 {
+		return nil, nil, fmt.Errorf("%w: table is empty", ErrTableHeader)
+	}
+
+	if opts.Comma == 0 {
+		opts.Comma = ','
+
+		if strings.Contains(lines[0], "\t") {
+			opts.Comma = '\t'
+		}
+	}
+
+	header, err := parseTableLine(lines[0], opts.Comma)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrTableHeader, err)
+	}
+
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	for _, required := range opts.Required {
+		if tableColumn(header, required) < 0 {
+			return nil, nil, fmt.Errorf("%w: missing column %q", ErrTableHeader, required)
+		}
+	}
+
+	rows := make([]*T, len(lines)-1)
+	failures := []*RowError{}
+
+	for i, line := range lines[1:] {
+		row, err := decodeTableRow[T](header, line, opts.Comma)
+
+		if err != nil {
+			failures = append(failures, &RowError{Row: i, Line: line, Err: err})
+
+			continue
+		}
+
+		rows[i] = row
+	}
+
+	if opts.Repair != nil && len(failures) > 0 {
+		failures = repairTable(rows, failures, lines[0], header, opts)
+	}
+
+	values := make([]T, 0, len(rows))
+
+	for _, row := range rows {
+		if row != nil {
+			values = append(values, *row)
+		}
+	}
+
+	return values, failures, nil
+}
+
+// repairTable asks the model to fix the malformed rows, storing the repaired rows in place.
+//
+// It returns the rows which could not be repaired.
+func repairTable[T any](rows []*T, failures []*RowError, headerLine string, header []string, opts TableOptions) []*RowError {
+	attempts := opts.Repair.Attempts
+
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	for attempt := 0; attempt < attempts && len(failures) > 0; attempt++ {
+		sb := strings.Builder{}
+
+		sb.WriteString("The following rows do not match the table header or contain invalid values.\n")
+		sb.WriteString("Rewrite each of them, one per line and in the same order, so they match the header exactly.\n")
+		sb.WriteString("Reply with the rewritten rows only, without the header or any explanation.\n\n")
+		sb.WriteString("Header:\n" + headerLine + "\n\nRows:\n")
+
+		for _, failure := range failures {
+			fmt.Fprintf(&sb, "%s\n", failure.Line)
+		}
+
+		reply, err := opts.Repair.Client.chatText(opts.Repair.Model, nil, ChatMessage{Role: USER, Content: sb.String()})
+
+		if err != nil {
+			return failures
+		}
+
+		repaired := tableLines(reply)
+
+		if len(repaired) > 0 && strings.EqualFold(strings.TrimSpace(repaired[0]), strings.TrimSpace(headerLine)) {
+			repaired = repaired[1:]
+		}
+
+		remaining := []*RowError{}
+
+		for i, failure := range failures {
+			if i >= len(repaired) {
+				remaining = append(remaining, failure)
+
+				continue
+			}
+
+			row, err := decodeTableRow[T](header, repaired[i], opts.Comma)
+
+			if err != nil {
+				remaining = append(remaining, &RowError{Row: failure.Row, Line: repaired[i], Err: err})
+
+				continue
+			}
+
+			rows[failure.Row] = row
+		}
+
+		failures = remaining
+	}
+
+	return failures
+}
+
+// tableLines returns the non blank lines of the text, skipping markdown code fences.
+func tableLines(text string) []string {
+	lines := []string{}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "```") {
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// parseTableLine splits a single line into its fields.
+func parseTableLine(line string, comma rune) ([]string, error) {
+	reader := csv.NewReader(strings.NewReader(line))
+	reader.Comma = comma
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	return reader.Read()
+}
+
+// tableColumn returns the index of the column in the header, compared case insensitively, or -1.
+func tableColumn(header []string, name string) int {
+	for i, column := range header {
+		if strings.EqualFold(column, name) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// decodeTableRow parses the line and decodes its fields into a T.
+func decodeTableRow[T any](header []string, line string, comma rune) (*T, error) {
+	fields, err := parseTableLine(line, comma)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) != len(header) {
+		return nil, fmt.Errorf("%w: expected %d fields, got %d", ErrTableRow, len(header), len(fields))
+	}
+
+	row := new(T)
+
+	if m, ok := any(row).(*map[string]string); ok {
+		*m = make(map[string]string, len(header))
+
+		for i, column := range header {
+			(*m)[column] = strings.TrimSpace(fields[i])
+		}
+
+		return row, nil
+	}
+
+	value := reflect.ValueOf(row).Elem()
+
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: unsupported type %s", ErrTableRow, value.Type())
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Tag.Get("table")
+
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		column := tableColumn(header, name)
+
+		if column < 0 {
+			continue
+		}
+
+		if err := coerce(value.Field(i), strings.TrimSpace(fields[column])); err != nil {
+			return nil, fmt.Errorf("%w: column %q: %v", ErrTableRow, header[column], err)
+		}
+	}
+
+	return row, nil
+}
+
+// coerce parses the text into the field according to its type.
+func coerce(field reflect.Value, text string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(text)
+
+		if err == nil {
+			field.SetInt(int64(d))
+		}
+
+		return err
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.ToLower(text))
+
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.ReplaceAll(text, ",", ""), 10, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.ReplaceAll(text, ",", ""), 10, field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", ""), field.Type().Bits())
+
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package talkative_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseTable tests parsing of CSV and TSV output with header validation.
+func TestParseTable(t *testing.T) {
+	rows, failures, err := talkative.ParseTable("```csv\nname, age\nAlice, 30\nBob\n\n\"Carol, Jr\", 41\n```", talkative.TableOptions{Required: []string{"Name"}})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, []map[string]string{{"name": "Alice", "age": "30"}, {"name": "Carol, Jr", "age": "41"}}, rows)
+		assert.Len(t, failures, 1)
+		assert.Equal(t, 1, failures[0].Row)
+		assert.True(t, errors.Is(failures[0], talkative.ErrTableRow))
+	}
+
+	rows, _, err = talkative.ParseTable("name\tage\nAlice\t30", talkative.TableOptions{})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, []map[string]string{{"name": "Alice", "age": "30"}}, rows)
+	}
+
+	_, _, err = talkative.ParseTable("name,age\nAlice,30", talkative.TableOptions{Required: []string{"email"}})
+	{
+		assert.ErrorIs(t, err, talkative.ErrTableHeader)
+	}
+}
+
+// TestParseTableInto tests coercion of the columns into struct fields.
+func TestParseTableInto(t *testing.T) {
+	type task struct {
+		Title    string
+		Done     bool
+		Points   int           `table:"story points"`
+		Estimate time.Duration `table:"estimate"`
+		Ignored  string        `table:"-"`
+	}
+
+	tasks, failures, err := talkative.ParseTableInto[task]("title,done,story points,estimate\nWrite docs,true,3,2h\nFix bug,maybe,5,1h", talkative.TableOptions{})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, []task{{Title: "Write docs", Done: true, Points: 3, Estimate: 2 * time.Hour}}, tasks)
+		assert.Len(t, failures, 1)
+		assert.Equal(t, "Fix bug,maybe,5,1h", failures[0].Line)
+	}
+}
+
+// TestParseTableRepair tests malformed rows being repaired by the model.
+func TestParseTableRepair(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "name,age\n"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Bob,25"}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	rows, failures, err := talkative.ParseTable("name,age\nAlice,30\nBob,25,extra\nCarol,41", talkative.TableOptions{
+		Repair: &talkative.TableRepair{Client: client, Model: "llama2"},
+	})
+	{
+		assert.NoError(t, err)
+		assert.Empty(t, failures)
+		assert.Equal(t, []map[string]string{
+			{"name": "Alice", "age": "30"},
+			{"name": "Bob", "age": "25"},
+			{"name": "Carol", "age": "41"},
+		}, rows)
+	}
+}
@@ -1,9 +1,10 @@
 package talkative
 
 import (
-	"errors"
 	"net/http"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 // Define an enum-like type to represent different user roles in the chat system.
@@ -16,43 +17,143 @@ const (
 	// Assistant role for AI assistants or chatbots.
 	ASSISTANT Role = "assistant"
 
-	// Default model to be used when model is not specified.
+	// System role for instructions guiding the behaviour of the assistant.
+	SYSTEM Role = "system"
+
+	// Default model to be used when model is not specified and the client has no default model, see WithDefaultModel.
 	DEFAULT_MODEL string = "llama2"
 )
 
 // Pre-defined errors used throughout the code for consistency.
 var (
-	ErrUrl        = errors.New("url cannot be empty")         // Error for missing URL
-	ErrCallback   = errors.New("callback cannot be empty")    // Error for missing callback function.
-	ErrMessage    = errors.New("message cannot be empty")     // Error for empty message list.
-	ErrInvoke     = errors.New("unable to invoke ollama api") // Error for failing to call the Ollama API.
-	ErrEncoding   = errors.New("unable to encode")            // Error for problems encoding data to JSON.
-	ErrDecoding   = errors.New("unable to decode")            // Error for problems encoding data to JSON.
-	ErrBadRequest = errors.New("")                            // Error for bad request response from Ollama API. This just acts as a placeholder, the actual response will be wrapped under this error
+	ErrUrl        = newError("url cannot be empty")         // Error for missing URL
+	ErrCallback   = newError("callback cannot be empty")    // Error for missing callback function.
+	ErrMessage    = newError("message cannot be empty")     // Error for empty message list.
+	ErrModel      = newError("model cannot be empty")       // Error for missing model name.
+	ErrModelfile  = newError("modelfile cannot be empty")   // Error for missing Modelfile content.
+	ErrInvoke     = newError("unable to invoke ollama api") // Error for failing to call the Ollama API.
+	ErrEncoding   = newError("unable to encode")            // Error for problems encoding data to JSON.
+	ErrDecoding   = newError("unable to decode")            // Error for problems encoding data to JSON.
+	ErrBadRequest = newError("bad request")                 // Error for bad request responses from the Ollama API, see APIError.
+	ErrNotFound   = newError("not found")                   // Error for not found responses from the Ollama API, i.e: unknown models, see APIError.
 )
 
 // Client struct holds information for interacting with the Ollama API.
 type Client struct {
-	urls   map[string]string // Stores endpoint URLs for the Ollama API.
-	client *http.Client      // Holds an http.Client instance for making HTTP requests.
+	base         string            // The base URL of the Ollama API.
+	fallbackURLs []string          // The base URLs tried in order when the base URL cannot be connected to.
+	urls         map[string]string // Stores endpoint URLs for the Ollama API.
+	client       *http.Client      // Holds an http.Client instance for making HTTP requests.
+
+	headers http.Header               // Headers attached to every request.
+	auth    func(*http.Request) error // Sets the credentials of every request.
+
+	compression         Compression // How request bodies are compressed.
+	compressionMinSize  int         // Minimum size of request bodies to be compressed.
+	compressionRejected atomic.Bool // Whether the server rejected compressed bodies (COMPRESSION_AUTO).
+
+	events eventBus // Dispatches the lifecycle events of requests to subscribers.
+
+	chatHooks       []ChatHook       // Hooks invoked before chat requests are sent.
+	completionHooks []CompletionHook // Hooks invoked before completion requests are sent.
+
+	prefix *promptPrefix // Keeps the prefix of chats stable and tracks its changes.
+
+	sizing *contextSizing // Sets the context length of requests from the size of their prompt.
+
+	models models // Tracks model aliases and in-flight requests per model.
+
+	features map[Feature]bool // Experimental behaviours enabled on the client.
+
+	pacing time.Duration // Minimum delay between the delivery of consecutive chunks.
+
+	skewTolerance time.Duration // Maximum offset of the creation time of responses from the local clock.
+
+	backfill bool // Whether missing metrics of final responses are estimated.
+
+	ignored atomic.Int64 // Number of chunks received after the final response of a stream.
+
+	defaultModel string // The model used when requests do not specify one.
+
+	ids IDGenerator // Generates the identifiers of requests, jobs, outbox entries and conversations.
+
+	fallback *string // Template of the responses delivered when the backend is unavailable.
+
+	panics PanicHandler // Receives the panics recovered from internal goroutines.
+
+	requestTimeout    time.Duration // Maximum time to connect and receive the response headers.
+	streamIdleTimeout time.Duration // Maximum silence between two chunks of a response.
+	stallTimeout      time.Duration // Maximum time between two chunks of a generation.
+
+	loadingRetry *LoadingRetry // How requests hitting a loading model are retried.
+	prewarm      *Prewarm      // How the connections to the endpoints are warmed when the client is created.
+
+	socket string // The Unix domain socket dialed by the HTTP client, see WithUnixSocket.
+
+	decode []DecodeOption // How the responses of chats, completions and Invoke are decoded.
+
+	err error // The error of the options, returned by New and by every request of clones, see ErrTransport.
 }
 
 // New function creates a new Client instance for interacting with the Ollama API.
-// Takes the base URL of the Ollama API as an argument.
-func New(url string) (*Client, error) {
+// Takes the base URL of the Ollama API and optional configuration options as arguments.
+//
+// The URL can also designate a Unix domain socket, i.e: "unix:///var/run/ollama.sock".
+// Fallback URLs, tried when it cannot be connected to, are given with WithFallbackURLs.
+func New(url string, opts ...Option) (*Client, error) {
 	url = strings.Trim(url, " ")
 
 	if url == "" {
 		return nil, ErrUrl
 	}
 
+	var socket string
+
+	// Unix socket URLs are served over HTTP, the host being ignored.
+	if strings.HasPrefix(url, "unix://") {
+		socket, url = strings.TrimPrefix(url, "unix://"), "http://localhost"
+	}
+
 	client := &http.Client{} // Create a new HTTP client instance.
 
-	return &Client{
+	c := &Client{
+		base: url,
 		urls: map[string]string{
-			"chat":       url + "/api/chat",     // Define the chat endpoint URL based on the provided base URL.
-			"completion": url + "/api/generate", // Define the completion endpoint URL based on the provided base URL.
+			"chat":       url + "/api/chat",       // Define the chat endpoint URL based on the provided base URL.
+			"completion": url + "/api/generate",   // Define the completion endpoint URL based on the provided base URL.
+			"blobs":      url + "/api/blobs",      // Define the endpoint URL of the blobs, followed by their digest.
+			"embed":      url + "/api/embed",      // Define the endpoint URL generating embeddings.
+			"ps":         url + "/api/ps",         // Define the endpoint URL listing the running models.
+			"tags":       url + "/api/tags",       // Define the endpoint URL listing the local models.
+			"pull":       url + "/api/pull",       // Define the endpoint URL downloading models.
+			"delete":     url + "/api/delete",     // Define the endpoint URL deleting models.
+			"copy":       url + "/api/copy",       // Define the endpoint URL copying models.
+			"show":       url + "/api/show",       // Define the endpoint URL describing models.
+			"create":     url + "/api/create",     // Define the endpoint URL creating models.
+			"push":       url + "/api/push",       // Define the endpoint URL uploading models.
+			"version":    url + "/api/version",    // Define the endpoint URL reporting the server version.
+			"transcribe": url + "/api/transcribe", // Define the endpoint URL transcribing audio, exposed by gateways only.
 		},
 		client: client,
-	}, nil
+		socket: socket,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// The socket is dialed by the resulting HTTP client, which the options may have replaced.
+	if c.socket != "" {
+		c.dialSocket()
+	}
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	if c.prewarm != nil {
+		c.keepWarm(c.prewarm)
+	}
+
+	return c, nil
 }
@@ -0,0 +1,130 @@
+package talkative
+
+import "sync"
+
+// DEFAULT_TEE_BUFFER is the default number of responses buffered per consumer of a Tee.
+const DEFAULT_TEE_BUFFER = 64
+
+// Define an enum-like type to represent how a Tee handles consumers which cannot keep up with the stream.
+type TeePolicy int
+
+const (
+	// The stream waits while the buffer of a consumer is full, the slowest consumer sets the pace of the stream.
+	TEE_BLOCK TeePolicy = iota
+
+	// The responses are dropped for consumers whose buffer is full, so they never slow the stream down.
+	// Errors are never dropped.
+	TEE_DROP
+)
+
+// TeeOptions configures a Tee.
+type TeeOptions struct {
+	Buffer int       // Number of responses buffered per consumer, defaults to DEFAULT_TEE_BUFFER.
+	Policy TeePolicy // How consumers which cannot keep up are handled, defaults to TEE_BLOCK.
+
+	// OnDrop is invoked with the index of the consumer whenever a response is dropped for it with TEE_DROP.
+	// (Optional)
+	OnDrop func(consumer int)
+}
+
+// Tee fans a single stream out to several callbacks, i.e: rendering the UI, logging and recording metrics,
+// without each of them sending its own request:
+//
+//	tee := talkative.NewTee(talkative.TeeOptions{}, render, logger, metrics)
+//	done, err := client.Chat(model, tee.Callback, nil, msgs...)
+//	...
+//	<-done
+//	tee.Close()
+//
+// Every consumer receives the responses in order from its own goroutine through a buffer, so a slow consumer
+// doesn't delay the others until its buffer is full, see TeePolicy. Every consumer receives its own copy of
+// the responses, so a consumer altering them, i.e: CumulativeChat, doesn't affect the others.
+type Tee[T any] struct {
+	opts   TeeOptions
+	queues []chan teeItem[T]
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	closed bool
+}
+
+// teeItem is a response queued for a consumer.
+type teeItem[T any] struct {
+	response *T
+	err      error
+}
+
+// NewTee creates a new Tee delivering the stream to the callbacks.
+func NewTee[T any](opts TeeOptions, cbs ...func(*T, error)) *Tee[T] {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DEFAULT_TEE_BUFFER
+	}
+
+	t := &Tee[T]{opts: opts}
+
+	for _, cb := range cbs {
+		queue := make(chan teeItem[T], opts.Buffer)
+		t.queues = append(t.queues, queue)
+		t.wg.Add(1)
+
+		go func(cb func(*T, error)) {
+			defer t.wg.Done()
+
+			for item := range queue {
+				cb(item.response, item.err)
+			}
+		}(cb)
+	}
+
+	return t
+}
+
+// Callback queues the response for every consumer, it is the callback to pass to the streaming request.
+// Responses received after Close are ignored.
+func (t *Tee[T]) Callback(response *T, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return
+	}
+
+	for i, queue := range t.queues {
+		item := teeItem[T]{err: err}
+
+		if response != nil {
+			copied := *response
+			item.response = &copied
+		}
+
+		if t.opts.Policy == TEE_BLOCK || err != nil {
+			queue <- item
+
+			continue
+		}
+
+		select {
+		case queue <- item:
+		default:
+			if t.opts.OnDrop != nil {
+				t.opts.OnDrop(i)
+			}
+		}
+	}
+}
+
+// Close stops accepting responses and waits for every consumer to process the responses queued for it.
+func (t *Tee[T]) Close() {
+	t.mu.Lock()
+
+	if !t.closed {
+		t.closed = true
+
+		for _, queue := range t.queues {
+			close(queue)
+		}
+	}
+
+	t.mu.Unlock()
+
+	t.wg.Wait()
+}
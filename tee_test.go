@@ -0,0 +1,91 @@
+package talkative_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTee tests fanning a chat out to several callbacks.
+func TestTee(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Content: " world"}, Done: true},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	cumulative := []string{}
+	deltas := []string{}
+
+	tee := talkative.NewTee(talkative.TeeOptions{},
+		talkative.CumulativeChat(func(cr *talkative.ChatResponse, err error) {
+			cumulative = append(cumulative, cr.Message.Content)
+		}),
+		func(cr *talkative.ChatResponse, err error) {
+			deltas = append(deltas, cr.Message.Content)
+		},
+	)
+
+	done, err := client.Chat("", tee.Callback, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+		<-done
+		tee.Close()
+
+		// Every consumer receives its own copy of the responses.
+		assert.Equal(t, []string{"Hello", "Hello world"}, cumulative)
+		assert.Equal(t, []string{"Hello", " world"}, deltas)
+	}
+
+	// Responses received after Close are ignored.
+	tee.Callback(&talkative.ChatResponse{}, nil)
+	{
+		assert.Len(t, deltas, 2)
+	}
+}
+
+// TestTeeDrop tests dropping the responses of consumers which cannot keep up.
+func TestTeeDrop(t *testing.T) {
+	release := make(chan struct{})
+	received := []string{}
+	dropped := []int{}
+	errs := 0
+
+	tee := talkative.NewTee(talkative.TeeOptions{Buffer: 1, Policy: talkative.TEE_DROP, OnDrop: func(consumer int) {
+		dropped = append(dropped, consumer)
+	}}, func(cr *talkative.CompletionResponse, err error) {
+		<-release
+
+		if err != nil {
+			errs++
+
+			return
+		}
+
+		received = append(received, cr.Response)
+	})
+
+	// The consumer blocks on the first response, the second one fills the buffer and the third one is dropped.
+	tee.Callback(&talkative.CompletionResponse{Response: "a"}, nil)
+	tee.Callback(&talkative.CompletionResponse{Response: "b"}, nil)
+
+	assert.Eventually(t, func() bool {
+		tee.Callback(&talkative.CompletionResponse{Response: "c"}, nil)
+
+		return len(dropped) > 0
+	}, time.Second, time.Millisecond)
+
+	close(release)
+
+	// Errors are never dropped.
+	tee.Callback(nil, talkative.ErrInvoke)
+	tee.Close()
+
+	assert.Equal(t, 0, dropped[0])
+	assert.Equal(t, "a", received[0])
+	assert.Equal(t, 1, errs)
+}
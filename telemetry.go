@@ -0,0 +1,220 @@
+package talkative
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TELEMETRY_LATENCY_BUCKETS are the upper bounds, in seconds, of the latency buckets reported by Telemetry.
+var TELEMETRY_LATENCY_BUCKETS = []float64{0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Telemetry aggregates anonymous usage statistics of clients and reports them to a user-configured endpoint.
+//
+// It is strictly opt-in: nothing is collected until it observes a client, and nothing is sent until it is run
+// or reported. Only operation and model names, counts and latency buckets are collected, never the content,
+// the endpoints or the errors of the requests.
+type Telemetry struct {
+	Endpoint string        // The URL the reports are POSTed to as JSON.
+	Service  string        // The name of the service reporting, i.e: "support-bot". (Optional)
+	Interval time.Duration // Interval between two reports of Run, defaults to 1 hour.
+	Client   *http.Client  // The HTTP client used to report, defaults to http.DefaultClient.
+	OnError  func(error)   // Invoked when a report fails. (Optional)
+
+	once     sync.Once
+	instance string
+	mu       sync.Mutex
+	since    time.Time
+	usage    map[[2]string]*TelemetryUsage
+}
+
+// TelemetryReport is the payload sent to the telemetry endpoint.
+type TelemetryReport struct {
+	Service  string           `json:"service,omitempty"` // The name of the service reporting.
+	Instance string           `json:"instance"`          // A random identifier of the reporting process, regenerated on restart.
+	Since    time.Time        `json:"since"`             // Start of the reported window.
+	Until    time.Time        `json:"until"`             // End of the reported window.
+	Usage    []TelemetryUsage `json:"usage"`             // The usage per operation and model.
+}
+
+// TelemetryUsage holds the usage statistics of an operation and model.
+type TelemetryUsage struct {
+	Op        string `json:"op"`        // The operation, i.e: chat or completion.
+	Model     string `json:"model"`     // The model name.
+	Requests  int    `json:"requests"`  // Number of requests started.
+	Failures  int    `json:"failures"`  // Number of requests failed.
+	Latencies []int  `json:"latencies"` // Number of completed requests per latency bucket, the last one counting the slower requests.
+}
+
+// Observe subscribes the telemetry to the lifecycle events of the client.
+//
+// The returned function stops observing the client.
+func (t *Telemetry) Observe(client *Client) (unsubscribe func()) {
+	return client.Subscribe(t.record, EVENT_REQUEST_STARTED, EVENT_COMPLETED, EVENT_FAILED)
+}
+
+// record aggregates a single event.
+func (t *Telemetry) record(event Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.usage == nil {
+		t.usage = map[[2]string]*TelemetryUsage{}
+		t.since = time.Now()
+	}
+
+	key := [2]string{event.Op, event.Model}
+	usage, ok := t.usage[key]
+
+	if !ok {
+		usage = &TelemetryUsage{Op: event.Op, Model: event.Model, Latencies: make([]int, len(TELEMETRY_LATENCY_BUCKETS)+1)}
+		t.usage[key] = usage
+	}
+
+	switch event.Type {
+	case EVENT_REQUEST_STARTED:
+		usage.Requests++
+	case EVENT_FAILED:
+		usage.Failures++
+	case EVENT_COMPLETED:
+		bucket := sort.SearchFloat64s(TELEMETRY_LATENCY_BUCKETS, event.Elapsed.Seconds())
+		usage.Latencies[bucket]++
+	}
+}
+
+// Snapshot returns the statistics aggregated since the last report, without resetting them.
+func (t *Telemetry) Snapshot() TelemetryReport {
+	t.once.Do(func() {
+		id := make([]byte, 8)
+		rand.Read(id)
+		t.instance = hex.EncodeToString(id)
+	})
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := TelemetryReport{Service: t.Service, Instance: t.instance, Since: t.since, Until: time.Now(), Usage: []TelemetryUsage{}}
+
+	for _, usage := range t.usage {
+		copied := *usage
+		copied.Latencies = append([]int(nil), usage.Latencies...)
+		report.Usage = append(report.Usage, copied)
+	}
+
+	sort.Slice(report.Usage, func(i, j int) bool {
+		a, b := report.Usage[i], report.Usage[j]
+
+		return a.Op < b.Op || (a.Op == b.Op && a.Model < b.Model)
+	})
+
+	return report
+}
+
+// Report sends the statistics aggregated since the last report, which are reset once accepted by the endpoint.
+// Nothing is sent when no request was observed.
+func (t *Telemetry) Report(ctx context.Context) error {
+	report := t.Snapshot()
+
+	if len(report.Usage) == 0 {
+		return nil
+	}
+
+	body := &bytes.Buffer{}
+
+	if err := json.NewEncoder(body).Encode(report); err != nil {
+		return fmt.Errorf("%w:%v", ErrEncoding, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Endpoint, body)
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	client := t.Client
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("%w: telemetry endpoint responded with %s", ErrInvoke, res.Status)
+	}
+
+	t.subtract(report)
+
+	return nil
+}
+
+// Run reports the statistics every interval until the context is cancelled, reporting a last time before returning.
+func (t *Telemetry) Run(ctx context.Context) {
+	interval := t.Interval
+
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.report(t.Report(context.Background()))
+
+			return
+		case <-ticker.C:
+			t.report(t.Report(ctx))
+		}
+	}
+}
+
+// subtract removes the reported statistics, keeping the ones aggregated while the report was being sent.
+func (t *Telemetry) subtract(report TelemetryReport) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.since = report.Until
+
+	for _, reported := range report.Usage {
+		key := [2]string{reported.Op, reported.Model}
+		usage := t.usage[key]
+
+		usage.Requests -= reported.Requests
+		usage.Failures -= reported.Failures
+		idle := usage.Requests == 0 && usage.Failures == 0
+
+		for i := range usage.Latencies {
+			usage.Latencies[i] -= reported.Latencies[i]
+			idle = idle && usage.Latencies[i] == 0
+		}
+
+		if idle {
+			delete(t.usage, key)
+		}
+	}
+}
+
+// report hands the report error to the configured handler.
+func (t *Telemetry) report(err error) {
+	if err != nil && t.OnError != nil {
+		t.OnError(err)
+	}
+}
@@ -0,0 +1,61 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTelemetry tests reporting anonymous usage statistics without content.
+func TestTelemetry(t *testing.T) {
+	server := streamServer(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "secret answer"}, Done: true})
+	defer server.Close()
+
+	reports := make(chan []byte, 2)
+
+	endpoint := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		reports <- body
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+	defer endpoint.Close()
+
+	client, _ := talkative.New(server.URL)
+	telemetry := &talkative.Telemetry{Endpoint: endpoint.URL, Service: "support-bot"}
+	telemetry.Observe(client)
+
+	for i := 0; i < 2; i++ {
+		done, err := client.Chat("llama2", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "secret question"})
+		assert.NoError(t, err)
+		<-done
+	}
+
+	assert.NoError(t, telemetry.Report(context.Background()))
+
+	body := <-reports
+	assert.NotContains(t, string(body), "secret")
+	assert.NotContains(t, string(body), server.URL)
+
+	report := talkative.TelemetryReport{}
+	json.Unmarshal(body, &report)
+
+	assert.Equal(t, "support-bot", report.Service)
+	assert.NotEmpty(t, report.Instance)
+	assert.Len(t, report.Usage, 1)
+	assert.Equal(t, "chat", report.Usage[0].Op)
+	assert.Equal(t, "llama2", report.Usage[0].Model)
+	assert.Equal(t, 2, report.Usage[0].Requests)
+	assert.Equal(t, 2, report.Usage[0].Latencies[0])
+
+	// Reported statistics are reset, nothing is sent without new requests.
+	assert.NoError(t, telemetry.Report(context.Background()))
+	assert.Empty(t, reports)
+	assert.Empty(t, telemetry.Snapshot().Usage)
+}
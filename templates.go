@@ -0,0 +1,148 @@
+package talkative
+
+import (
+	"bytes"
+	"sync"
+	"text/template"
+	"text/template/parse"
+)
+
+// DEFAULT_TEMPLATE_CACHE_SIZE is the number of compiled templates kept by DefaultTemplates.
+const DEFAULT_TEMPLATE_CACHE_SIZE = 1024
+
+// DefaultTemplates is the template cache used by Pipeline and MapReduce.
+var DefaultTemplates = NewTemplateCache(DEFAULT_TEMPLATE_CACHE_SIZE)
+
+// TemplateCache compiles prompt templates once and reuses them across calls.
+//
+// Templates without any action are static, their text is returned as is without executing them.
+// Missing keys are reported as errors. A TemplateCache is safe for concurrent use.
+type TemplateCache struct {
+	mu      sync.RWMutex
+	size    int
+	entries map[string]*cachedTemplate
+	buffers sync.Pool
+}
+
+// cachedTemplate is a compiled template and whether it is static.
+type cachedTemplate struct {
+	tmpl   *template.Template
+	static bool
+}
+
+// NewTemplateCache creates a new cache keeping up to size compiled templates.
+//
+// When size is less than or equal to zero, DEFAULT_TEMPLATE_CACHE_SIZE is used.
+func NewTemplateCache(size int) *TemplateCache {
+	if size <= 0 {
+		size = DEFAULT_TEMPLATE_CACHE_SIZE
+	}
+
+	return &TemplateCache{
+		size:    size,
+		entries: make(map[string]*cachedTemplate),
+		buffers: sync.Pool{New: func() any { return new(bytes.Buffer) }},
+	}
+}
+
+// Compile returns the compiled template of the source, compiling and caching it on the first call.
+func (tc *TemplateCache) Compile(source string) (*template.Template, error) {
+	entry, err := tc.get(source)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.tmpl, nil
+}
+
+// Render renders the source template with the data.
+func (tc *TemplateCache) Render(source string, data any) (string, error) {
+	entry, err := tc.get(source)
+
+	if err != nil {
+		return "", err
+	}
+
+	if entry.static {
+		return source, nil
+	}
+
+	buf := tc.buffers.Get().(*bytes.Buffer)
+	defer tc.buffers.Put(buf)
+
+	buf.Reset()
+
+	if err := entry.tmpl.Execute(buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// Len returns the number of cached templates.
+func (tc *TemplateCache) Len() int {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+
+	return len(tc.entries)
+}
+
+// Reset removes every cached template.
+func (tc *TemplateCache) Reset() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.entries = make(map[string]*cachedTemplate)
+}
+
+// get returns the cached entry of the source, compiling it when missing.
+//
+// When the cache is full, an arbitrary entry is evicted to make room for the new one.
+func (tc *TemplateCache) get(source string) (*cachedTemplate, error) {
+	tc.mu.RLock()
+	entry, ok := tc.entries[source]
+	tc.mu.RUnlock()
+
+	if ok {
+		return entry, nil
+	}
+
+	tmpl, err := template.New("prompt").Option("missingkey=error").Parse(source)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entry = &cachedTemplate{tmpl: tmpl, static: isStatic(tmpl)}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if len(tc.entries) >= tc.size {
+		for key := range tc.entries {
+			delete(tc.entries, key)
+
+			break
+		}
+	}
+
+	tc.entries[source] = entry
+
+	return entry, nil
+}
+
+// isStatic reports whether the template only contains text.
+func isStatic(tmpl *template.Template) bool {
+	if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+		return true
+	}
+
+	for _, node := range tmpl.Tree.Root.Nodes {
+		if node.Type() != parse.NodeText {
+			return false
+		}
+	}
+
+	return true
+}
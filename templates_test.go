@@ -0,0 +1,50 @@
+package talkative_test
+
+import (
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTemplateCache tests compiling templates once and rendering static templates as is.
+func TestTemplateCache(t *testing.T) {
+	cache := talkative.NewTemplateCache(2)
+
+	first, err := cache.Compile("Hello {{.Name}}")
+	{
+		assert.NoError(t, err)
+	}
+
+	second, _ := cache.Compile("Hello {{.Name}}")
+	assert.Same(t, first, second)
+
+	text, err := cache.Render("Hello {{.Name}}", map[string]string{"Name": "Gopher"})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello Gopher", text)
+	}
+
+	text, err = cache.Render("You are a helpful assistant.", nil)
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "You are a helpful assistant.", text)
+	}
+
+	_, err = cache.Render("{{.Missing}}", map[string]string{})
+	{
+		assert.Error(t, err)
+	}
+
+	assert.Equal(t, 2, cache.Len())
+
+	_, err = cache.Compile("{{.Broken")
+	{
+		assert.Error(t, err)
+	}
+
+	cache.Reset()
+	assert.Equal(t, 0, cache.Len())
+}
+
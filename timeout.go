@@ -0,0 +1,128 @@
+package talkative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrRequestTimeout    = newError("request timed out waiting for the server")    // Error for servers not responding within the request timeout.
+	ErrStreamIdleTimeout = newError("stream timed out waiting for the next chunk") // Error for streams silent for longer than the idle timeout.
+)
+
+// WithRequestTimeout bounds the time spent connecting and waiting for the response headers of every request.
+//
+// Unlike http.Client.Timeout, it doesn't bound the time spent streaming the response, which can legitimately
+// last minutes for long generations. Requests exceeding it fail with ErrRequestTimeout.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = timeout
+	}
+}
+
+// WithStreamIdleTimeout bounds the silence between two chunks of a response, once its headers were received.
+//
+// A server accepting the connection but never producing tokens would otherwise hang the stream forever.
+// Streams exceeding it are aborted and their callback receives an error matching ErrStreamIdleTimeout.
+func WithStreamIdleTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.streamIdleTimeout = timeout
+	}
+}
+
+// roundTrip sends the request with the HTTP client, failing over to the fallback URLs of the client when
+// its base URL cannot be connected to, see WithFallbackURLs.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	return c.failover(req)
+}
+
+// attempt sends the request with the HTTP client, setting its headers and credentials and enforcing
+// the request and stream idle timeouts of the client.
+func (c *Client) attempt(req *http.Request) (*http.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	if err := c.setHeaders(req); err != nil {
+		return nil, err
+	}
+
+	if c.requestTimeout <= 0 && c.streamIdleTimeout <= 0 {
+		return c.client.Do(req)
+	}
+
+	ctx, cancel := context.WithCancelCause(req.Context())
+
+	var timer *time.Timer
+
+	if c.requestTimeout > 0 {
+		timer = time.AfterFunc(c.requestTimeout, func() { cancel(ErrRequestTimeout) })
+	}
+
+	res, err := c.client.Do(req.WithContext(ctx))
+
+	// The timer may fire between the response and this point, the body is unusable then.
+	if timer != nil && !timer.Stop() && err == nil {
+		res.Body.Close()
+		err = context.Cause(ctx)
+	}
+
+	if err != nil {
+		cancel(nil)
+
+		if cause := context.Cause(ctx); errors.Is(cause, ErrRequestTimeout) {
+			return nil, fmt.Errorf("%w: %s %s", ErrRequestTimeout, req.Method, req.URL)
+		}
+
+		return nil, err
+	}
+
+	body := &idleBody{body: res.Body, ctx: ctx, cancel: cancel, timeout: c.streamIdleTimeout}
+
+	if body.timeout > 0 {
+		body.timer = time.AfterFunc(body.timeout, func() { cancel(ErrStreamIdleTimeout) })
+	}
+
+	res.Body = body
+
+	return res, nil
+}
+
+// idleBody is a response body aborted when no data is read for longer than its timeout.
+type idleBody struct {
+	body    io.ReadCloser
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+// Read reads from the body, restarting the idle timer whenever data arrives.
+func (b *idleBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+
+	if cause := context.Cause(b.ctx); err != nil && errors.Is(cause, ErrStreamIdleTimeout) {
+		return n, fmt.Errorf("%w: no data for %s", ErrStreamIdleTimeout, b.timeout)
+	}
+
+	if n > 0 && b.timer != nil {
+		b.timer.Reset(b.timeout)
+	}
+
+	return n, err
+}
+
+// Close stops the idle timer and closes the body.
+func (b *idleBody) Close() error {
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+
+	b.cancel(nil)
+
+	return b.body.Close()
+}
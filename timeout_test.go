@@ -0,0 +1,100 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithRequestTimeout tests failing requests whose server never sends the response headers.
+func TestWithRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	defer server.Close()
+	defer close(release)
+
+	client, _ := talkative.New(server.URL, talkative.WithRequestTimeout(50*time.Millisecond))
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+
+	assert.Nil(t, done)
+	assert.ErrorIs(t, err, talkative.ErrRequestTimeout)
+}
+
+// TestWithRequestTimeoutStreaming tests the request timeout doesn't bound slow streams.
+func TestWithRequestTimeoutStreaming(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		for _, done := range []bool{false, true} {
+			time.Sleep(40 * time.Millisecond)
+			json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "x"}, Done: done})
+			w.(http.Flusher).Flush()
+		}
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL, talkative.WithRequestTimeout(50*time.Millisecond))
+	chunks := 0
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {
+		assert.NoError(t, err)
+		chunks++
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+
+	assert.NoError(t, err)
+	<-done
+	assert.Equal(t, 2, chunks)
+}
+
+// TestWithStreamIdleTimeout tests aborting streams which stop producing chunks.
+func TestWithStreamIdleTimeout(t *testing.T) {
+	release := make(chan struct{})
+
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "Hello"}})
+		w.(http.Flusher).Flush()
+
+		<-release
+	})
+	defer server.Close()
+	defer close(release)
+
+	client, _ := talkative.New(server.URL, talkative.WithStreamIdleTimeout(50*time.Millisecond))
+
+	var (
+		content   string
+		streamErr error
+	)
+
+	done, err := client.Chat("", func(cr *talkative.ChatResponse, err error) {
+		if err != nil {
+			streamErr = err
+
+			return
+		}
+
+		content += cr.Message.Content
+	}, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+
+	assert.NoError(t, err)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream was not aborted")
+	}
+
+	assert.Equal(t, "Hello", content)
+	assert.ErrorIs(t, streamErr, talkative.ErrStreamIdleTimeout)
+}
@@ -0,0 +1,90 @@
+package talkative
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TranscribeOptions holds the optional parameters of a transcription.
+type TranscribeOptions struct {
+	Language string // The language of the audio as an ISO 639-1 code, detected by the model when empty.
+	Prompt   string // Text guiding the style or vocabulary of the transcription.
+}
+
+// TranscriptionRequest is the request body sent to the transcription endpoint.
+type TranscriptionRequest struct {
+	Model    string `json:"model"`              // The audio-capable model to use.
+	Audio    string `json:"audio"`              // The base64 encoded audio.
+	MIME     string `json:"mime_type"`          // The MIME type of the audio, i.e: "audio/wav".
+	Language string `json:"language,omitempty"` // The language of the audio.
+	Prompt   string `json:"prompt,omitempty"`   // Text guiding the transcription.
+}
+
+// TranscriptionResponse is the response of the transcription endpoint.
+type TranscriptionResponse struct {
+	Model    string  `json:"model"`    // The model used for the transcription.
+	Text     string  `json:"text"`     // The transcribed text.
+	Language string  `json:"language"` // The language of the audio, as detected by the model.
+	Duration float64 `json:"duration"` // The duration of the audio in seconds.
+}
+
+// Invoke sends the request as JSON to the endpoint and decodes the JSON response into response.
+//
+// It is the generic transport of the client, the endpoint is either a path relative to the base URL
+// of the client, i.e: "/api/transcribe", or a full URL. It allows using endpoints exposed by gateways
+// in front of Ollama which the client doesn't support natively.
+//
+// The response is decoded with the decode options of the client, see WithDecodeOptions, followed by opts.
+func (c *Client) Invoke(ctx context.Context, endpoint string, request, response any, opts ...DecodeOption) error {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = c.base + endpoint
+	}
+
+	res, err := c.post(ctx, endpoint, request)
+
+	if err != nil {
+		return wrapError("invoke", endpoint, "", "", err)
+	}
+
+	if response == nil {
+		return res.Body.Close()
+	}
+
+	return wrapError("invoke", endpoint, "", "", decodeInto(res, response, append(slices.Clip(c.decode), opts...)...))
+}
+
+// Transcribe transcribes the audio attachment with an audio-capable model.
+//
+// Ollama doesn't transcribe audio itself, this targets gateways exposing audio-capable models through an
+// Ollama-compatible API at /api/transcribe. The request is sent to the "transcribe" endpoint of the client.
+func (c *Client) Transcribe(ctx context.Context, model string, audio *Attachment, opts *TranscribeOptions) (*TranscriptionResponse, error) {
+	if audio == nil || audio.Kind() != ATTACHMENT_AUDIO {
+		return nil, fmt.Errorf("%w: an audio attachment is required", ErrAttachment)
+	}
+
+	if model == "" {
+		model = c.DefaultModel()
+	}
+
+	if opts == nil {
+		opts = &TranscribeOptions{}
+	}
+
+	request := TranscriptionRequest{
+		Model:    c.resolve(model),
+		Audio:    audio.Base64(),
+		MIME:     audio.MIME,
+		Language: opts.Language,
+		Prompt:   opts.Prompt,
+	}
+
+	response := &TranscriptionResponse{}
+
+	if err := c.Invoke(ctx, c.urls["transcribe"], request, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
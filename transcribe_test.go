@@ -0,0 +1,71 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTranscribe tests transcribing an audio attachment through a gateway.
+func TestTranscribe(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.TranscriptionRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		assert.Equal(t, "/api/transcribe", r.URL.Path)
+		assert.Equal(t, "whisper", request.Model)
+		assert.Equal(t, "audio/mpeg", request.MIME)
+		assert.Equal(t, "fr", request.Language)
+
+		json.NewEncoder(w).Encode(talkative.TranscriptionResponse{Model: request.Model, Text: "Bonjour", Language: "fr", Duration: 1.5})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	audio := talkative.NewAttachment("hello.mp3", []byte("ID3\x03\x00"))
+
+	transcription, err := client.Transcribe(context.Background(), "whisper", audio, &talkative.TranscribeOptions{Language: "fr"})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "Bonjour", transcription.Text)
+		assert.Equal(t, 1.5, transcription.Duration)
+	}
+
+	_, err = client.Transcribe(context.Background(), "whisper", talkative.NewAttachment("notes.txt", []byte("text")), nil)
+	{
+		assert.ErrorIs(t, err, talkative.ErrAttachment)
+	}
+}
+
+// TestInvoke tests sending requests to arbitrary endpoints.
+func TestInvoke(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/gateway/echo", r.URL.Path)
+
+		var request map[string]string
+
+		json.NewDecoder(r.Body).Decode(&request)
+		json.NewEncoder(w).Encode(request)
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	response := map[string]string{}
+
+	err := client.Invoke(context.Background(), "/gateway/echo", map[string]string{"ping": "pong"}, &response)
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"ping": "pong"}, response)
+	}
+
+	err = client.Invoke(context.Background(), server.URL+"/gateway/echo", map[string]string{}, nil)
+	{
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,109 @@
+package talkative
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// DEFAULT_VALIDATE_ATTEMPTS is the number of attempts made by ChatValidated when none are configured.
+const DEFAULT_VALIDATE_ATTEMPTS = 3
+
+// ErrValidation is returned when no response passed the validation within the allowed attempts.
+var ErrValidation = newError("response did not pass validation")
+
+// Validator checks a complete response, returning an error describing why it is not acceptable.
+type Validator func(response string) error
+
+// MatchRegexp returns a Validator accepting the responses matching the regular expression.
+func MatchRegexp(re *regexp.Regexp) Validator {
+	return func(response string) error {
+		if !re.MatchString(response) {
+			return fmt.Errorf("answer must match the pattern %s", re)
+		}
+
+		return nil
+	}
+}
+
+// Predicate returns a Validator accepting the responses for which fn returns true,
+// reporting reason for the rejected ones.
+func Predicate(fn func(response string) bool, reason string) Validator {
+	return func(response string) error {
+		if !fn(response) {
+			return errors.New(reason)
+		}
+
+		return nil
+	}
+}
+
+// Attempt records a single response and the validation error it produced, if any.
+type Attempt struct {
+	Response string // The complete response of the model.
+	Err      error  // The validation error, or the error of the request, nil for the accepted response.
+}
+
+// ValidateOptions configures ChatValidated.
+type ValidateOptions struct {
+	Attempts int // Maximum number of attempts, defaults to DEFAULT_VALIDATE_ATTEMPTS.
+
+	// Feedback builds the message sent back to the model after a rejected response. (Optional)
+	Feedback func(response string, err error) string
+}
+
+// ChatValidated sends the chat and validates the complete response, re-prompting the model with
+// feedback until a response is accepted or the attempts are exhausted.
+//
+// The accepted response is returned along with every attempt made, for debugging. When no response
+// is accepted, the returned error wraps ErrValidation and the last validation error.
+func (c *Client) ChatValidated(model string, validate Validator, params *ChatParams, opts *ValidateOptions, msgs ...ChatMessage) (string, []Attempt, error) {
+	if validate == nil {
+		return "", nil, fmt.Errorf("%w: validator is required", ErrValidation)
+	}
+
+	if opts == nil {
+		opts = &ValidateOptions{}
+	}
+
+	attempts := opts.Attempts
+
+	if attempts <= 0 {
+		attempts = DEFAULT_VALIDATE_ATTEMPTS
+	}
+
+	feedback := opts.Feedback
+
+	if feedback == nil {
+		feedback = func(response string, err error) string {
+			return fmt.Sprintf("Your previous answer was rejected: %v. Please answer again.", err)
+		}
+	}
+
+	history := append([]ChatMessage{}, msgs...)
+	tries := []Attempt{}
+
+	for i := 0; i < attempts; i++ {
+		response, err := c.chatText(model, params, history...)
+
+		if err != nil {
+			tries = append(tries, Attempt{Response: response, Err: err})
+
+			return "", tries, err
+		}
+
+		err = validate(response)
+		tries = append(tries, Attempt{Response: response, Err: err})
+
+		if err == nil {
+			return response, tries, nil
+		}
+
+		history = append(history,
+			ChatMessage{Role: ASSISTANT, Content: response},
+			ChatMessage{Role: USER, Content: feedback(response, err)},
+		)
+	}
+
+	return "", tries, fmt.Errorf("%w after %d attempts: %w", ErrValidation, len(tries), tries[len(tries)-1].Err)
+}
@@ -0,0 +1,61 @@
+package talkative_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChatValidated tests re-prompting the model until the response matches the pattern.
+func TestChatValidated(t *testing.T) {
+	replies := []string{"It is probably 42", "42"}
+	requests := []talkative.ChatRequest{}
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.ChatRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		requests = append(requests, request)
+
+		json.NewEncoder(w).Encode(talkative.ChatResponse{Message: talkative.ChatMessage{Content: replies[len(requests)-1]}, Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	validate := talkative.MatchRegexp(regexp.MustCompile(`^\d+$`))
+
+	response, attempts, err := client.ChatValidated("", validate, nil, nil, talkative.ChatMessage{Role: talkative.USER, Content: "Answer with a number"})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "42", response)
+		assert.Len(t, attempts, 2)
+		assert.Error(t, attempts[0].Err)
+		assert.NoError(t, attempts[1].Err)
+	}
+
+	assert.Len(t, requests[1].Messages, 3)
+	assert.Equal(t, talkative.ASSISTANT, requests[1].Messages[1].Role)
+	assert.Contains(t, requests[1].Messages[2].Content, "rejected")
+}
+
+// TestChatValidatedExhausted tests the error returned when no response is accepted.
+func TestChatValidatedExhausted(t *testing.T) {
+	server := streamServer(talkative.ChatResponse{Message: talkative.ChatMessage{Content: "no"}, Done: true})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	validate := talkative.Predicate(func(response string) bool { return strings.HasPrefix(response, "yes") }, "answer must start with yes")
+
+	_, attempts, err := client.ChatValidated("", validate, nil, &talkative.ValidateOptions{Attempts: 2}, talkative.ChatMessage{Role: talkative.USER, Content: "Agree?"})
+	{
+		assert.ErrorIs(t, err, talkative.ErrValidation)
+		assert.Contains(t, err.Error(), "answer must start with yes")
+		assert.Len(t, attempts, 2)
+	}
+}
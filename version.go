@@ -0,0 +1,126 @@
+package talkative
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrVersion is returned when a version string cannot be parsed.
+var ErrVersion = newError("invalid version")
+
+// Version is a semantic version of the Ollama server, i.e: "0.3.12" or "0.4.0-rc1".
+type Version struct {
+	Major      int    // The major version.
+	Minor      int    // The minor version.
+	Patch      int    // The patch version.
+	PreRelease string // The pre-release suffix without its dash, i.e: "rc1".
+	Raw        string // The version as reported by the server.
+}
+
+// ParseVersion parses a semantic version, with or without a leading "v".
+//
+// Missing minor and patch versions default to 0 and build metadata is ignored.
+func ParseVersion(s string) (Version, error) {
+	version := Version{Raw: s}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, version.PreRelease = s[:i], s[i+1:]
+	}
+
+	parts := strings.Split(s, ".")
+
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("%w: %q", ErrVersion, version.Raw)
+	}
+
+	numbers := []*int{&version.Major, &version.Minor, &version.Patch}
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("%w: %q", ErrVersion, version.Raw)
+		}
+
+		*numbers[i] = n
+	}
+
+	return version, nil
+}
+
+// Compare returns -1, 0 or +1 when v is respectively lower than, equal to or greater than other.
+//
+// Pre-releases are lower than their release and compared lexically with each other.
+func (v Version) Compare(other Version) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	switch {
+	case v.PreRelease == other.PreRelease:
+		return 0
+	case v.PreRelease == "":
+		return 1
+	case other.PreRelease == "":
+		return -1
+	default:
+		return strings.Compare(v.PreRelease, other.PreRelease)
+	}
+}
+
+// AtLeast reports whether v is greater than or equal to the version, i.e: v.AtLeast("0.3.0").
+//
+// It returns false when the version cannot be parsed.
+func (v Version) AtLeast(version string) bool {
+	other, err := ParseVersion(version)
+
+	return err == nil && v.Compare(other) >= 0
+}
+
+// String returns the version in its canonical form.
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+
+	if v.PreRelease != "" {
+		s += "-" + v.PreRelease
+	}
+
+	return s
+}
+
+// Version returns the version of the server.
+func (c *Client) Version() (Version, error) {
+	return c.VersionContext(context.Background())
+}
+
+// VersionContext is identical to Version(), except that the request is bound to the context.
+func (c *Client) VersionContext(ctx context.Context) (Version, error) {
+	res, err := c.get(ctx, c.urls["version"])
+
+	if err != nil {
+		return Version{}, wrapError("version", c.urls["version"], "", "", err)
+	}
+
+	response, err := decode[struct {
+		Version string `json:"version"`
+	}](res)
+
+	if err != nil {
+		return Version{}, wrapError("version", c.urls["version"], "", "", err)
+	}
+
+	return ParseVersion(response.Version)
+}
@@ -0,0 +1,55 @@
+package talkative_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseVersion tests parsing and comparing versions.
+func TestParseVersion(t *testing.T) {
+	version, err := talkative.ParseVersion("v0.3.12-rc1+build5")
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, 0, version.Major)
+		assert.Equal(t, 3, version.Minor)
+		assert.Equal(t, 12, version.Patch)
+		assert.Equal(t, "rc1", version.PreRelease)
+		assert.Equal(t, "0.3.12-rc1", version.String())
+	}
+
+	assert.True(t, version.AtLeast("0.3.0"))
+	assert.False(t, version.AtLeast("0.3.12"))
+	assert.False(t, version.AtLeast("0.4"))
+	assert.False(t, version.AtLeast("invalid"))
+
+	release, _ := talkative.ParseVersion("0.3.12")
+	assert.Equal(t, 1, release.Compare(version))
+	assert.Equal(t, 0, release.Compare(release))
+
+	_, err = talkative.ParseVersion("1.2.3.4")
+	{
+		assert.ErrorIs(t, err, talkative.ErrVersion)
+	}
+}
+
+// TestVersion tests reading the version of the server.
+func TestVersion(t *testing.T) {
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/version", r.URL.Path)
+
+		w.Write([]byte(`{"version": "0.1.32"}`))
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+
+	version, err := client.Version()
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, talkative.Version{Minor: 1, Patch: 32, Raw: "0.1.32"}, version)
+	}
+}
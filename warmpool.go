@@ -0,0 +1,277 @@
+package talkative
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WarmPoolOptions configures a WarmPool.
+type WarmPoolOptions struct {
+	Interval  time.Duration // Interval between two keep-alive pings, defaults to 4 minutes.
+	KeepAlive string        // How long a pinged model stays loaded, defaults to 5m. Keep it longer than Interval.
+
+	// Schedule reports whether models should be kept warm at the given time, i.e: during business hours only.
+	// Models are kept warm at all times when nil. (Optional)
+	Schedule func(now time.Time) bool
+
+	// OnStatus is invoked with the outcome of every keep-alive ping and eviction. (Optional)
+	OnStatus func(status WarmStatus)
+
+	// IdleTimeout enables eviction: models which have not been requested through the pool's clients for
+	// longer than IdleTimeout are unloaded (keep_alive=0), and pool models are no longer kept warm until
+	// they are requested again. Eviction applies to every model observed on the clients, not only to the
+	// models of the pool, which allows juggling more models than fit in VRAM. Disabled when zero. (Optional)
+	IdleTimeout time.Duration
+}
+
+// WarmStatus reports the warmth of a model on an endpoint.
+type WarmStatus struct {
+	Endpoint string        // The base URL of the endpoint.
+	Model    string        // The model.
+	Warm     bool          // Whether the last ping succeeded.
+	Evicted  bool          // Whether the model has been unloaded for being idle.
+	PingedAt time.Time     // Time of the last ping.
+	Latency  time.Duration // Duration of the last ping, which includes loading a cold model.
+	Err      error         // The error of the last ping.
+}
+
+// WarmPool keeps a set of models loaded on one or more endpoints with periodic keep-alive pings,
+// avoiding cold starts for interactive traffic.
+//
+// A ping is an empty completion request, which makes Ollama load the model (when needed) and reset
+// its keep-alive timer without generating anything.
+type WarmPool struct {
+	clients []*Client
+	models  []string
+	opts    WarmPoolOptions
+
+	mu       sync.RWMutex
+	status   map[[2]string]WarmStatus
+	lastUsed map[[2]string]time.Time // Time every model was last requested on every endpoint.
+	evicted  map[[2]string]bool      // Models unloaded for being idle, per endpoint.
+}
+
+// NewWarmPool creates a new WarmPool keeping the models warm on the endpoints of the given clients.
+func NewWarmPool(models []string, opts WarmPoolOptions, clients ...*Client) *WarmPool {
+	if opts.Interval <= 0 {
+		opts.Interval = 4 * time.Minute
+	}
+
+	if opts.KeepAlive == "" {
+		opts.KeepAlive = "5m"
+	}
+
+	p := &WarmPool{
+		clients:  clients,
+		models:   models,
+		opts:     opts,
+		status:   map[[2]string]WarmStatus{},
+		lastUsed: map[[2]string]time.Time{},
+		evicted:  map[[2]string]bool{},
+	}
+
+	for _, client := range clients {
+		for _, model := range models {
+			p.lastUsed[[2]string{client.base, model}] = time.Now()
+		}
+	}
+
+	return p
+}
+
+// Start warms the models immediately and keeps pinging them every interval until the context is cancelled.
+//
+// When eviction is enabled, the usage of models is observed on the clients and idle models are unloaded every interval.
+func (p *WarmPool) Start(ctx context.Context) {
+	if p.opts.IdleTimeout > 0 {
+		for _, client := range p.clients {
+			unsubscribe := p.observe(client)
+
+			context.AfterFunc(ctx, unsubscribe)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(p.opts.Interval)
+		defer ticker.Stop()
+
+		for {
+			if p.opts.Schedule == nil || p.opts.Schedule(time.Now()) {
+				p.Warm(ctx)
+			}
+
+			p.Evict(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Warm pings every model on every endpoint once, concurrently, and waits for the pings to finish.
+// The pings in flight are aborted once the context is done.
+func (p *WarmPool) Warm(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, client := range p.clients {
+		for _, model := range p.models {
+			if p.isEvicted(client.base, model) {
+				continue
+			}
+
+			wg.Add(1)
+
+			go func(client *Client, model string) {
+				defer wg.Done()
+
+				if ctx.Err() == nil {
+					p.record(client.ping(ctx, model, p.opts.KeepAlive))
+				}
+			}(client, model)
+		}
+	}
+
+	wg.Wait()
+}
+
+// Evict unloads the models which have been idle for longer than the idle timeout.
+//
+// It does nothing when eviction is disabled.
+func (p *WarmPool) Evict(ctx context.Context) {
+	if p.opts.IdleTimeout <= 0 {
+		return
+	}
+
+	idle := [][2]string{}
+
+	p.mu.Lock()
+
+	for key, used := range p.lastUsed {
+		if !p.evicted[key] && time.Since(used) > p.opts.IdleTimeout {
+			p.evicted[key] = true
+			idle = append(idle, key)
+		}
+	}
+
+	p.mu.Unlock()
+
+	for _, key := range idle {
+		for _, client := range p.clients {
+			if client.base != key[0] || ctx.Err() != nil {
+				continue
+			}
+
+			status := client.ping(ctx, key[1], "0")
+			status.Warm = false
+			status.Evicted = status.Err == nil
+
+			if status.Err != nil {
+				p.mu.Lock()
+				p.evicted[key] = false
+				p.mu.Unlock()
+			}
+
+			p.record(status)
+
+			break
+		}
+	}
+}
+
+// Touch records a request of the model on the endpoint, bringing an evicted model back into the pool.
+//
+// Usage is recorded automatically for the pool's clients once started, Touch covers requests issued
+// through other clients.
+func (p *WarmPool) Touch(endpoint, model string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := [2]string{endpoint, model}
+	p.lastUsed[key] = time.Now()
+	p.evicted[key] = false
+}
+
+// Status returns the warmth of every model on every endpoint, ordered by endpoint and model.
+func (p *WarmPool) Status() []WarmStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	statuses := make([]WarmStatus, 0, len(p.status))
+
+	for _, status := range p.status {
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Endpoint != statuses[j].Endpoint {
+			return statuses[i].Endpoint < statuses[j].Endpoint
+		}
+
+		return statuses[i].Model < statuses[j].Model
+	})
+
+	return statuses
+}
+
+// observe records the usage of models requested through the client.
+func (p *WarmPool) observe(client *Client) (unsubscribe func()) {
+	return client.Subscribe(func(e Event) {
+		p.Touch(client.base, e.Model)
+	}, EVENT_REQUEST_STARTED)
+}
+
+// isEvicted reports whether the model has been evicted from the endpoint.
+func (p *WarmPool) isEvicted(endpoint, model string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.evicted[[2]string{endpoint, model}]
+}
+
+// record stores the outcome of a ping and reports it.
+func (p *WarmPool) record(status WarmStatus) {
+	p.mu.Lock()
+	p.status[[2]string{status.Endpoint, status.Model}] = status
+	p.mu.Unlock()
+
+	if p.opts.OnStatus != nil {
+		p.opts.OnStatus(status)
+	}
+}
+
+// ping sends an empty completion request loading the model for the keep-alive duration.
+//
+// A keep-alive of "0" unloads the model instead. Pings bypass the request hooks and lifecycle events,
+// so they are not mistaken for user traffic. The ping is aborted once the context is done.
+func (c *Client) ping(ctx context.Context, model, keepAlive string) WarmStatus {
+	status := WarmStatus{
+		Endpoint: c.base,
+		Model:    model,
+		PingedAt: time.Now(),
+	}
+
+	res, err := c.post(ctx, c.urls["completion"], CompletionRequest{
+		Model:            model,
+		CompletionParams: &CompletionParams{KeepAlive: keepAlive},
+	})
+
+	if err != nil {
+		status.Err = err
+	} else {
+		StreamResponse(res.Body, func(cr *CompletionResponse, err error) {
+			if err != nil {
+				status.Err = err
+			}
+		})
+	}
+
+	status.Latency = time.Since(status.PingedAt)
+	status.Warm = status.Err == nil
+
+	return status
+}
@@ -0,0 +1,167 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWarmPool tests that the models are pinged with the keep-alive on every endpoint.
+func TestWarmPool(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		pinged []string
+	)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.CompletionRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		if request.Model == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		assert.Equal(t, "", request.Prompt)
+		assert.Equal(t, "30m", request.KeepAlive)
+
+		mu.Lock()
+		pinged = append(pinged, request.Model)
+		mu.Unlock()
+
+		json.NewEncoder(w).Encode(talkative.CompletionResponse{Model: request.Model, Done: true})
+	})
+
+	first, second := mockServer(handler), mockServer(handler)
+	defer first.Close()
+	defer second.Close()
+
+	client1, _ := talkative.New(first.URL)
+	client2, _ := talkative.New(second.URL)
+
+	pool := talkative.NewWarmPool([]string{"llama3", "missing"}, talkative.WarmPoolOptions{KeepAlive: "30m"}, client1, client2)
+	pool.Warm(context.Background())
+
+	assert.ElementsMatch(t, []string{"llama3", "llama3"}, pinged)
+
+	statuses := pool.Status()
+
+	assert.Len(t, statuses, 4)
+
+	for _, status := range statuses {
+		assert.Equal(t, status.Model == "llama3", status.Warm)
+	}
+}
+
+// TestWarmPoolEviction tests that idle models are unloaded and brought back once requested again.
+func TestWarmPoolEviction(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request talkative.CompletionRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+
+		keepAlive := ""
+
+		if request.CompletionParams != nil {
+			keepAlive = request.KeepAlive
+		}
+
+		mu.Lock()
+		requests = append(requests, request.Model+"@"+keepAlive)
+		mu.Unlock()
+
+		json.NewEncoder(w).Encode(talkative.CompletionResponse{Model: request.Model, Done: true})
+	}))
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	pool := talkative.NewWarmPool([]string{"llama3"}, talkative.WarmPoolOptions{
+		Interval:    time.Hour,
+		IdleTimeout: 50 * time.Millisecond,
+	}, client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pool.Start(ctx)
+
+	// a model outside of the pool, observed through the client
+	done, _ := client.Completion("mistral", func(cr *talkative.CompletionResponse, err error) {}, &talkative.CompletionMessage{Prompt: "Hi"})
+	<-done
+
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	requests = nil
+	mu.Unlock()
+
+	pool.Evict(ctx)
+	pool.Warm(ctx)
+
+	assert.ElementsMatch(t, []string{"llama3@0", "mistral@0"}, requests)
+
+	for _, status := range pool.Status() {
+		assert.True(t, status.Evicted)
+	}
+
+	requests = nil
+
+	pool.Touch(server.URL, "llama3")
+	pool.Warm(ctx)
+	pool.Evict(ctx)
+
+	assert.Equal(t, []string{"llama3@5m"}, requests)
+}
+
+// TestWarmPoolCancel tests that cancelling a warm-up aborts the pings in flight.
+func TestWarmPoolCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := mockServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, _ := talkative.New(server.URL)
+	pool := talkative.NewWarmPool([]string{"llama3"}, talkative.WarmPoolOptions{}, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	warmed := make(chan struct{})
+
+	go func() {
+		pool.Warm(ctx)
+		close(warmed)
+	}()
+
+	select {
+	case <-warmed:
+	case <-time.After(time.Second):
+		t.Fatal("warm-up not aborted by the context")
+	}
+
+	statuses := pool.Status()
+	{
+		assert.Len(t, statuses, 1)
+		assert.False(t, statuses[0].Warm)
+		assert.ErrorIs(t, statuses[0].Err, context.DeadlineExceeded)
+	}
+}
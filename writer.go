@@ -0,0 +1,90 @@
+package talkative
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ChatTo streams the content of the chat straight into the writer and returns the final response, whose
+// message holds the aggregated content along with the metrics of the chat.
+//
+// Writers implementing http.Flusher, i.e: an http.ResponseWriter, are flushed after every chunk so the content
+// reaches the client as it is generated. Writers implementing FlushHinter receive flush hints instead, see
+// NewFlushWriter. A failing write aborts the request and its error is returned.
+// When the stream fails, the partial response is returned along with the error.
+func (c *Client) ChatTo(ctx context.Context, w io.Writer, model string, params *ChatParams, msgs ...ChatMessage) (*ChatResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses, errs := c.ChatStream(ctx, model, params, msgs...)
+
+	return writeTo(w, cancel, responses, errs, func(response *ChatResponse) *string {
+		return &response.Message.Content
+	})
+}
+
+// CompletionTo streams the response of the completion straight into the writer and returns the final response,
+// which holds the aggregated response along with the metrics of the completion, like ChatTo() does.
+func (c *Client) CompletionTo(ctx context.Context, w io.Writer, model string, msg *CompletionMessage) (*CompletionResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses, errs := c.CompletionStream(ctx, model, msg)
+
+	return writeTo(w, cancel, responses, errs, func(response *CompletionResponse) *string {
+		return &response.Response
+	})
+}
+
+// writeTo writes the content of the responses into the writer and returns the last response holding the
+// aggregated content, the content function pointing at the content of a response.
+func writeTo[T any](w io.Writer, cancel context.CancelFunc, responses <-chan T, errs <-chan error, content func(*T) *string) (*T, error) {
+	var (
+		final T
+		sb    strings.Builder
+		err   error
+	)
+
+	flusher, _ := w.(http.Flusher)
+	hinter, _ := w.(FlushHinter)
+
+	for response := range responses {
+		if err != nil {
+			continue // Drain the responses of the aborted request.
+		}
+
+		delta := *content(&response)
+
+		if _, err = io.WriteString(w, delta); err != nil {
+			cancel()
+
+			continue
+		}
+
+		sb.WriteString(delta)
+		final = response
+
+		switch {
+		case hinter != nil:
+			if err = hinter.FlushHint(HintOf(sb.String())); err != nil {
+				cancel()
+			}
+		case flusher != nil:
+			flusher.Flush()
+		}
+	}
+
+	if streamErr := <-errs; err == nil {
+		err = streamErr
+	}
+
+	if err == nil && hinter != nil {
+		err = hinter.FlushHint(FLUSH_END)
+	}
+
+	*content(&final) = sb.String()
+
+	return &final, err
+}
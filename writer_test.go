@@ -0,0 +1,94 @@
+package talkative_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rifaideen/talkative"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingWriter accepts a number of writes before failing.
+type failingWriter struct {
+	written strings.Builder
+	writes  int
+}
+
+var errWrite = errors.New("broken pipe")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.writes == 0 {
+		return 0, errWrite
+	}
+
+	w.writes--
+
+	return w.written.Write(p)
+}
+
+// TestChatTo tests streaming the content of a chat into a writer and returning the aggregated response.
+func TestChatTo(t *testing.T) {
+	server := streamServer(
+		talkative.ChatResponse{Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: "Hello"}},
+		talkative.ChatResponse{Message: talkative.ChatMessage{Role: talkative.ASSISTANT, Content: "!"}, Done: true, ChatMetrics: talkative.ChatMetrics{EvalCount: 2}},
+	)
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	recorder := httptest.NewRecorder()
+
+	response, err := client.ChatTo(context.Background(), recorder, "", nil, talkative.ChatMessage{Role: talkative.USER, Content: "Hi"})
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello!", recorder.Body.String())
+		assert.True(t, recorder.Flushed)
+		assert.Equal(t, "Hello!", response.Message.Content)
+		assert.True(t, response.Done)
+		assert.Equal(t, 2, response.EvalCount)
+	}
+
+	_, err = client.ChatTo(context.Background(), recorder, "", nil)
+	assert.ErrorIs(t, err, talkative.ErrMessage)
+}
+
+// TestCompletionTo tests aborting the completion when the writer fails.
+func TestCompletionTo(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		encoder := json.NewEncoder(w)
+
+		for i := 0; i < 100; i++ {
+			encoder.Encode(talkative.CompletionResponse{Response: "x"})
+		}
+
+		encoder.Encode(talkative.CompletionResponse{Done: true})
+	})
+	defer server.Close()
+
+	client, _ := talkative.New(server.URL)
+	message := &talkative.CompletionMessage{Prompt: "Hi"}
+
+	var sb strings.Builder
+
+	response, err := client.CompletionTo(context.Background(), &sb, "", message)
+	{
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Repeat("x", 100), sb.String())
+		assert.Equal(t, strings.Repeat("x", 100), response.Response)
+		assert.True(t, response.Done)
+	}
+
+	w := &failingWriter{writes: 3}
+
+	response, err = client.CompletionTo(context.Background(), w, "", message)
+	{
+		assert.ErrorIs(t, err, errWrite)
+		assert.Equal(t, "xxx", w.written.String())
+		assert.Equal(t, "xxx", response.Response)
+	}
+}